@@ -0,0 +1,167 @@
+// Package logger provides structured, ECS-field-compatible logging with
+// pluggable sinks (stdout JSON, rotating file, HTTP batch) and automatic
+// caller capture, so both this tree's own operational messages and the
+// synthetic log entries it generates share one schema downstream tooling
+// can index uniformly.
+package logger
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Level is a logger severity, ordered low to high so a Logger can filter
+// by a configured minimum.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelNotice
+	LevelWarning
+	LevelError
+	LevelCritical
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelNotice:
+		return "notice"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	case LevelCritical:
+		return "critical"
+	default:
+		return "info"
+	}
+}
+
+// ParseLevel maps a config string (case-insensitive) to a Level,
+// defaulting to LevelInfo for an empty or unrecognized value.
+func ParseLevel(s string) Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug
+	case "notice":
+		return LevelNotice
+	case "warning", "warn":
+		return LevelWarning
+	case "error":
+		return LevelError
+	case "critical", "crit":
+		return LevelCritical
+	default:
+		return LevelInfo
+	}
+}
+
+// Record is one structured log event. Field names follow the Elastic
+// Common Schema so these records slot into the same indices/dashboards as
+// other ECS-emitting services without a translation layer.
+type Record struct {
+	Timestamp     time.Time         `json:"@timestamp"`
+	Level         string            `json:"log.level"`
+	EventCategory string            `json:"event.category"`
+	Message       string            `json:"message"`
+	HostName      string            `json:"host.name"`
+	ServiceName   string            `json:"service.name"`
+	File          string            `json:"log.origin.file.name,omitempty"`
+	Line          int               `json:"log.origin.file.line,omitempty"`
+	Fields        map[string]string `json:"fields,omitempty"`
+}
+
+// Sink is where a Logger delivers Records. Mirrors logtail.Sink's shape
+// (one interface, several backends) for the same pluggable-backend reason,
+// just over structured Records instead of raw tailed bytes. Write must be
+// safe for concurrent use.
+type Sink interface {
+	Write(Record) error
+	Close() error
+}
+
+// Logger emits Records to a Sink, attaching caller info and dropping
+// anything below its configured minimum Level.
+type Logger struct {
+	sink        Sink
+	minLevel    Level
+	serviceName string
+	hostName    string
+}
+
+// New builds a Logger delivering to sink. serviceName is stamped onto
+// every Record as service.name.
+func New(sink Sink, minLevel Level, serviceName string) *Logger {
+	host, _ := os.Hostname()
+	return &Logger{sink: sink, minLevel: minLevel, serviceName: serviceName, hostName: host}
+}
+
+// log resolves the caller two frames up (the exported Debug/Info/etc.
+// method, then its caller) so Record.File/Line always point at the
+// call site that triggered the log, not at this package.
+func (l *Logger) log(level Level, category, message string, fields map[string]string) {
+	if l == nil || level < l.minLevel {
+		return
+	}
+
+	_, file, line, ok := runtime.Caller(2)
+	if !ok {
+		file, line = "", 0
+	}
+
+	record := Record{
+		Timestamp:     time.Now().UTC(),
+		Level:         level.String(),
+		EventCategory: category,
+		Message:       message,
+		HostName:      l.hostName,
+		ServiceName:   l.serviceName,
+		File:          file,
+		Line:          line,
+		Fields:        fields,
+	}
+
+	if err := l.sink.Write(record); err != nil {
+		fmt.Fprintf(os.Stderr, "logger: sink write failed: %v\n", err)
+	}
+}
+
+func (l *Logger) Debug(category, message string, fields map[string]string) {
+	l.log(LevelDebug, category, message, fields)
+}
+
+func (l *Logger) Info(category, message string, fields map[string]string) {
+	l.log(LevelInfo, category, message, fields)
+}
+
+func (l *Logger) Notice(category, message string, fields map[string]string) {
+	l.log(LevelNotice, category, message, fields)
+}
+
+func (l *Logger) Warning(category, message string, fields map[string]string) {
+	l.log(LevelWarning, category, message, fields)
+}
+
+func (l *Logger) Error(category, message string, fields map[string]string) {
+	l.log(LevelError, category, message, fields)
+}
+
+func (l *Logger) Critical(category, message string, fields map[string]string) {
+	l.log(LevelCritical, category, message, fields)
+}
+
+// Close releases the underlying sink (flushing any buffered records).
+func (l *Logger) Close() error {
+	if l == nil || l.sink == nil {
+		return nil
+	}
+	return l.sink.Close()
+}
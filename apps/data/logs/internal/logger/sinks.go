@@ -0,0 +1,258 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StdoutSink writes each Record as one line of JSON to stdout.
+type StdoutSink struct {
+	mu sync.Mutex
+}
+
+func NewStdoutSink() *StdoutSink { return &StdoutSink{} }
+
+func (s *StdoutSink) Write(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = fmt.Println(string(data))
+	return err
+}
+
+func (s *StdoutSink) Close() error { return nil }
+
+// RotatingFileSink appends Records as newline-delimited JSON to a file
+// under dir, rotating to a new timestamped file once the current one
+// exceeds maxSizeMB (when maxSizeMB > 0) and pruning files under dir
+// older than retention on every rotation. This reproduces the package's
+// previous rotateLogFile/cleanupOldLogs behavior on top of the Sink
+// interface.
+type RotatingFileSink struct {
+	mu        sync.Mutex
+	dir       string
+	maxSizeMB int
+	retention time.Duration
+
+	file        *os.File
+	currentSize int64
+}
+
+func NewRotatingFileSink(dir string, maxSizeMB int, retention time.Duration) (*RotatingFileSink, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create log directory %s: %w", dir, err)
+	}
+
+	s := &RotatingFileSink{dir: dir, maxSizeMB: maxSizeMB, retention: retention}
+	if err := s.rotate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *RotatingFileSink) Write(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log record: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxSizeMB > 0 && s.currentSize+int64(len(data)) > int64(s.maxSizeMB)*1024*1024 {
+		if err := s.rotateLocked(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(data)
+	s.currentSize += int64(n)
+	return err
+}
+
+// Rotate closes the current file and opens a fresh one, for callers that
+// rotate on a schedule (e.g. a daily RotationInterval) rather than by
+// size.
+func (s *RotatingFileSink) Rotate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rotateLocked()
+}
+
+func (s *RotatingFileSink) rotate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rotateLocked()
+}
+
+func (s *RotatingFileSink) rotateLocked() error {
+	if s.file != nil {
+		s.file.Close()
+	}
+
+	if err := s.cleanupOldLocked(); err != nil {
+		fmt.Fprintf(os.Stderr, "logger: failed to clean up old log files in %s: %v\n", s.dir, err)
+	}
+
+	path := filepath.Join(s.dir, fmt.Sprintf("log-%s.jsonl", time.Now().Format("20060102-150405")))
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create log file %s: %w", path, err)
+	}
+
+	s.file = file
+	s.currentSize = 0
+	return nil
+}
+
+func (s *RotatingFileSink) cleanupOldLocked() error {
+	if s.retention <= 0 {
+		return nil
+	}
+	cutoff := time.Now().Add(-s.retention)
+
+	return filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if strings.HasSuffix(info.Name(), ".jsonl") && info.ModTime().Before(cutoff) {
+			return os.Remove(path)
+		}
+		return nil
+	})
+}
+
+func (s *RotatingFileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file != nil {
+		return s.file.Close()
+	}
+	return nil
+}
+
+// HTTPBatchSink buffers Records and POSTs them as a JSON array to a
+// configurable endpoint (Splunk HEC, Elastic's _bulk, Loki's push API)
+// once BatchSize is reached or FlushInterval elapses, whichever comes
+// first.
+type HTTPBatchSink struct {
+	url           string
+	authHeader    string
+	client        *http.Client
+	batchSize     int
+	flushInterval time.Duration
+
+	mu     sync.Mutex
+	buffer []Record
+	done   chan struct{}
+}
+
+// NewHTTPBatchSink starts the sink's background flush loop immediately;
+// callers must Close it to stop the loop and flush any remaining
+// buffered records.
+func NewHTTPBatchSink(url, authHeader string, batchSize int, flushInterval time.Duration) *HTTPBatchSink {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if flushInterval <= 0 {
+		flushInterval = 5 * time.Second
+	}
+
+	s := &HTTPBatchSink{
+		url:           url,
+		authHeader:    authHeader,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		done:          make(chan struct{}),
+	}
+
+	go s.flushLoop()
+	return s
+}
+
+func (s *HTTPBatchSink) Write(record Record) error {
+	s.mu.Lock()
+	s.buffer = append(s.buffer, record)
+	shouldFlush := len(s.buffer) >= s.batchSize
+	s.mu.Unlock()
+
+	if shouldFlush {
+		return s.flush()
+	}
+	return nil
+}
+
+func (s *HTTPBatchSink) flushLoop() {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.flush(); err != nil {
+				fmt.Fprintf(os.Stderr, "logger: HTTP batch flush failed: %v\n", err)
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *HTTPBatchSink) flush() error {
+	s.mu.Lock()
+	if len(s.buffer) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	batch := s.buffer
+	s.buffer = nil
+	s.mu.Unlock()
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log batch: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create log batch request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.authHeader != "" {
+		req.Header.Set("Authorization", s.authHeader)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send log batch to %s: %w", s.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("log batch endpoint %s returned status %d: %s", s.url, resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+func (s *HTTPBatchSink) Close() error {
+	close(s.done)
+	return s.flush()
+}
@@ -1,13 +1,14 @@
 package logs
 
 import (
-	"encoding/json"
 	"fmt"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/data/logs/internal/logger"
 )
 
 var LogTypes = map[string][]string{
@@ -64,23 +65,38 @@ var (
 	currentRand = rand.New(rand.NewSource(time.Now().UnixNano()))
 )
 
-type LogEntry struct {
-	Timestamp   string            `json:"timestamp"`
-	Level       string            `json:"level"`
-	Type        string            `json:"type"`
-	Message     string            `json:"message"`
-	Source      string            `json:"source,omitempty"`
-	Details     map[string]string `json:"details,omitempty"`
-}
-
 type LogConfig struct {
-	OutputDir          string
-	RotationInterval   time.Duration
-	LogsPerMinute      map[string]int
-	IncludeErrors      bool
-	RotateFilesBySize  bool
-	MaxFileSizeMB      int
-	RetentionPeriod    time.Duration
+	OutputDir         string
+	RotationInterval  time.Duration
+	LogsPerMinute     map[string]int
+	IncludeErrors     bool
+	RotateFilesBySize bool
+	MaxFileSizeMB     int
+	RetentionPeriod   time.Duration
+
+	// Level sets the minimum severity this package's loggers emit: "debug",
+	// "info" (default), "notice", "warning", "error", or "critical".
+	Level string
+
+	// ServiceName is stamped onto every log record's service.name field.
+	ServiceName string
+
+	// SinkType selects where synthetic log entries go: "file" (default,
+	// preserves the original per-log-type rotating-file behavior),
+	// "stdout", or "http". The generator's own operational messages always
+	// go to stdout regardless of SinkType, since they describe this
+	// process rather than the synthetic data stream.
+	SinkType string
+
+	// HTTPSinkURL, HTTPSinkAuthHeader, HTTPSinkBatchSize, and
+	// HTTPSinkFlushInterval configure the HTTP sink when SinkType is
+	// "http" — pointed at a Splunk HEC, Elastic bulk, or Loki push
+	// endpoint. HTTPSinkAuthHeader is sent verbatim as the Authorization
+	// header (e.g. "Splunk <token>" for Splunk HEC).
+	HTTPSinkURL           string
+	HTTPSinkAuthHeader    string
+	HTTPSinkBatchSize     int
+	HTTPSinkFlushInterval time.Duration
 }
 
 func DefaultLogConfig() LogConfig {
@@ -96,7 +112,10 @@ func DefaultLogConfig() LogConfig {
 		IncludeErrors:     true,
 		RotateFilesBySize: false,
 		MaxFileSizeMB:     100,
-		RetentionPeriod:   30 * 24 * time.Hour, 
+		RetentionPeriod:   30 * 24 * time.Hour,
+		Level:             "info",
+		ServiceName:       "log-generator",
+		SinkType:          "file",
 	}
 }
 
@@ -139,189 +158,208 @@ func extractMessageContent(message string) string {
 	return message
 }
 
-func generateLogEntry(logType string, timestamp time.Time, includeErrors bool) string {
+// logAtLevel dispatches to l's method matching the synthetic entry's
+// extracted severity ("INFO", "NOTICE", "WARNING", "ERROR", "CRITICAL"),
+// defaulting to Info for anything else.
+func logAtLevel(l *logger.Logger, level, category, message string, fields map[string]string) {
+	switch level {
+	case "NOTICE":
+		l.Notice(category, message, fields)
+	case "WARNING":
+		l.Warning(category, message, fields)
+	case "ERROR":
+		l.Error(category, message, fields)
+	case "CRITICAL":
+		l.Critical(category, message, fields)
+	default:
+		l.Info(category, message, fields)
+	}
+}
+
+// generateLogEntry synthesizes one log line for logType and emits it
+// through l as a structured logger.Record, rather than hand-marshaling a
+// LogEntry: synthetic entries and this package's own operational messages
+// now share the same ECS-compatible schema end to end.
+func generateLogEntry(l *logger.Logger, logType string, timestamp time.Time, includeErrors bool) {
 	levelIndex := 0
-	
+
 	if includeErrors {
 		roll := currentRand.Float64()
 		if roll < 0.15 {
-			levelIndex = 1 
+			levelIndex = 1
 		} else if roll < 0.40 {
-			levelIndex = 2 
+			levelIndex = 2
 		} else {
 			if len(LogTypes[logType]) > 4 {
 				levelIndex = []int{0, 3, 4}[currentRand.Intn(3)]
 			}
 		}
 	}
-	
+
 	template := LogTypes[logType][levelIndex%len(LogTypes[logType])]
-	
+
 	timestampStr := timestamp.Format("2006-01-02T15:04:05.000Z")
-	
+
 	values := map[string]string{
-		"timestamp":        timestampStr,
-		"service":          getRandomElement(Services),
-		"action":           getRandomElement(Actions),
-		"error_code":       getRandomElement(ErrorCodes),
-		"error_message":    getRandomElement(ErrorMessages),
-		"percentage":       fmt.Sprintf("%d", currentRand.Intn(20)+80),
-		"interface":        getRandomElement(Interfaces),
-		"status":           getRandomElement(Statuses),
-		"hostname":         getRandomElement(Hostnames),
-		"port":             fmt.Sprintf("%d", currentRand.Intn(64511)+1024),
-		"ntp_server":       getRandomElement(NtpServers),
-		"node":             getRandomElement(Nodes),
-		"node1":            getRandomElement(Nodes),
-		"node2":            getRandomElement(Nodes),
-		"resource":         getRandomElement(Resources),
+		"timestamp":         timestampStr,
+		"service":           getRandomElement(Services),
+		"action":            getRandomElement(Actions),
+		"error_code":        getRandomElement(ErrorCodes),
+		"error_message":     getRandomElement(ErrorMessages),
+		"percentage":        fmt.Sprintf("%d", currentRand.Intn(20)+80),
+		"interface":         getRandomElement(Interfaces),
+		"status":            getRandomElement(Statuses),
+		"hostname":          getRandomElement(Hostnames),
+		"port":              fmt.Sprintf("%d", currentRand.Intn(64511)+1024),
+		"ntp_server":        getRandomElement(NtpServers),
+		"node":              getRandomElement(Nodes),
+		"node1":             getRandomElement(Nodes),
+		"node2":             getRandomElement(Nodes),
+		"resource":          getRandomElement(Resources),
 		"partition_details": fmt.Sprintf("nodes %s,%s isolated", getRandomElement(Nodes), getRandomElement(Nodes)),
-		"job_id":           fmt.Sprintf("%d", currentRand.Intn(9000)+1000),
-		"user":             getRandomElement(Users),
-		"reservation_id":   fmt.Sprintf("res_%d", currentRand.Intn(900)+100),
-		"time_period":      fmt.Sprintf("%d hours", currentRand.Intn(24)+1),
+		"job_id":            fmt.Sprintf("%d", currentRand.Intn(9000)+1000),
+		"user":              getRandomElement(Users),
+		"reservation_id":    fmt.Sprintf("res_%d", currentRand.Intn(900)+100),
+		"time_period":       fmt.Sprintf("%d hours", currentRand.Intn(24)+1),
 	}
-	
+
 	logMessage := replacePlaceholders(template, values)
 	level := extractLogLevel(logMessage)
 	messageContent := extractMessageContent(logMessage)
-	
-	details := make(map[string]string)
+
+	fields := make(map[string]string)
 	for key, value := range values {
-		if key != "timestamp" && strings.Contains(template, "{"+key+"}") {
-			details[key] = value
+		if strings.Contains(template, "{"+key+"}") {
+			fields[key] = value
 		}
 	}
-	
-	logEntry := LogEntry{
-		Timestamp: timestampStr,
-		Level:     level,
-		Type:      logType,
-		Message:   messageContent,
-		Source:    fmt.Sprintf("%s-generator", logType),
-		Details:   details,
-	}
-	
-	jsonBytes, err := json.MarshalIndent(logEntry, "  ", "  ")
-	if err != nil {
-		return fmt.Sprintf(`  {
-    "timestamp": "%s",
-    "level": "ERROR",
-    "type": "%s",
-    "message": "Failed to marshal log entry",
-    "source": "log-generator"
-  }`, timestampStr, logType)
-	}
-	
-	return string(jsonBytes)
+	fields["synthetic_timestamp"] = timestampStr
+
+	logAtLevel(l, level, logType, messageContent, fields)
 }
 
-func appendLogToFile(logEntry string, filename string) error {
-	_, err := os.Stat(filename)
-	fileExists := !os.IsNotExist(err)
-	
-	if !fileExists {
-		dir := filepath.Dir(filename)
-		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-			return err
-		}
-		
-		file, err := os.Create(filename)
-		if err != nil {
-			return err
+// newSink builds the Sink a logType's synthetic Logger writes to, per
+// config.SinkType.
+func newSink(config LogConfig, logType string) (logger.Sink, error) {
+	switch config.SinkType {
+	case "", "file":
+		maxSizeMB := 0
+		if config.RotateFilesBySize {
+			maxSizeMB = config.MaxFileSizeMB
 		}
-		defer file.Close()
-		
-		_, err = file.WriteString("[\n" + logEntry + "\n]")
-		return err
+		dir := filepath.Join(config.OutputDir, logType)
+		return logger.NewRotatingFileSink(dir, maxSizeMB, config.RetentionPeriod)
+	case "stdout":
+		return logger.NewStdoutSink(), nil
+	case "http":
+		return logger.NewHTTPBatchSink(config.HTTPSinkURL, config.HTTPSinkAuthHeader, config.HTTPSinkBatchSize, config.HTTPSinkFlushInterval), nil
+	default:
+		return nil, fmt.Errorf("unknown log sink type %q", config.SinkType)
 	}
-	
-	content, err := os.ReadFile(filename)
-	if err != nil {
-		return err
+}
+
+func serviceName(config LogConfig) string {
+	if config.ServiceName != "" {
+		return config.ServiceName
 	}
-	
-	contentStr := string(content)
-	hasEntries := len(contentStr) > 2 && !strings.EqualFold(strings.TrimSpace(contentStr), "[]")
-	
-	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_TRUNC, 0644)
+	return "log-generator"
+}
+
+// newSyntheticLogger builds the Logger logType's synthetic entries are
+// emitted through, returning its underlying RotatingFileSink too (non-nil
+// only for SinkType "file") so StartContinuousLogging's scheduled
+// rotation ticker can call Rotate on it directly.
+func newSyntheticLogger(config LogConfig, logType string) (*logger.Logger, *logger.RotatingFileSink, error) {
+	sink, err := newSink(config, logType)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	defer file.Close()
-	
-	if hasEntries {
-		if strings.HasSuffix(contentStr, "\n]") {
-			contentStr = contentStr[:len(contentStr)-2]
-		} else if strings.HasSuffix(contentStr, "]") {
-			contentStr = contentStr[:len(contentStr)-1] 
-		}
-		
-		_, err = file.WriteString(contentStr + ",\n" + logEntry + "\n]")
-	} else {
-		_, err = file.WriteString("[\n" + logEntry + "\n]")
-	}
-	
-	return err
+
+	fileSink, _ := sink.(*logger.RotatingFileSink)
+	return logger.New(sink, logger.ParseLevel(config.Level), serviceName(config)), fileSink, nil
 }
 
-func rotateLogFile(logType string, config LogConfig) (string, error) {
-	timestamp := time.Now().Format("20060102-150405")
-	baseDir := filepath.Join(config.OutputDir, logType)
-	
-	if err := os.MkdirAll(baseDir, os.ModePerm); err != nil {
-		return "", err
+// rotateLogFile rotates logType's current file to a fresh one, logging
+// the event through opLogger. A no-op when logType's sink isn't
+// file-based (only RotatingFileSink supports rotation).
+func rotateLogFile(opLogger *logger.Logger, fileSinks map[string]*logger.RotatingFileSink, logType string) error {
+	sink, ok := fileSinks[logType]
+	if !ok {
+		return nil
+	}
+
+	if err := sink.Rotate(); err != nil {
+		return err
 	}
-	
-	newFilename := filepath.Join(baseDir, fmt.Sprintf("%s-%s.json", logType, timestamp))
-	return newFilename, nil
+
+	opLogger.Notice("generator", fmt.Sprintf("Rotated log file for %s", logType), map[string]string{"log_type": logType})
+	return nil
 }
 
-func cleanupOldLogs(config LogConfig) error {
+// cleanupOldLogs removes synthetic log files under config.OutputDir older
+// than config.RetentionPeriod. RotatingFileSink already prunes on every
+// rotation; this catches files left behind between scheduled rotations,
+// e.g. a log type whose LogsPerMinute is high enough it never idles long
+// enough to hit a size-based rotation on its own.
+func cleanupOldLogs(opLogger *logger.Logger, config LogConfig) error {
+	if config.RetentionPeriod <= 0 {
+		return nil
+	}
 	cutoffTime := time.Now().Add(-config.RetentionPeriod)
-	
+
 	return filepath.Walk(config.OutputDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		
 		if info.IsDir() {
 			return nil
 		}
-		
-		if strings.HasSuffix(info.Name(), ".json") && info.ModTime().Before(cutoffTime) {
-			return os.Remove(path)
+		if strings.HasSuffix(info.Name(), ".jsonl") && info.ModTime().Before(cutoffTime) {
+			if rmErr := os.Remove(path); rmErr != nil {
+				return rmErr
+			}
+			opLogger.Debug("generator", fmt.Sprintf("Removed expired log file %s", path), map[string]string{"path": path})
 		}
-		
 		return nil
 	})
 }
 
 func StartContinuousLogging(config LogConfig) {
-	fmt.Printf("Starting continuous JSON log generation in %s\n", config.OutputDir)
-	
+	opLogger := logger.New(logger.NewStdoutSink(), logger.ParseLevel(config.Level), serviceName(config))
+
+	opLogger.Info("generator", fmt.Sprintf("Starting continuous log generation in %s", config.OutputDir), nil)
+
 	if err := os.MkdirAll(config.OutputDir, os.ModePerm); err != nil {
-		fmt.Printf("Error creating output directory: %v\n", err)
+		opLogger.Error("generator", "Error creating output directory", map[string]string{"error": err.Error()})
 		return
 	}
-	
-	currentLogFiles := make(map[string]string)
+
+	synthLoggers := make(map[string]*logger.Logger)
+	fileSinks := make(map[string]*logger.RotatingFileSink)
+
 	for logType := range config.LogsPerMinute {
-		newFile, err := rotateLogFile(logType, config)
+		l, fileSink, err := newSyntheticLogger(config, logType)
 		if err != nil {
-			fmt.Printf("Error creating JSON log file for %s: %v\n", logType, err)
+			opLogger.Error("generator", fmt.Sprintf("Error creating log sink for %s", logType), map[string]string{"error": err.Error(), "log_type": logType})
 			return
 		}
-		currentLogFiles[logType] = newFile
-		fmt.Printf("Created initial JSON log file for %s: %s\n", logType, newFile)
+		synthLoggers[logType] = l
+		if fileSink != nil {
+			fileSinks[logType] = fileSink
+		}
 	}
-	
+	defer func() {
+		for _, l := range synthLoggers {
+			l.Close()
+		}
+	}()
+
 	rotationTicker := time.NewTicker(config.RotationInterval)
 	defer rotationTicker.Stop()
-	
+
 	cleanupTicker := time.NewTicker(24 * time.Hour)
 	defer cleanupTicker.Stop()
-	
+
 	logIntervals := make(map[string]time.Duration)
 	for logType, logsPerMinute := range config.LogsPerMinute {
 		if logsPerMinute <= 0 {
@@ -330,82 +368,51 @@ func StartContinuousLogging(config LogConfig) {
 		interval := time.Minute / time.Duration(logsPerMinute)
 		logIntervals[logType] = interval
 	}
-	
+
 	logTickers := make(map[string]*time.Ticker)
 	for logType, interval := range logIntervals {
 		logTickers[logType] = time.NewTicker(interval)
 	}
-	
+
 	defer func() {
 		for _, ticker := range logTickers {
 			ticker.Stop()
 		}
 	}()
-	
-	fileSizes := make(map[string]int64)
-	
 
 	done := make(chan bool)
-	
+
 	for logType, ticker := range logTickers {
 		go func(lt string, tk *time.Ticker) {
+			l := synthLoggers[lt]
 			for {
 				select {
 				case <-tk.C:
-					logEntry := generateLogEntry(lt, time.Now(), config.IncludeErrors)
-					
-				
-					err := appendLogToFile(logEntry, currentLogFiles[lt])
-					if err != nil {
-						fmt.Printf("Error writing to JSON log file for %s: %v\n", lt, err)
-						continue
-					}
-					
-					if config.RotateFilesBySize {
-						fileSizes[lt] += int64(len(logEntry) + 1) 
-						
-						if fileSizes[lt] > int64(config.MaxFileSizeMB)*1024*1024 {
-							newFile, err := rotateLogFile(lt, config)
-							if err != nil {
-								fmt.Printf("Error rotating JSON log file for %s: %v\n", lt, err)
-								continue
-							}
-							
-							fmt.Printf("Rotated JSON log file for %s due to size: %s\n", lt, newFile)
-							currentLogFiles[lt] = newFile
-							fileSizes[lt] = 0
-						}
-					}
-					
+					generateLogEntry(l, lt, time.Now(), config.IncludeErrors)
+
 				case <-done:
 					return
 				}
 			}
 		}(logType, ticker)
 	}
-	
+
 	for {
 		select {
 		case <-rotationTicker.C:
 			for logType := range config.LogsPerMinute {
-				newFile, err := rotateLogFile(logType, config)
-				if err != nil {
-					fmt.Printf("Error rotating JSON log file for %s: %v\n", logType, err)
-					continue
+				if err := rotateLogFile(opLogger, fileSinks, logType); err != nil {
+					opLogger.Error("generator", fmt.Sprintf("Error rotating log file for %s", logType), map[string]string{"error": err.Error(), "log_type": logType})
 				}
-				
-				fmt.Printf("Rotated JSON log file for %s at scheduled interval: %s\n", logType, newFile)
-				currentLogFiles[logType] = newFile
-				fileSizes[logType] = 0
 			}
-			
+
 		case <-cleanupTicker.C:
-			if err := cleanupOldLogs(config); err != nil {
-				fmt.Printf("Error cleaning up old JSON log files: %v\n", err)
+			if err := cleanupOldLogs(opLogger, config); err != nil {
+				opLogger.Error("generator", "Error cleaning up old log files", map[string]string{"error": err.Error()})
 			} else {
-				fmt.Println("Cleaned up old JSON log files")
+				opLogger.Info("generator", "Cleaned up old log files", nil)
 			}
-			
+
 		case <-done:
 			return
 		}
@@ -414,11 +421,10 @@ func StartContinuousLogging(config LogConfig) {
 
 func Logs() {
 	config := DefaultLogConfig()
-	
-	
+
 	StartContinuousLogging(config)
 }
 
 func LogsWithConfig(config LogConfig) {
 	StartContinuousLogging(config)
-}
\ No newline at end of file
+}
@@ -0,0 +1,121 @@
+package logtail
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// FileState is the checkpointed progress for one tailed file, keyed by
+// inode+device rather than path so a rename-based log rotation doesn't look
+// like a truncation back to offset 0.
+type FileState struct {
+	Path           string    `json:"path"`
+	Inode          uint64    `json:"inode"`
+	Device         uint64    `json:"device"`
+	Offset         int64     `json:"offset"`
+	LastUploadTime time.Time `json:"last_upload_time"`
+}
+
+func fileKey(inode, device uint64) string {
+	return fmt.Sprintf("%d:%d", device, inode)
+}
+
+// fileIdentity reads the inode and device of a file from its os.FileInfo,
+// which on Linux means digging into the underlying syscall.Stat_t. ok is
+// false on platforms where that isn't available.
+func fileIdentity(info os.FileInfo) (inode, device uint64, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return stat.Ino, uint64(stat.Dev), true
+}
+
+// Checkpoint persists FileState for every tailed file as a single JSON
+// document, written atomically (write-tmp+rename) so a crash mid-write
+// can't leave a half-written checkpoint that would cause re-shipping or
+// data loss on restart.
+type Checkpoint struct {
+	mu    sync.Mutex
+	path  string
+	files map[string]*FileState
+}
+
+func NewCheckpoint(path string) (*Checkpoint, error) {
+	c := &Checkpoint{path: path, files: make(map[string]*FileState)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &c.files); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	return c, nil
+}
+
+func (c *Checkpoint) Get(inode, device uint64) (*FileState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, ok := c.files[fileKey(inode, device)]
+	return state, ok
+}
+
+func (c *Checkpoint) Set(state *FileState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.files[fileKey(state.Inode, state.Device)] = state
+}
+
+func (c *Checkpoint) Delete(inode, device uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.files, fileKey(inode, device))
+}
+
+// Save writes the checkpoint atomically: it serializes to a temp file in
+// the same directory, then renames over the real path, so a reader never
+// observes a partially-written checkpoint.
+func (c *Checkpoint) Save() error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c.files, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	dir := filepath.Dir(c.path)
+	tmp, err := os.CreateTemp(dir, ".checkpoint-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp checkpoint file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write temp checkpoint file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temp checkpoint file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, c.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temp checkpoint file into place: %w", err)
+	}
+	return nil
+}
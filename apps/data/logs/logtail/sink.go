@@ -0,0 +1,241 @@
+package logtail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"google.golang.org/api/option"
+)
+
+// SinkMeta carries the provenance of a batch of tailed log bytes so a Sink
+// doesn't need to reparse the tailer's checkpoint state to route or name
+// what it writes.
+type SinkMeta struct {
+	Path    string
+	LogType string
+}
+
+// Sink is where a Tailer delivers batches of tailed log bytes. A Sink may be
+// shared across multiple tracked files, so Write must be safe for
+// concurrent use.
+type Sink interface {
+	Write(ctx context.Context, lines []byte, meta SinkMeta) error
+	Close() error
+}
+
+// GCSSink uploads each batch as its own object under a bucket prefix, named
+// "<prefix><log type>/<upload time>.log". It adapts the original
+// log_shiper.go upload to the Sink interface, adding the per-log-type
+// path segment.
+type GCSSink struct {
+	client *storage.Client
+	bucket string
+	prefix string
+}
+
+func NewGCSSink(ctx context.Context, bucket, prefix, credentialsFile string) (*GCSSink, error) {
+	var client *storage.Client
+	var err error
+
+	if credentialsFile != "" {
+		client, err = storage.NewClient(ctx, option.WithCredentialsFile(credentialsFile))
+	} else {
+		client, err = storage.NewClient(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %w", err)
+	}
+
+	return &GCSSink{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+func (s *GCSSink) Write(ctx context.Context, lines []byte, meta SinkMeta) error {
+	objectName := fmt.Sprintf("%s%s/%s.log", s.prefix, meta.LogType, time.Now().Format("20060102-150405.000000000"))
+
+	writer := s.client.Bucket(s.bucket).Object(objectName).NewWriter(ctx)
+	if _, err := writer.Write(lines); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write to gs://%s/%s: %w", s.bucket, objectName, err)
+	}
+	return writer.Close()
+}
+
+func (s *GCSSink) Close() error {
+	return s.client.Close()
+}
+
+// S3Sink uploads each batch as its own object, and works against both AWS S3
+// and S3-compatible stores (MinIO) by accepting an optional custom endpoint.
+type S3Sink struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+func NewS3Sink(ctx context.Context, bucket, prefix, endpoint string) (*S3Sink, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &S3Sink{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+func (s *S3Sink) Write(ctx context.Context, lines []byte, meta SinkMeta) error {
+	key := fmt.Sprintf("%s%s/%s.log", s.prefix, meta.LogType, time.Now().Format("20060102-150405.000000000"))
+
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   newReadSeeker(lines),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write to s3://%s/%s: %w", s.bucket, key, err)
+	}
+	return nil
+}
+
+func (s *S3Sink) Close() error {
+	return nil
+}
+
+// LocalSink appends batches to a file per log type under a local directory.
+// Useful for development and for environments without object storage.
+type LocalSink struct {
+	outputDir string
+}
+
+func NewLocalSink(outputDir string) (*LocalSink, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create local sink directory: %w", err)
+	}
+	return &LocalSink{outputDir: outputDir}, nil
+}
+
+func (s *LocalSink) Write(_ context.Context, lines []byte, meta SinkMeta) error {
+	path := filepath.Join(s.outputDir, meta.LogType+".log")
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open local sink file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(lines)
+	return err
+}
+
+func (s *LocalSink) Close() error {
+	return nil
+}
+
+// KafkaSink produces each line in a batch as its own Kafka message, so
+// tailed logs flow into the same topics the embedding pipeline consumes
+// from.
+type KafkaSink struct {
+	producer *kafka.Producer
+	topic    string
+}
+
+func NewKafkaSink(bootstrapServers, topic string) (*KafkaSink, error) {
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers": bootstrapServers,
+		"acks":              "all",
+		"retries":           "3",
+		"batch.size":        "16384",
+		"linger.ms":         "1",
+		"compression.type":  "snappy",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
+	}
+
+	return &KafkaSink{producer: producer, topic: topic}, nil
+}
+
+func (s *KafkaSink) Write(_ context.Context, lines []byte, meta SinkMeta) error {
+	for _, line := range splitLines(lines) {
+		if len(line) == 0 {
+			continue
+		}
+		if err := s.producer.Produce(&kafka.Message{
+			TopicPartition: kafka.TopicPartition{Topic: &s.topic, Partition: kafka.PartitionAny},
+			Key:            []byte(meta.LogType),
+			Value:          line,
+		}, nil); err != nil {
+			return fmt.Errorf("failed to produce tailed log line to %s: %w", s.topic, err)
+		}
+	}
+	return nil
+}
+
+func (s *KafkaSink) Close() error {
+	s.producer.Flush(30 * 1000)
+	s.producer.Close()
+	return nil
+}
+
+func splitLines(data []byte) [][]byte {
+	var lines [][]byte
+	start := 0
+	for i, b := range data {
+		if b == '\n' {
+			lines = append(lines, data[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(data) {
+		lines = append(lines, data[start:])
+	}
+	return lines
+}
+
+// newReadSeeker adapts a byte slice to the io.ReadSeeker the S3 PutObject
+// API requires for request signing.
+func newReadSeeker(b []byte) io.ReadSeeker {
+	return &byteReadSeeker{data: b}
+}
+
+type byteReadSeeker struct {
+	data []byte
+	pos  int64
+}
+
+func (r *byteReadSeeker) Read(p []byte) (int, error) {
+	if r.pos >= int64(len(r.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data[r.pos:])
+	r.pos += int64(n)
+	return n, nil
+}
+
+func (r *byteReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	var newPos int64
+	switch whence {
+	case io.SeekStart:
+		newPos = offset
+	case io.SeekCurrent:
+		newPos = r.pos + offset
+	case io.SeekEnd:
+		newPos = int64(len(r.data)) + offset
+	}
+	r.pos = newPos
+	return r.pos, nil
+}
@@ -0,0 +1,430 @@
+// Package logtail is a resumable, checkpointed log tailer. It watches one or
+// more file globs, ships newly-appended bytes to a pluggable Sink, and
+// survives restarts and log rotation without re-shipping or losing data:
+// progress is checkpointed per inode+device rather than per path, so a
+// rotation via rename+create is recognized instead of looking like the file
+// was truncated back to zero.
+package logtail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Config configures a Tailer.
+type Config struct {
+	// Globs are file patterns (as accepted by filepath.Glob) to tail. They
+	// are re-evaluated on PollInterval so newly-created files matching a
+	// glob get picked up without a restart.
+	Globs []string
+
+	// LogType classifies a path for SinkMeta, e.g. for routing to a Kafka
+	// topic or an object storage prefix. The default classifies by the
+	// immediate parent directory name.
+	LogType func(path string) string
+
+	Sink           Sink
+	CheckpointPath string
+
+	PollInterval    time.Duration
+	BatchSize       int
+	ForceFlushEvery time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.PollInterval <= 0 {
+		c.PollInterval = 10 * time.Second
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 5 * 1024 * 1024
+	}
+	if c.ForceFlushEvery <= 0 {
+		c.ForceFlushEvery = time.Minute
+	}
+	if c.LogType == nil {
+		c.LogType = func(path string) string { return filepath.Base(filepath.Dir(path)) }
+	}
+	return c
+}
+
+// trackedFile is one currently-open file being tailed.
+type trackedFile struct {
+	path    string
+	file    *os.File
+	inode   uint64
+	device  uint64
+	offset  int64
+	buffer  bytes.Buffer
+	logType string
+}
+
+// Tailer watches Config.Globs and ships newly-appended bytes to Config.Sink.
+type Tailer struct {
+	config     Config
+	checkpoint *Checkpoint
+	watcher    *fsnotify.Watcher // nil if fsnotify couldn't be initialized; falls back to polling
+
+	mu    sync.Mutex
+	files map[string]*trackedFile // keyed by path
+}
+
+func New(config Config) (*Tailer, error) {
+	config = config.withDefaults()
+
+	if config.Sink == nil {
+		return nil, fmt.Errorf("logtail: Sink is required")
+	}
+	if config.CheckpointPath == "" {
+		return nil, fmt.Errorf("logtail: CheckpointPath is required")
+	}
+
+	checkpoint, err := NewCheckpoint(config.CheckpointPath)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Tailer{
+		config:     config,
+		checkpoint: checkpoint,
+		files:      make(map[string]*trackedFile),
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("logtail: fsnotify unavailable, falling back to polling: %v", err)
+	} else {
+		t.watcher = watcher
+	}
+
+	return t, nil
+}
+
+// Run discovers files matching the configured globs and tails them until ctx
+// is canceled. It blocks until shutdown, which is expected to happen via ctx
+// cancellation so Run can drain and checkpoint cleanly.
+func (t *Tailer) Run(ctx context.Context) error {
+	if err := t.discover(); err != nil {
+		log.Printf("logtail: initial discovery error: %v", err)
+	}
+
+	pollTicker := time.NewTicker(t.config.PollInterval)
+	defer pollTicker.Stop()
+
+	forceFlush := time.NewTicker(t.config.ForceFlushEvery)
+	defer forceFlush.Stop()
+
+	var events <-chan fsnotify.Event
+	var errs <-chan error
+	if t.watcher != nil {
+		events = t.watcher.Events
+		errs = t.watcher.Errors
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return t.Close()
+
+		case <-pollTicker.C:
+			if err := t.discover(); err != nil {
+				log.Printf("logtail: discovery error: %v", err)
+			}
+			if err := t.pollAll(ctx); err != nil {
+				log.Printf("logtail: poll error: %v", err)
+			}
+
+		case <-forceFlush.C:
+			t.flushAll(ctx, true)
+
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				if err := t.pollPath(ctx, event.Name); err != nil {
+					log.Printf("logtail: error handling fsnotify event for %s: %v", event.Name, err)
+				}
+			}
+
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			log.Printf("logtail: fsnotify error: %v", err)
+		}
+	}
+}
+
+// discover expands the configured globs and starts tailing any file not
+// already tracked.
+func (t *Tailer) discover() error {
+	var firstErr error
+	for _, pattern := range t.config.Globs {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("invalid glob %q: %w", pattern, err)
+			}
+			continue
+		}
+
+		for _, path := range matches {
+			t.mu.Lock()
+			_, tracked := t.files[path]
+			t.mu.Unlock()
+			if tracked {
+				continue
+			}
+
+			if err := t.openTracked(path); err != nil {
+				log.Printf("logtail: failed to open %s: %v", path, err)
+				continue
+			}
+
+			if t.watcher != nil {
+				if err := t.watcher.Add(path); err != nil {
+					log.Printf("logtail: failed to watch %s, relying on polling: %v", path, err)
+				}
+			}
+		}
+	}
+	return firstErr
+}
+
+// openTracked opens path and seeks to its checkpointed offset if one exists
+// for this inode+device, or to the end of the file on first sight (so a
+// freshly-added tailer doesn't re-ship a file's entire history).
+func (t *Tailer) openTracked(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	inode, device, ok := fileIdentity(info)
+	if !ok {
+		f.Close()
+		return fmt.Errorf("could not determine inode identity for %s", path)
+	}
+
+	offset := info.Size()
+	if state, found := t.checkpoint.Get(inode, device); found {
+		offset = state.Offset
+		if offset > info.Size() {
+			log.Printf("logtail: checkpoint offset %d beyond current size %d for %s, restarting from 0", offset, info.Size(), path)
+			offset = 0
+		}
+	} else {
+		log.Printf("logtail: first time seeing %s, starting from end of file (%d bytes)", path, offset)
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+
+	tf := &trackedFile{
+		path:    path,
+		file:    f,
+		inode:   inode,
+		device:  device,
+		offset:  offset,
+		logType: t.config.LogType(path),
+	}
+
+	t.mu.Lock()
+	t.files[path] = tf
+	t.mu.Unlock()
+
+	t.checkpoint.Set(&FileState{Path: path, Inode: inode, Device: device, Offset: offset})
+	return nil
+}
+
+func (t *Tailer) pollAll(ctx context.Context) error {
+	t.mu.Lock()
+	paths := make([]string, 0, len(t.files))
+	for path := range t.files {
+		paths = append(paths, path)
+	}
+	t.mu.Unlock()
+
+	var firstErr error
+	for _, path := range paths {
+		if err := t.pollPath(ctx, path); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// pollPath reads whatever new bytes are available for path, detecting
+// rotation (the path now resolves to a different inode) and truncation
+// (same inode, but smaller than our recorded offset) along the way.
+func (t *Tailer) pollPath(ctx context.Context, path string) error {
+	t.mu.Lock()
+	tf, ok := t.files[path]
+	t.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	inode, device, ok := fileIdentity(info)
+	if ok && (inode != tf.inode || device != tf.device) {
+		return t.handleRotation(ctx, tf, path)
+	}
+
+	return t.readNewBytes(ctx, tf, info.Size())
+}
+
+// handleRotation flushes the remaining tail of the old file (the data
+// written to it after the last rotation check but before the rename/create
+// that replaced it) and then reopens path as a new tracked file.
+func (t *Tailer) handleRotation(ctx context.Context, tf *trackedFile, path string) error {
+	log.Printf("logtail: detected rotation of %s, flushing tail of old file", path)
+
+	if oldInfo, err := tf.file.Stat(); err == nil {
+		if err := t.readNewBytes(ctx, tf, oldInfo.Size()); err != nil {
+			log.Printf("logtail: failed to flush rotated file's tail for %s: %v", path, err)
+		}
+	}
+	t.flushBuffer(ctx, tf, true)
+	tf.file.Close()
+
+	t.mu.Lock()
+	delete(t.files, path)
+	t.mu.Unlock()
+
+	// The replacement file is new, so it is shipped from its start rather
+	// than from its end as openTracked does for a file seen the first time.
+	if info, err := os.Stat(path); err == nil {
+		if inode, device, ok := fileIdentity(info); ok {
+			if _, found := t.checkpoint.Get(inode, device); !found {
+				t.checkpoint.Set(&FileState{Path: path, Inode: inode, Device: device, Offset: 0})
+			}
+		}
+	}
+
+	return t.openTracked(path)
+}
+
+// readNewBytes reads from the tracked file's current offset up to size,
+// handling the truncation case (size shrank below our offset) by resetting
+// to the start of the file.
+func (t *Tailer) readNewBytes(ctx context.Context, tf *trackedFile, size int64) error {
+	if size < tf.offset {
+		log.Printf("logtail: %s appears truncated (recorded offset %d, current size %d); resetting", tf.path, tf.offset, size)
+		tf.offset = 0
+		if _, err := tf.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	if size == tf.offset {
+		return nil
+	}
+
+	chunk := make([]byte, size-tf.offset)
+	n, err := io.ReadFull(tf.file, chunk)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+
+	tf.buffer.Write(chunk[:n])
+	tf.offset += int64(n)
+
+	if tf.buffer.Len() >= t.config.BatchSize {
+		t.flushBuffer(ctx, tf, false)
+	}
+
+	// Checkpoint only what has been shipped, so bytes still buffered are
+	// read again after a restart instead of being skipped.
+	t.checkpoint.Set(&FileState{Path: tf.path, Inode: tf.inode, Device: tf.device, Offset: tf.offset - int64(tf.buffer.Len())})
+	return t.checkpoint.Save()
+}
+
+func (t *Tailer) flushAll(ctx context.Context, force bool) {
+	t.mu.Lock()
+	files := make([]*trackedFile, 0, len(t.files))
+	for _, tf := range t.files {
+		files = append(files, tf)
+	}
+	t.mu.Unlock()
+
+	for _, tf := range files {
+		t.flushBuffer(ctx, tf, force)
+	}
+}
+
+func (t *Tailer) flushBuffer(ctx context.Context, tf *trackedFile, force bool) {
+	if tf.buffer.Len() == 0 {
+		return
+	}
+	if !force && tf.buffer.Len() < t.config.BatchSize {
+		return
+	}
+
+	data := tf.buffer.Bytes()
+	if err := t.config.Sink.Write(ctx, data, SinkMeta{Path: tf.path, LogType: tf.logType}); err != nil {
+		log.Printf("logtail: failed to write batch for %s: %v", tf.path, err)
+		return
+	}
+
+	tf.buffer.Reset()
+	t.checkpoint.Set(&FileState{
+		Path:           tf.path,
+		Inode:          tf.inode,
+		Device:         tf.device,
+		Offset:         tf.offset,
+		LastUploadTime: time.Now(),
+	})
+	if err := t.checkpoint.Save(); err != nil {
+		log.Printf("logtail: failed to save checkpoint after flush for %s: %v", tf.path, err)
+	}
+}
+
+// Close flushes any buffered bytes, persists the checkpoint, and releases
+// open file handles and the sink. It's safe to call from Run's shutdown
+// path or directly by a caller that isn't using Run.
+func (t *Tailer) Close() error {
+	t.flushAll(context.Background(), true)
+
+	t.mu.Lock()
+	for path, tf := range t.files {
+		tf.file.Close()
+		delete(t.files, path)
+	}
+	t.mu.Unlock()
+
+	if t.watcher != nil {
+		t.watcher.Close()
+	}
+
+	if err := t.checkpoint.Save(); err != nil {
+		log.Printf("logtail: failed to save checkpoint on close: %v", err)
+	}
+
+	return t.config.Sink.Close()
+}
@@ -0,0 +1,106 @@
+// Command dlq-replay reads dead-lettered messages off "<topic>.dlq" and
+// re-injects them onto their source topic, optionally filtering by error
+// class so an operator can replay only the failures a fix actually
+// addresses.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/consumer"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+func main() {
+	configPath := flag.String("config", "config.json", "Path to configuration file")
+	sourceTopic := flag.String("topic", "", "Source topic whose <topic>.dlq should be replayed")
+	errorContains := flag.String("error-contains", "", "Only replay records whose failure.error contains this substring")
+	stage := flag.String("stage", "", "Only replay records whose failure.stage equals this value")
+	flag.Parse()
+
+	if *sourceTopic == "" {
+		log.Fatal("--topic is required")
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	dlqTopic := cfg.Kafka.DLQTopic
+	if dlqTopic == "" {
+		dlqTopic = *sourceTopic + ".dlq"
+	}
+
+	reader, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": cfg.Kafka.BootstrapServers,
+		"group.id":          cfg.Kafka.GroupID + "-dlq-replay",
+		"auto.offset.reset": "earliest",
+	})
+	if err != nil {
+		log.Fatalf("Failed to create DLQ reader: %v", err)
+	}
+	defer reader.Close()
+
+	if err := reader.SubscribeTopics([]string{dlqTopic}, nil); err != nil {
+		log.Fatalf("Failed to subscribe to %s: %v", dlqTopic, err)
+	}
+
+	writer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers": cfg.Kafka.BootstrapServers,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create replay producer: %v", err)
+	}
+	defer writer.Flush(5000)
+	defer writer.Close()
+
+	log.Printf("Replaying %s onto %s (error-contains=%q stage=%q)", dlqTopic, *sourceTopic, *errorContains, *stage)
+
+	replayed := 0
+	for {
+		msg, err := reader.ReadMessage(5 * time.Second)
+		if err != nil {
+			log.Printf("No more DLQ messages to replay: %v", err)
+			break
+		}
+
+		var record consumer.DLQRecord
+		if err := json.Unmarshal(msg.Value, &record); err != nil {
+			log.Printf("skipping unparseable DLQ record: %v", err)
+			continue
+		}
+
+		if *errorContains != "" && !strings.Contains(record.Failure.Error, *errorContains) {
+			continue
+		}
+		if *stage != "" && record.Failure.Stage != *stage {
+			continue
+		}
+
+		record.ReplayCount++
+
+		topic := *sourceTopic
+		if err := writer.Produce(&kafka.Message{
+			TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+			Value:          record.Message,
+			Headers: []kafka.Header{
+				{Key: "replay_count", Value: []byte(strconv.Itoa(record.ReplayCount))},
+			},
+		}, nil); err != nil {
+			log.Printf("failed to replay message onto %s: %v", topic, err)
+			continue
+		}
+
+		consumer.RecordReplay(topic)
+		replayed++
+	}
+
+	log.Printf("Replayed %d messages onto %s", replayed, *sourceTopic)
+}
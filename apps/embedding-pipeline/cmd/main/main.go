@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"log"
 	"os"
@@ -10,10 +11,18 @@ import (
 
 	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
 	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/consumer"
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/dedup"
 	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/embedders"
 	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/storage"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/google/uuid"
 )
 
+// batchPollTimeout bounds how long a batch waits to fill up before it's
+// embedded and stored with whatever it has. It doesn't affect the first
+// read of a batch, which blocks indefinitely for the next message.
+const batchPollTimeout = 2 * time.Second
+
 func main() {
 	configPath := flag.String("config", "config.json", "Path to configuration file")
 	flag.Parse()
@@ -23,31 +32,84 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	logConsumer, err := consumer.NewLogKafkaConsumer(cfg.Kafka)
+	watcher, err := config.NewWatcher(*configPath, "", cfg)
+	if err != nil {
+		log.Fatalf("Failed to start config watcher: %v", err)
+	}
+
+	embedder, err := embedders.NewEmbedder(cfg.Embedding)
+	if err != nil {
+		log.Fatalf("Failed to create embedder: %v", err)
+	}
+
+	logClient, err := storage.NewQdrantClient(cfg.Qdrant, "logs")
+	if err != nil {
+		log.Fatalf("Failed to create log Qdrant client: %v", err)
+	}
+
+	documentClient, err := storage.NewQdrantClient(cfg.Qdrant, "documents")
+	if err != nil {
+		log.Fatalf("Failed to create document Qdrant client: %v", err)
+	}
+
+	// logSink and documentSink are what the batch processors actually write
+	// to. Each starts out as just its Qdrant client; when archival is
+	// configured, a shared S3Sink is fanned into both via MultiSink so logs
+	// and documents land in cold storage too, keyed apart by their
+	// kafka_topic payload field.
+	var logSink storage.Sink = logClient
+	var documentSink storage.Sink = documentClient
+
+	if cfg.Archive.Enabled {
+		archiveSink, err := storage.NewS3Sink(cfg.Archive)
+		if err != nil {
+			log.Fatalf("Failed to create archive sink: %v", err)
+		}
+		logSink = storage.NewMultiSink(logClient, archiveSink)
+		documentSink = storage.NewMultiSink(documentClient, archiveSink)
+	}
+
+	// On revoke, flush whatever's buffered for the partitions this consumer
+	// is about to lose, so a rebalance never strands embedded-but-unstored
+	// work for the next owner to silently miss.
+	logConsumer, err := consumer.NewLogKafkaConsumer(cfg.Kafka, func(_ []kafka.TopicPartition) error {
+		return logSink.FlushBuffer()
+	})
 	if err != nil {
 		log.Fatalf("Failed to create log Kafka consumer: %v", err)
 	}
 	defer logConsumer.Close()
 
-	chunkConsumer, err := consumer.NewChunkKafkaConsumer(cfg.Kafka)
+	chunkConsumer, err := consumer.NewChunkKafkaConsumer(cfg.Kafka, func(_ []kafka.TopicPartition) error {
+		return documentSink.FlushBuffer()
+	})
 	if err != nil {
 		log.Fatalf("Failed to create chunk Kafka consumer: %v", err)
 	}
 	defer chunkConsumer.Close()
 
-	embedder, err := embedders.NewGeminiEmbedderWithConfig(cfg.Gemini)
-	if err != nil {
-		log.Fatalf("Failed to create Gemini embedder: %v", err)
+	if cfg.ObjectStore.Endpoint != "" {
+		payloadResolver, err := consumer.NewPayloadResolver(cfg.ObjectStore)
+		if err != nil {
+			log.Fatalf("Failed to create object store payload resolver: %v", err)
+		}
+		chunkConsumer.SetPayloadResolver(payloadResolver)
 	}
 
-	logClient, err := storage.NewQdrantClient(cfg.Qdrant, "logs")
+	dlq, err := consumer.NewDLQProducer(cfg.Kafka)
 	if err != nil {
-		log.Fatalf("Failed to create log Qdrant client: %v", err)
+		log.Fatalf("Failed to create DLQ producer: %v", err)
 	}
+	defer dlq.Close()
+	chunkConsumer.SetDLQ(dlq)
+	logConsumer.SetDLQ(dlq)
 
-	documentClient, err := storage.NewQdrantClient(cfg.Qdrant, "documents")
-	if err != nil {
-		log.Fatalf("Failed to create document Qdrant client: %v", err)
+	// simhashIndex catches near-duplicate document chunks (e.g. a re-upload
+	// that only differs by a page header/footer), so they're stored as a
+	// reference to an existing embedding instead of paying for a new one.
+	var simhashIndex *dedup.SimHashIndex
+	if cfg.Qdrant.SimHashDedupEnabled {
+		simhashIndex = dedup.NewSimHashIndex()
 	}
 
 	logFields := []string{"level", "type", "source", "collector", "kafka_topic"}
@@ -60,113 +122,265 @@ func main() {
 		log.Fatalf("Failed to create document payload indexes: %v", err)
 	}
 
+	// Subscribing these lets BootstrapServers, GroupID, Topic, and the
+	// Qdrant connection/fusion settings change via a config file edit
+	// without restarting the process; see config.Watcher.
+	watcher.Subscribe(logConsumer)
+	watcher.Subscribe(chunkConsumer)
+	watcher.Subscribe(logClient)
+	watcher.Subscribe(documentClient)
+	go watcher.Start()
+	defer watcher.Stop()
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	log.Println("Starting embedding pipeline...")
+	log.Printf("Starting embedding pipeline with %s provider (batch size %d)...", cfg.Embedding.Provider, cfg.Embedding.BatchSize)
 
 	go func() {
 		for {
-			logChunk, err := logConsumer.ConsumeLogMessage()
+			batch, err := logConsumer.ConsumeLogBatch(cfg.Embedding.BatchSize, batchPollTimeout)
 			if err != nil {
-				log.Printf("Error consuming log message: %v", err)
+				log.Printf("Error consuming log batch: %v", err)
 				continue
 			}
-			processLogMessage(*logChunk, embedder, logClient)
+			if processLogBatch(*batch, embedder, logSink, dlq, cfg.Kafka.Retry) {
+				if err := logConsumer.CommitBatch(batch); err != nil {
+					log.Printf("Error committing log batch: %v", err)
+				}
+			}
 		}
 	}()
 
 	go func() {
 		for {
-			chunk, topic, err := chunkConsumer.ConsumeChunk()
+			batch, err := chunkConsumer.ConsumeChunkBatch(cfg.Embedding.BatchSize, batchPollTimeout)
 			if err != nil {
-				log.Printf("Error consuming structured chunk: %v", err)
+				log.Printf("Error consuming chunk batch: %v", err)
 				continue
 			}
-			processDocumentChunk(*chunk, topic, embedder, documentClient)
+			if processDocumentChunkBatch(*batch, embedder, documentSink, dlq, simhashIndex, cfg.Qdrant.SimHashMaxDistance, cfg.Embedding.MaxRetries, cfg.Kafka.Retry) {
+				if err := chunkConsumer.CommitBatch(batch); err != nil {
+					log.Printf("Error committing chunk batch: %v", err)
+				}
+			}
 		}
 	}()
 
 	<-sigChan
 	log.Println("Shutting down embedding pipeline...")
 
-	if err := logClient.FlushBuffer(); err != nil {
-		log.Printf("Error flushing log buffer: %v", err)
+	if err := logSink.FlushBuffer(); err != nil {
+		log.Printf("Error flushing log sink: %v", err)
+	}
+	if err := documentSink.FlushBuffer(); err != nil {
+		log.Printf("Error flushing document sink: %v", err)
+	}
+	if err := logSink.Close(); err != nil {
+		log.Printf("Error closing log sink: %v", err)
 	}
-	if err := documentClient.FlushBuffer(); err != nil {
-		log.Printf("Error flushing document buffer: %v", err)
+	if err := documentSink.Close(); err != nil {
+		log.Printf("Error closing document sink: %v", err)
 	}
 }
 
-func processDocumentChunk(chunk consumer.ChunkOutput, topic string, embedder *embedders.GoogleEmbedder, client *storage.QdrantClient) {
-	embedding, err := embedder.GenerateEmbedding(chunk.Text)
-	if err != nil {
-		log.Printf("Embedding error (document: %s): %v", chunk.Source.DocumentTitle, err)
-		return
+// processDocumentChunkBatch embeds and stores batch, returning whether it
+// can be safely acknowledged: true once storage succeeds, false on a
+// failure that's already been routed to the DLQ, so the caller knows not to
+// commit offsets for work that was never actually stored. A store failure
+// is retried up to retry.MaxAttempts times with a simpleBackoff delay
+// before giving up to the DLQ.
+//
+// Before embedding, each chunk is checked against simhashIndex (nil when
+// SimHashDedupEnabled is off): a chunk within maxDistance Hamming distance
+// of one already indexed reuses that chunk's embedding and is flagged as a
+// duplicate in its payload instead of being sent through the embedder.
+func processDocumentChunkBatch(batch consumer.ChunkBatch, embedder embedders.Embedder, sink storage.Sink, dlq *consumer.DLQProducer, simhashIndex *dedup.SimHashIndex, maxDistance int, maxRetries int, retry config.RetryConfig) bool {
+	if len(batch.Chunks) == 0 {
+		return true
+	}
+
+	embeddings := make([][]float32, len(batch.Chunks))
+	duplicateOf := make([]string, len(batch.Chunks))
+	simhashes := make([]uint64, len(batch.Chunks))
+
+	var toEmbed []string
+	var toEmbedIdx []int
+	for i, chunk := range batch.Chunks {
+		simhashes[i] = dedup.SimHash(chunk.Text)
+
+		if simhashIndex != nil {
+			if pointID, vector, found := simhashIndex.Lookup(simhashes[i], maxDistance); found {
+				embeddings[i] = vector
+				duplicateOf[i] = pointID
+				dedup.RecordHit()
+				continue
+			}
+		}
+
+		toEmbed = append(toEmbed, chunk.Text)
+		toEmbedIdx = append(toEmbedIdx, i)
 	}
 
-	payload := map[string]interface{}{
-		"text":           chunk.Text,
-		"document_title": chunk.Source.DocumentTitle,
-		"document_type":  chunk.Source.DocumentType,
-		"chunk_index":    chunk.ChunkMetadata.ChunkIndex,
-		"word_count":     chunk.ChunkMetadata.WordCount,
-		"kafka_topic":    topic,
-		"timestamp":      time.Now().Format(time.RFC3339),
+	if len(toEmbed) > 0 {
+		freshEmbeddings, err := embedder.GenerateEmbeddingsBatch(toEmbed)
+		if err != nil {
+			log.Printf("Embedding error (document batch of %d): %v", len(toEmbed), err)
+			deadLetterChunkBatch(dlq, batch, "embed", maxRetries, err)
+			return false
+		}
+		for j, i := range toEmbedIdx {
+			embeddings[i] = freshEmbeddings[j]
+		}
 	}
 
-	data := storage.EmbeddedData{
-		Embedding: embedding,
-		Payload:   payload,
+	dataPoints := make([]storage.EmbeddedData, len(batch.Chunks))
+	pointIDs := make([]string, len(batch.Chunks))
+	for i, chunk := range batch.Chunks {
+		pointIDs[i] = uuid.New().String()
+
+		payload := map[string]interface{}{
+			"text":           chunk.Text,
+			"document_title": chunk.Source.DocumentTitle,
+			"document_type":  chunk.Source.DocumentType,
+			"chunk_index":    chunk.ChunkMetadata.ChunkIndex,
+			"word_count":     chunk.ChunkMetadata.WordCount,
+			"kafka_topic":    batch.Topics[i],
+			"timestamp":      time.Now().Format(time.RFC3339),
+		}
+		if duplicateOf[i] != "" {
+			payload["duplicate_of"] = duplicateOf[i]
+		}
+
+		dataPoints[i] = storage.EmbeddedData{
+			ID:        pointIDs[i],
+			Embedding: embeddings[i],
+			Payload:   payload,
+		}
 	}
 
-	if err := client.Store(data); err != nil {
-		log.Printf("Error storing document chunk: %v", err)
-		return
+	for i, dp := range dataPoints {
+		dp := dp
+		attempts, err := consumer.StoreWithRetry(retry, "store", func() error { return sink.Store(dp) })
+		if err != nil {
+			log.Printf("Error storing document chunk batch after %d attempt(s): %v", attempts, err)
+			deadLetterChunkBatch(dlq, batch, "store", attempts, err)
+			return false
+		}
+		if simhashIndex != nil && duplicateOf[i] == "" {
+			simhashIndex.Add(simhashes[i], pointIDs[i], embeddings[i])
+		}
 	}
 
-	log.Printf("✅ Stored document chunk: '%s' (%d words)", 
-		chunk.Source.DocumentTitle, chunk.ChunkMetadata.WordCount)
+	log.Printf("✅ Stored %d document chunks", len(dataPoints))
+	return true
 }
 
-func processLogMessage(logChunk consumer.LogChunk, embedder *embedders.GoogleEmbedder, client *storage.QdrantClient) {
-	embedding, err := embedder.GenerateEmbedding(logChunk.Message)
-	if err != nil {
-		log.Printf("Embedding error: %v", err)
+// deadLetterChunkBatch re-marshals each chunk in batch back to its original
+// wire format and sends it to its topic's DLQ. It's best-effort: a message
+// this is true for already survived N retries inside the embedder/storage
+// client, so there's nothing left to retry here.
+func deadLetterChunkBatch(dlq *consumer.DLQProducer, batch consumer.ChunkBatch, stage string, attempts int, cause error) {
+	if dlq == nil {
+		consumer.RecordDropped(stage)
 		return
 	}
 
-	collector := "unknown"
-	if collectorValue, exists := logChunk.Details["collector"]; exists {
-		if collectorStr, ok := collectorValue.(string); ok {
-			collector = collectorStr
+	for i, chunk := range batch.Chunks {
+		raw, err := json.Marshal(chunk)
+		if err != nil {
+			log.Printf("failed to re-marshal chunk for DLQ: %v", err)
+			continue
+		}
+
+		failure := consumer.FailureMeta{Error: cause.Error(), Stage: stage, Attempts: attempts}
+		if err := dlq.Send(batch.Topics[i], batch.Partitions[i], batch.Offsets[i], raw, failure); err != nil {
+			log.Printf("failed to send chunk to DLQ: %v", err)
 		}
 	}
+}
 
-	payload := map[string]interface{}{
-		"message":     logChunk.Message,
-		"timestamp":   logChunk.Timestamp.Format(time.RFC3339),
-		"level":       logChunk.Level,
-		"type":        logChunk.Type,
-		"source":      logChunk.Source,
-		"collector":   collector,
-		"kafka_topic": "logs",
+// processLogBatch embeds and stores batch, returning whether it can be
+// safely acknowledged. A store failure is retried up to retry.MaxAttempts
+// times with a simpleBackoff delay before giving up to the DLQ.
+func processLogBatch(batch consumer.LogBatch, embedder embedders.Embedder, sink storage.Sink, dlq *consumer.DLQProducer, retry config.RetryConfig) bool {
+	if len(batch.Chunks) == 0 {
+		return true
 	}
 
-	if len(logChunk.Details) > 0 {
-		payload["details"] = logChunk.Details
+	texts := make([]string, len(batch.Chunks))
+	for i, logChunk := range batch.Chunks {
+		texts[i] = logChunk.Message
 	}
 
-	data := storage.EmbeddedData{
-		Embedding: embedding,
-		Payload:   payload,
+	embeddings, err := embedder.GenerateEmbeddingsBatch(texts)
+	if err != nil {
+		log.Printf("Embedding error (log batch of %d): %v", len(batch.Chunks), err)
+		deadLetterLogBatch(dlq, batch, "embed", retry.MaxAttempts, err)
+		return false
 	}
 
-	if err := client.Store(data); err != nil {
-		log.Printf("Error storing log message: %v", err)
+	dataPoints := make([]storage.EmbeddedData, len(batch.Chunks))
+	for i, logChunk := range batch.Chunks {
+		collector := "unknown"
+		if collectorValue, exists := logChunk.Details["collector"]; exists {
+			if collectorStr, ok := collectorValue.(string); ok {
+				collector = collectorStr
+			}
+		}
+
+		payload := map[string]interface{}{
+			"message":     logChunk.Message,
+			"timestamp":   logChunk.Timestamp.Format(time.RFC3339),
+			"level":       logChunk.Level,
+			"type":        logChunk.Type,
+			"source":      logChunk.Source,
+			"collector":   collector,
+			"kafka_topic": "logs",
+		}
+
+		if len(logChunk.Details) > 0 {
+			payload["details"] = logChunk.Details
+		}
+
+		dataPoints[i] = storage.EmbeddedData{
+			Embedding: embeddings[i],
+			Payload:   payload,
+		}
+	}
+
+	for _, dp := range dataPoints {
+		dp := dp
+		attempts, err := consumer.StoreWithRetry(retry, "store", func() error { return sink.Store(dp) })
+		if err != nil {
+			log.Printf("Error storing log batch after %d attempt(s): %v", attempts, err)
+			deadLetterLogBatch(dlq, batch, "store", attempts, err)
+			return false
+		}
+	}
+
+	log.Printf("✅ Stored %d logs", len(dataPoints))
+	return true
+}
+
+// deadLetterLogBatch re-marshals each log chunk in batch back to its
+// original wire format and sends it to the logs DLQ.
+func deadLetterLogBatch(dlq *consumer.DLQProducer, batch consumer.LogBatch, stage string, attempts int, cause error) {
+	if dlq == nil {
+		consumer.RecordDropped(stage)
 		return
 	}
 
-	log.Printf("✅ Stored log: %s/%s - %s", 
-		logChunk.Type, logChunk.Level, logChunk.Source)
-}
\ No newline at end of file
+	for i, logChunk := range batch.Chunks {
+		raw, err := json.Marshal(logChunk)
+		if err != nil {
+			log.Printf("failed to re-marshal log chunk for DLQ: %v", err)
+			continue
+		}
+
+		failure := consumer.FailureMeta{Error: cause.Error(), Stage: stage, Attempts: attempts}
+		if err := dlq.Send("logs", batch.Partitions[i], batch.Offsets[i], raw, failure); err != nil {
+			log.Printf("failed to send log chunk to DLQ: %v", err)
+		}
+	}
+}
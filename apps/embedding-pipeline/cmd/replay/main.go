@@ -0,0 +1,140 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/storage"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// replay reads every archived object under a topic's prefix in the
+// configured archive bucket and restores its records directly into a
+// Qdrant collection, skipping the embedder entirely since the archive
+// already carries the embeddings. This is how you'd rebuild a collection
+// after a schema change without re-running Gemini over the source
+// documents again.
+func main() {
+	configPath := flag.String("config", "config.json", "Path to configuration file")
+	topic := flag.String("topic", "", "Topic prefix to replay (matches the archive sink's object key prefix)")
+	collection := flag.String("collection", "", "Destination Qdrant collection")
+	batchSize := flag.Int("batch-size", 100, "Records per StoreBatch call")
+	flag.Parse()
+
+	if *topic == "" || *collection == "" {
+		log.Fatal("--topic and --collection are required")
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	if !cfg.Archive.Enabled {
+		log.Fatal("archive sink is not configured in config.json")
+	}
+
+	objectClient, err := minio.New(cfg.Archive.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.Archive.AccessKeyID, cfg.Archive.SecretAccessKey, ""),
+		Secure: cfg.Archive.UseSSL,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create object storage client: %v", err)
+	}
+
+	qdrantClient, err := storage.NewQdrantClient(cfg.Qdrant, *collection)
+	if err != nil {
+		log.Fatalf("Failed to create Qdrant client: %v", err)
+	}
+
+	ctx := context.Background()
+	total, err := replayTopic(ctx, objectClient, qdrantClient, cfg.Archive.Bucket, *topic, *batchSize)
+	if err != nil {
+		log.Fatalf("Replay failed after %d records: %v", total, err)
+	}
+
+	log.Printf("Replayed %d records from topic %q into collection %q", total, *topic, *collection)
+}
+
+func replayTopic(ctx context.Context, objectClient *minio.Client, qdrantClient *storage.QdrantClient, bucket, topic string, batchSize int) (int, error) {
+	objects := objectClient.ListObjects(ctx, bucket, minio.ListObjectsOptions{
+		Prefix:    topic + "/",
+		Recursive: true,
+	})
+
+	var batch []storage.EmbeddedData
+	var total int
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := qdrantClient.StoreBatch(batch); err != nil {
+			return fmt.Errorf("failed to store replayed batch: %w", err)
+		}
+		total += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for obj := range objects {
+		if obj.Err != nil {
+			return total, fmt.Errorf("failed to list archive objects: %w", obj.Err)
+		}
+
+		records, err := readArchiveObject(ctx, objectClient, bucket, obj.Key)
+		if err != nil {
+			log.Printf("Skipping unreadable archive object %s: %v", obj.Key, err)
+			continue
+		}
+
+		for _, record := range records {
+			batch = append(batch, record)
+			if len(batch) >= batchSize {
+				if err := flush(); err != nil {
+					return total, err
+				}
+			}
+		}
+	}
+
+	return total, flush()
+}
+
+// readArchiveObject downloads and decodes one gzip-NDJSON archive object
+// into its EmbeddedData records.
+func readArchiveObject(ctx context.Context, client *minio.Client, bucket, key string) ([]storage.EmbeddedData, error) {
+	obj, err := client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	gz, err := gzip.NewReader(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+	defer gz.Close()
+
+	var records []storage.EmbeddedData
+	decoder := json.NewDecoder(gz)
+	for {
+		var record storage.EmbeddedData
+		if err := decoder.Decode(&record); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode record: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,117 @@
+// Command snapshot drives Qdrant's snapshot API outside the main embedding
+// pipeline process: take one-off backups, list what's available, or restore
+// a collection (recreated with the config file's vector/hnsw/quantization
+// settings) from a previously captured snapshot.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/data/logs/logtail"
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/storage"
+)
+
+func main() {
+	configPath := flag.String("config", "config.json", "Path to configuration file")
+	collection := flag.String("collection", "", "Collection to operate on")
+	backup := flag.Bool("backup", false, "Take a one-off snapshot and exit")
+	schedule := flag.Duration("schedule", 0, "Run a recurring backup loop at this interval instead of exiting")
+	bucket := flag.String("bucket", "", "GCS bucket to push scheduled backups to")
+	restoreURL := flag.String("restore", "", "Recreate --collection and restore it from this snapshot URL")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	if *restoreURL != "" {
+		if *collection == "" {
+			log.Fatal("--collection is required with --restore")
+		}
+		runRestore(cfg, *collection, *restoreURL)
+		return
+	}
+
+	client, err := storage.NewDocumentQdrantClientWithConfig(cfg.Qdrant)
+	if err != nil {
+		log.Fatalf("Failed to create Qdrant client: %v", err)
+	}
+
+	switch {
+	case *schedule > 0:
+		runScheduledBackups(client, cfg, *bucket, *schedule)
+	case *backup:
+		runOneOffBackup(client, *collection)
+	default:
+		log.Fatal("specify one of --backup, --schedule, or --restore")
+	}
+}
+
+func runOneOffBackup(client *storage.DocumentQdrantClient, collection string) {
+	if collection == "" {
+		log.Fatal("--collection is required with --backup")
+	}
+
+	snapshot, err := client.CreateSnapshot(collection)
+	if err != nil {
+		log.Fatalf("Failed to create snapshot: %v", err)
+	}
+
+	log.Printf("Created snapshot %s for collection %s", snapshot.Name, collection)
+}
+
+func runScheduledBackups(client *storage.DocumentQdrantClient, cfg *config.Config, bucket string, interval time.Duration) {
+	if bucket == "" {
+		log.Fatal("--bucket is required with --schedule")
+	}
+
+	collections := make([]string, 0, len(cfg.Qdrant.Collections))
+	for _, name := range cfg.Qdrant.Collections {
+		collections = append(collections, name)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	sink, err := logtail.NewGCSSink(ctx, bucket, "qdrant-snapshots", "")
+	if err != nil {
+		log.Fatalf("Failed to create GCS sink: %v", err)
+	}
+	defer sink.Close()
+
+	log.Printf("Starting scheduled backups of %v every %s", collections, interval)
+	scheduler := storage.NewBackupScheduler(client, collections, sink, interval)
+	if err := scheduler.Run(ctx); err != nil && err != context.Canceled {
+		log.Fatalf("Backup scheduler stopped: %v", err)
+	}
+}
+
+func runRestore(cfg *config.Config, collection, url string) {
+	restoreCfg := cfg.Qdrant
+	restoreCfg.Collections = map[string]string{collection: collection}
+
+	client, err := storage.NewDocumentQdrantClientWithConfig(restoreCfg)
+	if err != nil {
+		log.Fatalf("Failed to recreate collection %s: %v", collection, err)
+	}
+
+	if err := client.RestoreFromURL(collection, url); err != nil {
+		log.Fatalf("Failed to restore collection %s: %v", collection, err)
+	}
+
+	fmt.Printf("Restored collection %s from %s\n", collection, url)
+}
@@ -3,13 +3,44 @@ package config
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/secrets"
 )
 
 type Config struct {
-	Kafka  KafkaConfig  `json:"kafka"`
-	Qdrant QdrantConfig `json:"qdrant"`
-	Gemini GeminiConfig `json:"gemini"`
+	Kafka       KafkaConfig           `json:"kafka"`
+	Qdrant      QdrantConfig          `json:"qdrant"`
+	Gemini      GeminiConfig          `json:"gemini"`
+	Embedding   EmbeddingConfig       `json:"embedding"`
+	Archive     ArchiveConfig         `json:"archive"`
+	ObjectStore ObjectStoreConfig     `json:"object_store"`
+	Secrets     secrets.BackendConfig `json:"secrets"`
+}
+
+// ObjectStoreConfig points the chunk consumer at the same MinIO/S3 bucket
+// KafkaAgenticChunker spills oversized chunk bodies to, so a chunk message that
+// arrives with a payload_ref instead of inline text can be resolved before
+// embedding. Shares ArchiveConfig's field shape since both talk to the same
+// kind of backend.
+type ObjectStoreConfig struct {
+	Endpoint        string `json:"endpoint"`
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	UseSSL          bool   `json:"use_ssl"`
+}
+
+// ArchiveConfig configures the optional S3/MinIO archive sink. When
+// Enabled, embedded records are fanned out to cold object storage
+// alongside Qdrant, for replay after a schema change or collection loss.
+type ArchiveConfig struct {
+	Enabled            bool   `json:"enabled"`
+	Endpoint           string `json:"endpoint"`
+	Bucket             string `json:"bucket"`
+	AccessKeyID        string `json:"access_key_id"`
+	SecretAccessKey    string `json:"secret_access_key"`
+	UseSSL             bool   `json:"use_ssl"`
+	RotateSize         int    `json:"rotate_size"`          // records per object before rotating
+	RotateIntervalSecs int    `json:"rotate_interval_secs"` // max age of an open object before rotating
 }
 
 type KafkaConfig struct {
@@ -17,34 +48,158 @@ type KafkaConfig struct {
 	GroupID          string   `json:"group_id"`
 	Topic            []string `json:"topic"`
 	AutoOffsetReset  string   `json:"auto_offset_reset"`
+
+	Security KafkaSecurityConfig `json:"security"`
+
+	// Compression sets the producer-side compression.type ("none",
+	// "snappy", "zstd", or "lz4") for anything this pipeline produces
+	// (DLQ records). ConsumeChunkBatch does not read it: a chunk value's
+	// frame header names its own codec.
+	Compression string `json:"compression"`
+
+	// DLQTopic, if set, overrides the default "<source topic>.dlq" name so
+	// every source topic's poison messages land on one shared DLQ topic.
+	DLQTopic string `json:"dlq_topic"`
+
+	Retry RetryConfig `json:"retry"`
+}
+
+// RetryConfig tunes simpleBackoff, the retry policy a failed store goes
+// through before the message is routed to the DLQ.
+type RetryConfig struct {
+	MaxAttempts      int     `json:"max_attempts"`
+	InitialBackoffMs int     `json:"initial_backoff_ms"`
+	BackoffFactor    float64 `json:"backoff_factor"`
+	MaxBackoffMs     int     `json:"max_backoff_ms"`
+}
+
+// KafkaSecurityConfig configures how consumers authenticate to the Kafka
+// cluster. Protocol left empty means plaintext, unauthenticated Kafka
+// (local dev); a managed cluster (Strimzi, Confluent Cloud) will set
+// Protocol to "ssl" or "sasl_ssl" and one of SASLMechanism/TLS/OAuth.
+type KafkaSecurityConfig struct {
+	Protocol      string      `json:"protocol"`       // "plaintext", "ssl", "sasl_plaintext", "sasl_ssl"
+	SASLMechanism string      `json:"sasl_mechanism"` // "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512", "OAUTHBEARER"
+	TLS           TLSConfig   `json:"tls"`
+	OAuth         OAuthConfig `json:"oauth"`
+}
+
+type TLSConfig struct {
+	CAFile             string `json:"ca_file"`
+	CertFile           string `json:"cert_file"`
+	KeyFile            string `json:"key_file"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+}
+
+// OAuthConfig drives a client-credentials token refresher for
+// SASL/OAUTHBEARER. It's shared by the Kafka consumers and any HTTP
+// client (Qdrant, object storage) that also needs a bearer token, so
+// credentials are configured in exactly one place.
+type OAuthConfig struct {
+	TokenURL     string   `json:"token_url"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Scopes       []string `json:"scopes"`
 }
 
 type QdrantConfig struct {
-	URL        string            `json:"url"`
-	APIKey     string            `json:"api_key"`
+	URL         string            `json:"url"`
+	APIKey      string            `json:"api_key" secret:"true"`
 	Collections map[string]string `json:"collections"` // Map topic to collection name
-	VectorSize int               `json:"vector_size"`
+	VectorSize  int               `json:"vector_size"`
+
+	// TLS and OAuth reuse KafkaSecurityConfig's TLS/OAuth shapes, so mTLS
+	// and client-credentials auth are configured from one set of fields
+	// across both the Kafka consumers and the Qdrant HTTP client.
+	TLS   TLSConfig   `json:"tls"`
+	OAuth OAuthConfig `json:"oauth"`
+
+	// HybridSearch, if true, makes document collections store a named
+	// "sparse" BM25 vector alongside the named "dense" embedding so
+	// retrieval can fuse lexical and semantic results.
+	HybridSearch  bool   `json:"hybrid_search"`
+	BM25StatsPath string `json:"bm25_stats_path"`
+
+	// FusionAlgorithm selects how HybridSearch combines its dense and
+	// sparse prefetch legs: "rrf" (reciprocal rank fusion, the default) or
+	// "dbsf" (distribution-based score fusion). PrefetchLimit bounds how
+	// many candidates each leg contributes before fusion.
+	FusionAlgorithm string `json:"fusion_algorithm"`
+	PrefetchLimit   int    `json:"prefetch_limit"`
+
+	// Dedup, if enabled, skips re-embedding a chunk whose content hash was
+	// already stored. Backend selects where that hash->point-ID mapping
+	// lives: "bolt", "badger", or "qdrant" (a payload lookup, no extra
+	// database).
+	DedupEnabled bool   `json:"dedup_enabled"`
+	DedupBackend string `json:"dedup_backend"`
+	DedupPath    string `json:"dedup_path"`
+	DedupLRUSize int    `json:"dedup_lru_size"`
+
+	// SimHashDedup, if enabled, catches near-duplicates that DedupEnabled's
+	// exact content hash misses: a chunk whose SimHash is within
+	// SimHashMaxDistance of one already seen is stored as a reference to
+	// that point's embedding instead of being sent through the embedder.
+	SimHashDedupEnabled bool `json:"simhash_dedup_enabled"`
+	SimHashMaxDistance  int  `json:"simhash_max_distance"`
+
+	// DeadLetterDir, if set, is where a document batch's upsert is
+	// persisted as JSONL after exhausting its retries, so it can be
+	// inspected and replayed with ReplayDeadLetter instead of being lost.
+	DeadLetterDir string `json:"dead_letter_dir"`
 }
 
 type GeminiConfig struct {
-	APIKey string `json:"api_key"`
+	APIKey string `json:"api_key" secret:"true"`
 	Model  string `json:"model"`
 }
 
+type EmbeddingConfig struct {
+	Provider   string `json:"provider"` // gemini, openai, cohere, local
+	Model      string `json:"model"`
+	APIKey     string `json:"api_key"`
+	Endpoint   string `json:"endpoint"` // local sidecar URL; ignored by hosted providers
+	BatchSize  int    `json:"batch_size"`
+	MaxRetries int    `json:"max_retries"`
+}
+
+// LoadConfig loads configPath into a Config. The file format is inferred
+// from configPath's extension (.yaml/.yml, otherwise JSON); use
+// LoadConfigFormat to pick one explicitly. Before being parsed, the file
+// (and, if present, its environment-specific overlay) goes through
+// ${VAR}/${VAR:-default} interpolation and path-based environment
+// variable overrides — see loader.go.
 func LoadConfig(configPath string) (*Config, error) {
-	file, err := os.Open(configPath)
+	return LoadConfigFormat(configPath, "")
+}
+
+// LoadConfigFormat loads configPath as LoadConfig does, with an explicit
+// format ("yaml" or "json") instead of inferring one from the file
+// extension.
+func LoadConfigFormat(configPath, format string) (*Config, error) {
+	merged, err := loadConfigMap(configPath, format)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open config file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
 
-	var config Config
-	decoder := json.NewDecoder(file)
-	err = decoder.Decode(&config)
+	reencoded, err := json.Marshal(merged)
 	if err != nil {
+		return nil, fmt.Errorf("failed to re-encode merged config: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(reencoded, &config); err != nil {
 		return nil, fmt.Errorf("failed to decode config: %w", err)
 	}
 
+	provider, err := secrets.New(config.Secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build secrets provider: %w", err)
+	}
+	if err := resolveSecrets(&config, provider); err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
 	if config.Kafka.BootstrapServers == "" {
 		config.Kafka.BootstrapServers = "localhost:9092"
 	}
@@ -57,28 +212,98 @@ func LoadConfig(configPath string) (*Config, error) {
 	if config.Kafka.AutoOffsetReset == "" {
 		config.Kafka.AutoOffsetReset = "earliest"
 	}
+	if config.Kafka.Security.Protocol == "" {
+		config.Kafka.Security.Protocol = "plaintext"
+	}
+	switch config.Kafka.Compression {
+	case "", "none", "snappy", "zstd", "lz4":
+	default:
+		return nil, fmt.Errorf("unsupported kafka.compression %q", config.Kafka.Compression)
+	}
+	if config.Kafka.Retry.MaxAttempts == 0 {
+		config.Kafka.Retry.MaxAttempts = 3
+	}
+	if config.Kafka.Retry.InitialBackoffMs == 0 {
+		config.Kafka.Retry.InitialBackoffMs = 100
+	}
+	if config.Kafka.Retry.BackoffFactor == 0 {
+		config.Kafka.Retry.BackoffFactor = 2
+	}
+	if config.Kafka.Retry.MaxBackoffMs == 0 {
+		config.Kafka.Retry.MaxBackoffMs = 30_000
+	}
 
 	if config.Qdrant.URL == "" {
 		config.Qdrant.URL = "http://localhost:6333"
 	}
-	
+
 	if config.Qdrant.Collections == nil {
 		config.Qdrant.Collections = make(map[string]string)
 		for _, topic := range config.Kafka.Topic {
-			config.Qdrant.Collections[topic] = topic 
+			config.Qdrant.Collections[topic] = topic
 		}
 	}
-	
+
 	if config.Qdrant.VectorSize == 0 {
 		config.Qdrant.VectorSize = 768
 	}
 
+	if config.Qdrant.HybridSearch && config.Qdrant.BM25StatsPath == "" {
+		config.Qdrant.BM25StatsPath = "bm25_stats.json"
+	}
+
+	if config.Qdrant.DedupEnabled {
+		if config.Qdrant.DedupBackend == "" {
+			config.Qdrant.DedupBackend = "qdrant"
+		}
+		if config.Qdrant.DedupPath == "" {
+			config.Qdrant.DedupPath = "dedup.db"
+		}
+		if config.Qdrant.DedupLRUSize == 0 {
+			config.Qdrant.DedupLRUSize = 100_000
+		}
+	}
+
+	if config.Qdrant.SimHashDedupEnabled && config.Qdrant.SimHashMaxDistance == 0 {
+		config.Qdrant.SimHashMaxDistance = 3
+	}
+
 	if config.Gemini.Model == "" {
 		config.Gemini.Model = "models/text-embedding-004"
 	}
 
-	if config.Gemini.APIKey == "" {
-		return nil, fmt.Errorf("gemini api_key is required")
+	if config.Embedding.Provider == "" {
+		config.Embedding.Provider = "gemini"
+	}
+	if config.Embedding.Provider == "gemini" {
+		if config.Embedding.Model == "" {
+			config.Embedding.Model = config.Gemini.Model
+		}
+		if config.Embedding.APIKey == "" {
+			config.Embedding.APIKey = config.Gemini.APIKey
+		}
+	}
+	if config.Embedding.BatchSize == 0 {
+		config.Embedding.BatchSize = 20
+	}
+	if config.Embedding.MaxRetries == 0 {
+		config.Embedding.MaxRetries = 3
+	}
+
+	if config.Embedding.APIKey == "" && config.Embedding.Provider != "local" {
+		return nil, fmt.Errorf("%s api_key is required", config.Embedding.Provider)
+	}
+
+	if config.Archive.Enabled {
+		if config.Archive.Bucket == "" {
+			return nil, fmt.Errorf("archive.bucket is required when archive.enabled is true")
+		}
+		if config.Archive.RotateSize == 0 {
+			config.Archive.RotateSize = 1000
+		}
+		if config.Archive.RotateIntervalSecs == 0 {
+			config.Archive.RotateIntervalSecs = 300
+		}
 	}
 
 	return &config, nil
@@ -103,5 +328,11 @@ func GetConfig() *Config {
 		Gemini: GeminiConfig{
 			Model: "models/text-embedding-004",
 		},
+		Embedding: EmbeddingConfig{
+			Provider:   "gemini",
+			Model:      "models/text-embedding-004",
+			BatchSize:  20,
+			MaxRetries: 3,
+		},
 	}
-}
\ No newline at end of file
+}
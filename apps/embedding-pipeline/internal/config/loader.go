@@ -0,0 +1,208 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envOverridePrefix is prepended to a config path when deriving the
+// environment variable name an env-var-by-path override looks for, e.g.
+// kafka.bootstrap_servers -> ORBIT_KAFKA_BOOTSTRAP_SERVERS.
+const envOverridePrefix = "ORBIT"
+
+// envVarName is the environment variable loadConfigMap reads to pick an
+// environment-specific overlay file: "config.yaml" with ORBIT_ENV=prod
+// overlays "config.prod.yaml" on top of it if that file exists.
+const envVarName = "ORBIT_ENV"
+
+// interpolationPattern matches ${VAR} and ${VAR:-default} placeholders.
+var interpolationPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// interpolateEnv replaces every ${VAR}/${VAR:-default} placeholder in data
+// with VAR's environment value, the given default if VAR is unset, or an
+// empty string if VAR is unset and no default was given. Run on the raw
+// file bytes before parsing, so it applies identically to YAML and JSON.
+func interpolateEnv(data []byte) []byte {
+	return interpolationPattern.ReplaceAllFunc(data, func(match []byte) []byte {
+		groups := interpolationPattern.FindSubmatch(match)
+		name := string(groups[1])
+		if value, ok := os.LookupEnv(name); ok {
+			return []byte(value)
+		}
+		if len(groups[2]) > 0 {
+			return groups[3]
+		}
+		return nil
+	})
+}
+
+// inferFormat picks "yaml" or "json" from path's extension, defaulting to
+// json for anything that isn't .yaml/.yml.
+func inferFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	default:
+		return "json"
+	}
+}
+
+// loadFormat reads path, applies env-var interpolation, and decodes it
+// into a generic map so it can be merged with an overlay before being
+// unmarshaled into Config. format is "yaml" or "json"; an empty format is
+// inferred from path's extension.
+func loadFormat(path, format string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	data = interpolateEnv(data)
+
+	if format == "" {
+		format = inferFormat(path)
+	}
+
+	var decoded map[string]interface{}
+	switch format {
+	case "yaml":
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config %s: %w", path, err)
+		}
+	case "json":
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON config %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config format %q for %s", format, path)
+	}
+
+	return decoded, nil
+}
+
+// overlayPath returns the environment-specific overlay file LoadConfig
+// looks for alongside base, e.g. base "config.yaml" with env "prod" ->
+// "config.prod.yaml". Returns "" if env is empty.
+func overlayPath(base, env string) string {
+	if env == "" {
+		return ""
+	}
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "." + env + ext
+}
+
+// mergeMaps deep-merges overlay onto base into a new map. A key present as
+// a nested map in both merges recursively; anything else from overlay
+// replaces base's value outright.
+func mergeMaps(base, overlay map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		merged[k] = v
+	}
+
+	for k, overlayValue := range overlay {
+		baseValue, exists := merged[k]
+		if !exists {
+			merged[k] = overlayValue
+			continue
+		}
+
+		baseMap, baseIsMap := baseValue.(map[string]interface{})
+		overlayMap, overlayIsMap := overlayValue.(map[string]interface{})
+		if baseIsMap && overlayIsMap {
+			merged[k] = mergeMaps(baseMap, overlayMap)
+		} else {
+			merged[k] = overlayValue
+		}
+	}
+
+	return merged
+}
+
+// applyEnvOverrides walks m in place, overriding every leaf whose path has
+// a matching "ORBIT_<PATH>" environment variable set (path segments
+// upper-cased and joined with underscores, e.g. kafka.bootstrap_servers ->
+// ORBIT_KAFKA_BOOTSTRAP_SERVERS). An override can only replace a key m
+// already has; it can't introduce a field the config files didn't set.
+func applyEnvOverrides(m map[string]interface{}, pathPrefix string) {
+	for k, v := range m {
+		path := k
+		if pathPrefix != "" {
+			path = pathPrefix + "_" + k
+		}
+
+		if nested, ok := v.(map[string]interface{}); ok {
+			applyEnvOverrides(nested, path)
+			continue
+		}
+
+		raw, ok := os.LookupEnv(envOverridePrefix + "_" + strings.ToUpper(path))
+		if !ok {
+			continue
+		}
+
+		m[k] = coerceEnvValue(raw, v)
+	}
+}
+
+// coerceEnvValue parses raw into the same Go type as example (the value
+// currently on file for this key), so overriding a bool or numeric field
+// doesn't turn it into a string the final struct decode would reject.
+// example being a []interface{} (a YAML/JSON list) splits raw on commas.
+// Anything else, including a key with no existing value to match against,
+// is used as a plain string.
+func coerceEnvValue(raw string, example interface{}) interface{} {
+	switch example.(type) {
+	case bool:
+		if parsed, err := strconv.ParseBool(raw); err == nil {
+			return parsed
+		}
+	case int:
+		if parsed, err := strconv.Atoi(raw); err == nil {
+			return parsed
+		}
+	case float64:
+		if parsed, err := strconv.ParseFloat(raw, 64); err == nil {
+			return parsed
+		}
+	case []interface{}:
+		parts := strings.Split(raw, ",")
+		values := make([]interface{}, len(parts))
+		for i, part := range parts {
+			values[i] = strings.TrimSpace(part)
+		}
+		return values
+	}
+	return raw
+}
+
+// loadConfigMap builds the final configuration map for configPath: the
+// base file, deep-merged with its environment-specific overlay (when
+// ORBIT_ENV is set and that overlay file exists), deep-merged with
+// path-based environment variable overrides.
+func loadConfigMap(configPath, format string) (map[string]interface{}, error) {
+	base, err := loadFormat(configPath, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if overlay := overlayPath(configPath, os.Getenv(envVarName)); overlay != "" {
+		if _, err := os.Stat(overlay); err == nil {
+			overlayMap, err := loadFormat(overlay, format)
+			if err != nil {
+				return nil, err
+			}
+			base = mergeMaps(base, overlayMap)
+		}
+	}
+
+	applyEnvOverrides(base, "")
+
+	return base, nil
+}
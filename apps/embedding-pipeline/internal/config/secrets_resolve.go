@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/secrets"
+)
+
+// secretRefPrefix marks a config value as a secret reference to resolve
+// through the active provider even on a field that isn't itself tagged
+// secret:"true" — useful for a field reused outside the fixed set this
+// package tags directly.
+const secretRefPrefix = "secret://"
+
+// resolveSecrets walks cfg's fields recursively, replacing the value of
+// every string field tagged secret:"true" (or already holding a
+// "secret://"-prefixed reference) with the plaintext provider.Resolve
+// returns for it. Called once, right after a config file is decoded and
+// before LoadConfigFormat's defaulting/validation chain runs, so every
+// later reader of Config sees a resolved value.
+func resolveSecrets(cfg *Config, provider secrets.Provider) error {
+	return resolveSecretsValue(reflect.ValueOf(cfg).Elem(), provider)
+}
+
+func resolveSecretsValue(v reflect.Value, provider secrets.Provider) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := v.Field(i)
+
+		switch fieldValue.Kind() {
+		case reflect.Struct:
+			if err := resolveSecretsValue(fieldValue, provider); err != nil {
+				return err
+			}
+		case reflect.String:
+			current := fieldValue.String()
+			tagged := field.Tag.Get("secret") == "true"
+			prefixed := len(current) > len(secretRefPrefix) && current[:len(secretRefPrefix)] == secretRefPrefix
+
+			if !tagged && !prefixed {
+				continue
+			}
+			if current == "" {
+				continue
+			}
+
+			ref := current
+			if prefixed {
+				ref = current[len(secretRefPrefix):]
+			}
+
+			resolved, err := provider.Resolve(ref)
+			if err != nil {
+				return fmt.Errorf("failed to resolve secret for %s: %w", field.Name, err)
+			}
+			fieldValue.SetString(resolved)
+		}
+	}
+
+	return nil
+}
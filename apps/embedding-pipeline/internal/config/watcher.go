@@ -0,0 +1,225 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Reconfigurer is implemented by a long-lived component that can apply a
+// newly loaded Config in place instead of being rebuilt from scratch.
+// Reconfigure must be atomic from the Watcher's point of view: if it
+// returns an error, the component is expected to still be serving whatever
+// config it had before the call, so the Watcher can roll every other
+// subscriber back to match.
+type Reconfigurer interface {
+	Reconfigure(cfg *Config) error
+}
+
+// ConfigDiff is one top-level Config field whose value changed between a
+// Watcher's previously applied Config and a newly loaded one.
+type ConfigDiff struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+// defaultPollInterval is how often a Watcher checks its config file's mtime
+// when none is given to NewWatcher.
+const defaultPollInterval = 5 * time.Second
+
+// Watcher polls a config file for changes. On a change it reloads and
+// validates the file through LoadConfigFormat, diffs the result against the
+// last applied Config, and publishes the new Config to every subscriber by
+// calling its Reconfigure method. If any subscriber rejects the reload, the
+// Watcher rolls back every subscriber that already accepted it, so the
+// pipeline's components never end up disagreeing about which Config is
+// live.
+type Watcher struct {
+	configPath   string
+	format       string
+	pollInterval time.Duration
+
+	mu      sync.Mutex
+	current *Config
+	modTime time.Time
+	subs    []Reconfigurer
+
+	updates chan *Config
+	stopCh  chan struct{}
+}
+
+// NewWatcher builds a Watcher for configPath, seeded with current (the
+// Config the process already loaded at startup via LoadConfig/
+// LoadConfigFormat), so the first poll only fires a reload once the file
+// changes after the process started, not immediately on Start.
+func NewWatcher(configPath, format string, current *Config) (*Watcher, error) {
+	modTime, err := statModTime(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat config file %s: %w", configPath, err)
+	}
+
+	return &Watcher{
+		configPath:   configPath,
+		format:       format,
+		pollInterval: defaultPollInterval,
+		current:      current,
+		modTime:      modTime,
+		updates:      make(chan *Config, 1),
+		stopCh:       make(chan struct{}),
+	}, nil
+}
+
+func statModTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// SetPollInterval overrides the default poll interval. Call before Start.
+func (w *Watcher) SetPollInterval(interval time.Duration) {
+	w.pollInterval = interval
+}
+
+// Subscribe registers r to be reconfigured whenever the watched file
+// changes. A subscriber added after Start is already running is picked up
+// on the next poll tick.
+func (w *Watcher) Subscribe(r Reconfigurer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subs = append(w.subs, r)
+}
+
+// Updates returns a channel of Configs this Watcher has successfully
+// applied, for a caller that wants to observe reloads directly instead of
+// (or in addition to) registering a Reconfigurer. The channel holds one
+// Config; a Config applied while the previous one is still unread is not
+// sent.
+func (w *Watcher) Updates() <-chan *Config {
+	return w.updates
+}
+
+// Start polls configPath every poll interval until Stop is called. It
+// blocks, so callers run it with `go watcher.Start()`.
+func (w *Watcher) Start() {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.poll()
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// Stop ends a running Start loop.
+func (w *Watcher) Stop() {
+	close(w.stopCh)
+}
+
+// poll reloads configPath if its mtime has moved forward, logging (rather
+// than failing) a bad reload so a typo in the file doesn't take down the
+// watcher loop; the process keeps running on its last-known-good Config
+// until the file is fixed.
+func (w *Watcher) poll() {
+	modTime, err := statModTime(w.configPath)
+	if err != nil {
+		log.Printf("config watcher: failed to stat %s: %v", w.configPath, err)
+		return
+	}
+	if !modTime.After(w.modTime) {
+		return
+	}
+	w.modTime = modTime
+
+	next, err := LoadConfigFormat(w.configPath, w.format)
+	if err != nil {
+		log.Printf("config watcher: failed to reload %s, keeping previous config: %v", w.configPath, err)
+		return
+	}
+
+	w.mu.Lock()
+	previous := w.current
+	w.mu.Unlock()
+
+	diff := Diff(previous, next)
+	if len(diff) == 0 {
+		return
+	}
+	for _, d := range diff {
+		log.Printf("config watcher: %s changed from %v to %v", d.Field, d.Old, d.New)
+	}
+
+	if err := w.apply(next); err != nil {
+		log.Printf("config watcher: %v", err)
+		return
+	}
+
+	select {
+	case w.updates <- next:
+	default:
+	}
+}
+
+// apply publishes next to every subscriber. If one rejects it, apply rolls
+// back by re-calling Reconfigure with previous on every subscriber that
+// already accepted next, and returns the rejecting subscriber's error.
+func (w *Watcher) apply(next *Config) error {
+	w.mu.Lock()
+	previous := w.current
+	subs := make([]Reconfigurer, len(w.subs))
+	copy(subs, w.subs)
+	w.mu.Unlock()
+
+	applied := make([]Reconfigurer, 0, len(subs))
+	for _, s := range subs {
+		if err := s.Reconfigure(next); err != nil {
+			for _, a := range applied {
+				if rollbackErr := a.Reconfigure(previous); rollbackErr != nil {
+					log.Printf("config watcher: rollback failed for a subscriber: %v", rollbackErr)
+				}
+			}
+			return fmt.Errorf("subscriber rejected reloaded config, rolled back: %w", err)
+		}
+		applied = append(applied, s)
+	}
+
+	w.mu.Lock()
+	w.current = next
+	w.mu.Unlock()
+
+	return nil
+}
+
+// Diff reports every top-level Config field whose value differs between
+// old and next, comparing with reflect.DeepEqual so a changed nested
+// struct or slice is still reported under its top-level field name. A nil
+// old or next yields no diffs, since that isn't a reload, it's the first
+// load.
+func Diff(old, next *Config) []ConfigDiff {
+	if old == nil || next == nil {
+		return nil
+	}
+
+	oldVal := reflect.ValueOf(*old)
+	nextVal := reflect.ValueOf(*next)
+	t := oldVal.Type()
+
+	var diffs []ConfigDiff
+	for i := 0; i < t.NumField(); i++ {
+		ov := oldVal.Field(i).Interface()
+		nv := nextVal.Field(i).Interface()
+		if !reflect.DeepEqual(ov, nv) {
+			diffs = append(diffs, ConfigDiff{Field: t.Field(i).Name, Old: ov, New: nv})
+		}
+	}
+	return diffs
+}
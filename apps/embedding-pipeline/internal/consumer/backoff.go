@@ -0,0 +1,64 @@
+package consumer
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+)
+
+// simpleBackoff is a full-jitter exponential backoff: delay(attempt) picks
+// uniformly between 0 and initial*factor^(attempt-1), capped at max. attempt
+// is 1-indexed (the delay before the second try). Unlike the embedder's
+// cenkalti/backoff policy, this one is sized for retrying a single store
+// call a handful of times before giving up to the DLQ, not for a whole
+// request's worth of provider retries.
+type simpleBackoff struct {
+	initial time.Duration
+	factor  float64
+	max     time.Duration
+}
+
+func newSimpleBackoff(cfg config.RetryConfig) simpleBackoff {
+	return simpleBackoff{
+		initial: time.Duration(cfg.InitialBackoffMs) * time.Millisecond,
+		factor:  cfg.BackoffFactor,
+		max:     time.Duration(cfg.MaxBackoffMs) * time.Millisecond,
+	}
+}
+
+func (b simpleBackoff) Delay(attempt int) time.Duration {
+	d := float64(b.initial) * math.Pow(b.factor, float64(attempt-1))
+	if d > float64(b.max) {
+		d = float64(b.max)
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// StoreWithRetry calls store up to retry.MaxAttempts times, sleeping a
+// simpleBackoff delay between attempts and recording a retry metric for
+// each attempt beyond the first. It returns how many attempts were made
+// and the last error, which is nil iff store eventually succeeded.
+func StoreWithRetry(retry config.RetryConfig, stage string, store func() error) (int, error) {
+	maxAttempts := retry.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	delay := newSimpleBackoff(retry)
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = store(); err == nil {
+			return attempt, nil
+		}
+		if attempt < maxAttempts {
+			RecordRetry(stage)
+			time.Sleep(delay.Delay(attempt))
+		}
+	}
+	return maxAttempts, err
+}
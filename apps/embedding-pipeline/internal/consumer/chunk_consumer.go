@@ -5,26 +5,64 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 )
 
+// ChunkBatch is a Kafka poll's worth of chunks, paired one-to-one with the
+// topic each was read from and the raw message it was decoded from, so a
+// caller can embed and store the whole batch in one request and only
+// commit offsets once that succeeds.
+type ChunkBatch struct {
+	Chunks     []ChunkOutput
+	Topics     []string
+	Partitions []int32
+	Offsets    []int64
+	messages   []*kafka.Message
+}
+
 type ChunkKafkaConsumer struct {
-	consumer *kafka.Consumer
-	topics   []string
+	sessionMu sync.Mutex
+	session   *Session
+	topics    []string
+
+	// bootstrapServers, groupID, and autoOffsetReset mirror the values the
+	// live session was built from, so Reconfigure can tell whether a
+	// reloaded config actually changed anything before rebuilding it.
+	bootstrapServers string
+	groupID          string
+	autoOffsetReset  string
+	onRevoke         CleanupFunc
+
+	dlq             *DLQProducer
+	payloadResolver *PayloadResolver
 }
 
-func NewChunkKafkaConsumer(cfg config.KafkaConfig) (*ChunkKafkaConsumer, error) {
-	c, err := kafka.NewConsumer(&kafka.ConfigMap{
-		"bootstrap.servers": cfg.BootstrapServers,
-		"group.id":          cfg.GroupID + "-chunks",
-		"auto.offset.reset": cfg.AutoOffsetReset,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create consumer: %w", err)
-	}
+// SetDLQ attaches a DLQProducer so unparseable messages are preserved on a
+// dead-letter topic instead of being dropped. Safe to call at any time;
+// nil disables DLQ routing (the zero value for *ChunkKafkaConsumer).
+func (cc *ChunkKafkaConsumer) SetDLQ(dlq *DLQProducer) {
+	cc.dlq = dlq
+}
+
+// SetPayloadResolver attaches a PayloadResolver so a chunk whose body was
+// spilled to object storage is resolved back to inline text before
+// consumeOne returns it. Safe to call at any time; nil (the zero value)
+// means a PayloadRef chunk is treated as undecodable and routed to the DLQ.
+func (cc *ChunkKafkaConsumer) SetPayloadResolver(resolver *PayloadResolver) {
+	cc.payloadResolver = resolver
+}
 
+// NewChunkKafkaConsumer builds a consumer-group member with cooperative-
+// sticky rebalancing and manual offset commits. onRevoke runs when
+// partitions are about to be taken from this consumer, whether by a
+// rebalance or on shutdown, and should flush any buffer scoped to those
+// partitions (e.g. a Qdrant upsert buffer) before ownership moves
+// elsewhere; pass nil if there's nothing to flush.
+func NewChunkKafkaConsumer(cfg config.KafkaConfig, onRevoke CleanupFunc) (*ChunkKafkaConsumer, error) {
 	var chunkTopics []string
 	for _, topic := range cfg.Topic {
 		if topic != "logs" {
@@ -36,28 +74,106 @@ func NewChunkKafkaConsumer(cfg config.KafkaConfig) (*ChunkKafkaConsumer, error)
 		return nil, fmt.Errorf("no valid chunk topics found (excluding 'logs')")
 	}
 
-	if err := c.SubscribeTopics(chunkTopics, nil); err != nil {
-		c.Close()
-		return nil, fmt.Errorf("failed to subscribe to topics: %w", err)
+	groupID := cfg.GroupID + "-chunks"
+	session, err := NewSession(cfg.BootstrapServers, groupID, cfg.AutoOffsetReset, cfg.Security, chunkTopics, nil, onRevoke)
+	if err != nil {
+		return nil, err
 	}
 
 	log.Printf("Chunk consumer started for topics: %s", strings.Join(chunkTopics, ", "))
-	return &ChunkKafkaConsumer{consumer: c, topics: chunkTopics}, nil
+	return &ChunkKafkaConsumer{
+		session:          session,
+		topics:           chunkTopics,
+		bootstrapServers: cfg.BootstrapServers,
+		groupID:          groupID,
+		autoOffsetReset:  cfg.AutoOffsetReset,
+		onRevoke:         onRevoke,
+	}, nil
 }
 
-func (cc *ChunkKafkaConsumer) ConsumeChunk() (*ChunkOutput, string, error) {
-	msg, err := cc.consumer.ReadMessage(-1)
+// Reconfigure rebuilds the underlying consumer-group session if cfg.Kafka's
+// BootstrapServers, GroupID, AutoOffsetReset, or chunk topic list actually
+// changed, swapping it in under sessionMu so consumeOne always sees either
+// the old session or the fully-built new one, never a half-built one. The
+// DLQ producer, payload resolver, and onRevoke callback carry over
+// unchanged. If building the new session fails, the old one is left in
+// place and the error is returned so a Watcher can roll other subscribers
+// back.
+func (cc *ChunkKafkaConsumer) Reconfigure(cfg *config.Config) error {
+	var chunkTopics []string
+	for _, topic := range cfg.Kafka.Topic {
+		if topic != "logs" {
+			chunkTopics = append(chunkTopics, topic)
+		}
+	}
+	if len(chunkTopics) == 0 {
+		return fmt.Errorf("no valid chunk topics found (excluding 'logs')")
+	}
+
+	groupID := cfg.Kafka.GroupID + "-chunks"
+
+	cc.sessionMu.Lock()
+	defer cc.sessionMu.Unlock()
+
+	if cfg.Kafka.BootstrapServers == cc.bootstrapServers &&
+		groupID == cc.groupID &&
+		cfg.Kafka.AutoOffsetReset == cc.autoOffsetReset &&
+		equalTopics(chunkTopics, cc.topics) {
+		return nil
+	}
+
+	session, err := NewSession(cfg.Kafka.BootstrapServers, groupID, cfg.Kafka.AutoOffsetReset, cfg.Kafka.Security, chunkTopics, nil, cc.onRevoke)
 	if err != nil {
-		return nil, "", fmt.Errorf("read error: %w", err)
+		return fmt.Errorf("failed to rebuild chunk consumer session: %w", err)
 	}
 
-	if len(msg.Value) == 0 {
-		return nil, "", fmt.Errorf("empty message")
+	previous := cc.session
+	cc.session = session
+	cc.topics = chunkTopics
+	cc.bootstrapServers = cfg.Kafka.BootstrapServers
+	cc.groupID = groupID
+	cc.autoOffsetReset = cfg.Kafka.AutoOffsetReset
+
+	if previous != nil {
+		if err := previous.Close(); err != nil {
+			log.Printf("chunk consumer: failed to close previous session during reconfigure: %v", err)
+		}
 	}
 
-	var chunk ChunkOutput
-	if err := json.Unmarshal(msg.Value, &chunk); err != nil {
-		return nil, "", fmt.Errorf("unmarshal error: %w", err)
+	log.Printf("Chunk consumer reconfigured for topics: %s", strings.Join(chunkTopics, ", "))
+	return nil
+}
+
+// equalTopics reports whether a and b contain the same topics in the same
+// order, which is how both NewChunkKafkaConsumer and Reconfigure build
+// their topic lists from cfg.Kafka.Topic.
+func equalTopics(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// consumeOne polls for a single message and decodes it, returning
+// (nil, nil, nil) for an empty poll, an empty message, or one that fails to
+// unmarshal (which is also routed to the DLQ), so callers can just loop
+// until they get a real chunk or an error.
+func (cc *ChunkKafkaConsumer) consumeOne(timeout time.Duration) (*ChunkOutput, *kafka.Message, error) {
+	cc.sessionMu.Lock()
+	session := cc.session
+	cc.sessionMu.Unlock()
+
+	msg, err := session.Poll(timeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read error: %w", err)
+	}
+	if msg == nil || len(msg.Value) == 0 {
+		return nil, nil, nil
 	}
 
 	topic := ""
@@ -65,12 +181,125 @@ func (cc *ChunkKafkaConsumer) ConsumeChunk() (*ChunkOutput, string, error) {
 		topic = *msg.TopicPartition.Topic
 	}
 
-	return &chunk, topic, nil
+	value, err := unframeValue(msg.Value)
+	if err != nil {
+		log.Printf("skipping undecodable chunk: %v", err)
+		cc.deadLetter(topic, msg, err)
+		return nil, nil, nil
+	}
+
+	var chunk ChunkOutput
+	if err := json.Unmarshal(value, &chunk); err != nil {
+		log.Printf("skipping unparseable chunk: %v", err)
+		cc.deadLetter(topic, msg, err)
+		return nil, nil, nil
+	}
+
+	if chunk.PayloadRef != nil {
+		if cc.payloadResolver == nil {
+			resolveErr := fmt.Errorf("chunk has external payload_ref but no payload resolver is configured")
+			log.Printf("skipping chunk: %v", resolveErr)
+			cc.deadLetter(topic, msg, resolveErr)
+			return nil, nil, nil
+		}
+
+		text, err := cc.payloadResolver.Resolve(chunk.PayloadRef)
+		if err != nil {
+			log.Printf("skipping chunk: failed to resolve external payload: %v", err)
+			cc.deadLetter(topic, msg, err)
+			return nil, nil, nil
+		}
+		chunk.Text = text
+		chunk.PayloadRef = nil
+	}
+
+	return &chunk, msg, nil
+}
+
+// deadLetter publishes a poison message to its topic's DLQ if a DLQProducer
+// is attached, logging (rather than failing) on a DLQ publish error so a
+// Kafka blip doesn't also take down the consumer loop. With no DLQProducer
+// attached the message is simply dropped, which is recorded as such.
+func (cc *ChunkKafkaConsumer) deadLetter(topic string, msg *kafka.Message, cause error) {
+	if cc.dlq == nil || topic == "" {
+		RecordDropped("unmarshal")
+		return
+	}
+
+	failure := FailureMeta{Error: cause.Error(), Stage: "unmarshal", Attempts: 1}
+	if err := cc.dlq.Send(topic, msg.TopicPartition.Partition, int64(msg.TopicPartition.Offset), msg.Value, failure); err != nil {
+		log.Printf("failed to send poison message to DLQ for topic %s: %v", topic, err)
+	}
+}
+
+// ConsumeChunkBatch polls up to maxSize chunks, returning early once timeout
+// elapses so a batch doesn't stall waiting to fill up during a quiet
+// period. It blocks indefinitely for the first chunk. Offsets aren't
+// committed until the caller calls CommitBatch, so a crash between read and
+// commit is recovered by simply re-reading the batch.
+func (cc *ChunkKafkaConsumer) ConsumeChunkBatch(maxSize int, timeout time.Duration) (*ChunkBatch, error) {
+	if maxSize <= 0 {
+		maxSize = 1
+	}
+
+	var chunk *ChunkOutput
+	var msg *kafka.Message
+	for chunk == nil {
+		var err error
+		chunk, msg, err = cc.consumeOne(-1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	batch := &ChunkBatch{
+		Chunks:     []ChunkOutput{*chunk},
+		Topics:     []string{*msg.TopicPartition.Topic},
+		Partitions: []int32{msg.TopicPartition.Partition},
+		Offsets:    []int64{int64(msg.TopicPartition.Offset)},
+		messages:   []*kafka.Message{msg},
+	}
+
+	deadline := time.Now().Add(timeout)
+	for len(batch.Chunks) < maxSize {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
+		}
+
+		next, nextMsg, err := cc.consumeOne(remaining)
+		if err != nil {
+			break
+		}
+		if next == nil {
+			continue
+		}
+
+		batch.Chunks = append(batch.Chunks, *next)
+		batch.Topics = append(batch.Topics, *nextMsg.TopicPartition.Topic)
+		batch.Partitions = append(batch.Partitions, nextMsg.TopicPartition.Partition)
+		batch.Offsets = append(batch.Offsets, int64(nextMsg.TopicPartition.Offset))
+		batch.messages = append(batch.messages, nextMsg)
+	}
+
+	return batch, nil
+}
+
+// CommitBatch commits offsets for every message in batch. Call this only
+// after batch has been fully embedded and stored.
+func (cc *ChunkKafkaConsumer) CommitBatch(batch *ChunkBatch) error {
+	cc.sessionMu.Lock()
+	session := cc.session
+	cc.sessionMu.Unlock()
+	return session.CommitMessages(batch.messages)
 }
 
 func (cc *ChunkKafkaConsumer) Close() error {
-	if cc.consumer != nil {
-		return cc.consumer.Close()
+	cc.sessionMu.Lock()
+	session := cc.session
+	cc.sessionMu.Unlock()
+	if session != nil {
+		return session.Close()
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,166 @@
+package consumer
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	dlqSentTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dlq_sent_total",
+		Help: "Messages published to a dead-letter topic, by topic and failure stage.",
+	}, []string{"topic", "stage"})
+
+	dlqReplayedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dlq_replayed_total",
+		Help: "Messages replayed from a dead-letter topic back onto its source topic.",
+	}, []string{"topic"})
+
+	retryAttemptsBucket = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "retry_attempts_bucket",
+		Help:    "Attempts made before a message was sent to the DLQ, by failure stage.",
+		Buckets: prometheus.LinearBuckets(0, 1, 6),
+	}, []string{"stage"})
+
+	messagesRetriedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "messages_retried_total",
+		Help: "Retry attempts made against a failing store/embed call before it either succeeded or was dead-lettered.",
+	}, []string{"stage"})
+
+	messagesDroppedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "messages_dropped_total",
+		Help: "Messages discarded without a DLQ attempt (no DLQProducer attached).",
+	}, []string{"stage"})
+)
+
+// FailureMeta records why a message is being dead-lettered, so a dlq-replay
+// pass can filter by error class and an operator can tell a transient
+// embedding-provider blip apart from a message that will never parse.
+type FailureMeta struct {
+	Error     string    `json:"error"`
+	Stage     string    `json:"stage"` // "unmarshal", "embed", or "store"
+	Attempts  int       `json:"attempts"`
+	FirstSeen time.Time `json:"first_seen"`
+	Stack     string    `json:"stack,omitempty"`
+}
+
+// DLQRecord is the envelope published to the dead-letter topic: the
+// original bytes untouched, where they came from, why they ended up here,
+// and (once replayed) how many times.
+type DLQRecord struct {
+	SourceTopic     string          `json:"source_topic"`
+	SourcePartition int32           `json:"source_partition"`
+	SourceOffset    int64           `json:"source_offset"`
+	Message         json.RawMessage `json:"message"`
+	Failure         FailureMeta     `json:"failure"`
+	ReplayCount     int             `json:"replay_count,omitempty"`
+}
+
+// DLQProducer publishes poison/failed messages to a dead-letter topic so a
+// single bad message can't stall the main consumer loop.
+type DLQProducer struct {
+	producer *kafka.Producer
+	dlqTopic string // overrides the default "<source topic>.dlq" name when set
+}
+
+func NewDLQProducer(cfg config.KafkaConfig) (*DLQProducer, error) {
+	cm := &kafka.ConfigMap{
+		"bootstrap.servers": cfg.BootstrapServers,
+	}
+	if cfg.Compression != "" {
+		if err := cm.SetKey("compression.type", cfg.Compression); err != nil {
+			return nil, fmt.Errorf("failed to set compression.type: %w", err)
+		}
+	}
+
+	p, err := kafka.NewProducer(cm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DLQ producer: %w", err)
+	}
+
+	go func() {
+		for e := range p.Events() {
+			if m, ok := e.(*kafka.Message); ok && m.TopicPartition.Error != nil {
+				log.Printf("DLQ delivery failed: %v", m.TopicPartition.Error)
+			}
+		}
+	}()
+
+	return &DLQProducer{producer: p, dlqTopic: cfg.DLQTopic}, nil
+}
+
+func (d *DLQProducer) dlqTopicFor(sourceTopic string) string {
+	if d.dlqTopic != "" {
+		return d.dlqTopic
+	}
+	return sourceTopic + ".dlq"
+}
+
+// Send publishes original (the raw, unparsed message bytes where possible)
+// to sourceTopic's DLQ along with the failure that caused it to be
+// dead-lettered and, when known, the partition/offset it was read from.
+// Callers should still advance their own consumer offset after Send
+// succeeds, since the message has been durably preserved.
+func (d *DLQProducer) Send(sourceTopic string, partition int32, offset int64, original []byte, failure FailureMeta) error {
+	if failure.FirstSeen.IsZero() {
+		failure.FirstSeen = time.Now()
+	}
+
+	record := DLQRecord{
+		SourceTopic:     sourceTopic,
+		SourcePartition: partition,
+		SourceOffset:    offset,
+		Message:         json.RawMessage(original),
+		Failure:         failure,
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal DLQ record: %w", err)
+	}
+
+	topic := d.dlqTopicFor(sourceTopic)
+	if err := d.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Value:          data,
+	}, nil); err != nil {
+		return fmt.Errorf("failed to produce to DLQ topic %s: %w", topic, err)
+	}
+
+	dlqSentTotal.WithLabelValues(topic, failure.Stage).Inc()
+	retryAttemptsBucket.WithLabelValues(failure.Stage).Observe(float64(failure.Attempts))
+
+	return nil
+}
+
+// RecordReplay increments dlq_replayed_total for topic. It's exported so
+// the dlq-replay command shares this package's metric registration instead
+// of declaring a duplicate counter of its own.
+func RecordReplay(topic string) {
+	dlqReplayedTotal.WithLabelValues(topic).Inc()
+}
+
+// RecordRetry increments messages_retried_total for stage. Call it once
+// per retry attempt, not once per eventual success/failure.
+func RecordRetry(stage string) {
+	messagesRetriedTotal.WithLabelValues(stage).Inc()
+}
+
+// RecordDropped increments messages_dropped_total for stage. Call it when
+// a message is discarded without a DLQ attempt, e.g. no DLQProducer is
+// attached.
+func RecordDropped(stage string) {
+	messagesDroppedTotal.WithLabelValues(stage).Inc()
+}
+
+func (d *DLQProducer) Close() {
+	d.producer.Flush(5000)
+	d.producer.Close()
+}
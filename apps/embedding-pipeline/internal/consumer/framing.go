@@ -0,0 +1,155 @@
+package consumer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// frameMagic marks a message value as compressed by an Orbit producer: a
+// 4-byte magic prefix followed by a 1-byte codec ID precedes the
+// compressed payload, so a consumer can tell a compressed value apart from
+// plain JSON without a side channel (a Kafka header, a separate topic).
+var frameMagic = [4]byte{'O', 'R', 'B', '1'}
+
+// frameCodec identifies which compressor produced a framed payload.
+type frameCodec byte
+
+const (
+	codecNone frameCodec = iota
+	codecSnappy
+	codecZstd
+	codecLZ4
+)
+
+// parseFrameCodec maps a config.KafkaConfig.Compression value to its
+// frameCodec. An empty string or "none" both mean uncompressed.
+func parseFrameCodec(name string) (frameCodec, error) {
+	switch name {
+	case "", "none":
+		return codecNone, nil
+	case "snappy":
+		return codecSnappy, nil
+	case "zstd":
+		return codecZstd, nil
+	case "lz4":
+		return codecLZ4, nil
+	default:
+		return codecNone, fmt.Errorf("unsupported compression codec %q", name)
+	}
+}
+
+// frameValue compresses payload with codec and prepends frameMagic, so a
+// consumer can recognize and reverse it in unframeValue. codecNone returns
+// payload unchanged.
+func frameValue(codec frameCodec, payload []byte) ([]byte, error) {
+	if codec == codecNone {
+		return payload, nil
+	}
+
+	compressed, err := compressPayload(codec, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	framed := make([]byte, 0, len(frameMagic)+1+len(compressed))
+	framed = append(framed, frameMagic[:]...)
+	framed = append(framed, byte(codec))
+	framed = append(framed, compressed...)
+	return framed, nil
+}
+
+// unframeValue reverses frameValue. It also recognizes a bare Snappy
+// framing-format stream (github.com/golang/snappy's own framing, used by
+// non-Confluent Snappy producers) even without Orbit's frameMagic header,
+// so ConsumeChunkBatch can read either. Anything else is assumed to
+// already be plain JSON and is returned unchanged.
+func unframeValue(data []byte) ([]byte, error) {
+	if len(data) >= len(frameMagic)+1 && bytes.Equal(data[:len(frameMagic)], frameMagic[:]) {
+		codec := frameCodec(data[len(frameMagic)])
+		return decompressPayload(codec, data[len(frameMagic)+1:])
+	}
+
+	if isSnappyStream(data) {
+		decoded, err := io.ReadAll(snappy.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode snappy stream: %w", err)
+		}
+		return decoded, nil
+	}
+
+	return data, nil
+}
+
+// snappyStreamMagic is the stream identifier chunk every
+// github.com/golang/snappy framing-format stream starts with.
+const snappyStreamMagic = "\xff\x06\x00\x00sNaPpY"
+
+func isSnappyStream(data []byte) bool {
+	return len(data) >= len(snappyStreamMagic) && string(data[:len(snappyStreamMagic)]) == snappyStreamMagic
+}
+
+func compressPayload(codec frameCodec, payload []byte) ([]byte, error) {
+	switch codec {
+	case codecSnappy:
+		return snappy.Encode(nil, payload), nil
+
+	case codecZstd:
+		enc, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+		}
+		defer enc.Close()
+		return enc.EncodeAll(payload, nil), nil
+
+	case codecLZ4:
+		var buf bytes.Buffer
+		w := lz4.NewWriter(&buf)
+		if _, err := w.Write(payload); err != nil {
+			return nil, fmt.Errorf("failed to lz4-compress payload: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("failed to close lz4 writer: %w", err)
+		}
+		return buf.Bytes(), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported frame codec %d", codec)
+	}
+}
+
+func decompressPayload(codec frameCodec, payload []byte) ([]byte, error) {
+	switch codec {
+	case codecNone:
+		return payload, nil
+
+	case codecSnappy:
+		decoded, err := snappy.Decode(nil, payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode snappy frame: %w", err)
+		}
+		return decoded, nil
+
+	case codecZstd:
+		dec, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+		}
+		defer dec.Close()
+		return dec.DecodeAll(payload, nil)
+
+	case codecLZ4:
+		decoded, err := io.ReadAll(lz4.NewReader(bytes.NewReader(payload)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to lz4-decompress frame: %w", err)
+		}
+		return decoded, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported frame codec %d", codec)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
@@ -20,145 +21,334 @@ type LogChunk struct {
 	Details   map[string]interface{} `json:"details,omitempty"`
 }
 
+// LogBatch pairs a poll's worth of parsed log chunks with the raw messages
+// they came from, so the caller only commits offsets once the batch has
+// actually been embedded and stored.
+type LogBatch struct {
+	Chunks     []LogChunk
+	Partitions []int32
+	Offsets    []int64
+	messages   []*kafka.Message
+}
+
 type LogKafkaConsumer struct {
-	consumer *kafka.Consumer
-	topic    string
+	sessionMu sync.Mutex
+	session   *Session
+	topic     string
+
+	// bootstrapServers, groupID, and autoOffsetReset mirror the values the
+	// live session was built from, so Reconfigure can tell whether a
+	// reloaded config actually changed anything before rebuilding it.
+	bootstrapServers string
+	groupID          string
+	autoOffsetReset  string
+	onRevoke         CleanupFunc
+
+	dlq *DLQProducer
+}
+
+// SetDLQ attaches a DLQProducer so unparseable log records are preserved on
+// a dead-letter topic instead of being dropped. Safe to call at any time;
+// nil disables DLQ routing (the zero value for *LogKafkaConsumer).
+func (l *LogKafkaConsumer) SetDLQ(dlq *DLQProducer) {
+	l.dlq = dlq
 }
 
-func NewLogKafkaConsumer(cfg config.KafkaConfig) (*LogKafkaConsumer, error) {
-	c, err := kafka.NewConsumer(&kafka.ConfigMap{
-		"bootstrap.servers": cfg.BootstrapServers,
-		"group.id":          cfg.GroupID + "-logs",
-		"auto.offset.reset": cfg.AutoOffsetReset,
-	})
+// NewLogKafkaConsumer builds a consumer-group member with cooperative-
+// sticky rebalancing and manual offset commits. onRevoke runs when
+// partitions are about to be taken from this consumer, whether by a
+// rebalance or on shutdown, and should flush any buffer scoped to those
+// partitions; pass nil if there's nothing to flush.
+func NewLogKafkaConsumer(cfg config.KafkaConfig, onRevoke CleanupFunc) (*LogKafkaConsumer, error) {
+	groupID := cfg.GroupID + "-logs"
+	session, err := NewSession(cfg.BootstrapServers, groupID, cfg.AutoOffsetReset, cfg.Security, []string{"logs"}, nil, onRevoke)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create log consumer: %w", err)
 	}
 
-	if err := c.SubscribeTopics([]string{"logs"}, nil); err != nil {
-		c.Close()
-		return nil, fmt.Errorf("failed to subscribe to logs topic: %w", err)
+	log.Println("Log consumer started for topic: logs")
+	return &LogKafkaConsumer{
+		session:          session,
+		topic:            "logs",
+		bootstrapServers: cfg.BootstrapServers,
+		groupID:          groupID,
+		autoOffsetReset:  cfg.AutoOffsetReset,
+		onRevoke:         onRevoke,
+	}, nil
+}
+
+// Reconfigure rebuilds the underlying consumer-group session if cfg.Kafka's
+// BootstrapServers, GroupID, or AutoOffsetReset actually changed, swapping
+// it in under sessionMu so consumeOne always sees either the old session
+// or the fully-built new one. The DLQ producer and onRevoke callback carry
+// over unchanged. If building the new session fails, the old one is left
+// in place and the error is returned so a Watcher can roll other
+// subscribers back.
+func (l *LogKafkaConsumer) Reconfigure(cfg *config.Config) error {
+	groupID := cfg.Kafka.GroupID + "-logs"
+
+	l.sessionMu.Lock()
+	defer l.sessionMu.Unlock()
+
+	if cfg.Kafka.BootstrapServers == l.bootstrapServers &&
+		groupID == l.groupID &&
+		cfg.Kafka.AutoOffsetReset == l.autoOffsetReset {
+		return nil
 	}
 
-	log.Println("Log consumer started for topic: logs")
-	return &LogKafkaConsumer{consumer: c, topic: "logs"}, nil
+	session, err := NewSession(cfg.Kafka.BootstrapServers, groupID, cfg.Kafka.AutoOffsetReset, cfg.Kafka.Security, []string{"logs"}, nil, l.onRevoke)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild log consumer session: %w", err)
+	}
+
+	previous := l.session
+	l.session = session
+	l.bootstrapServers = cfg.Kafka.BootstrapServers
+	l.groupID = groupID
+	l.autoOffsetReset = cfg.Kafka.AutoOffsetReset
+
+	if previous != nil {
+		if err := previous.Close(); err != nil {
+			log.Printf("log consumer: failed to close previous session during reconfigure: %v", err)
+		}
+	}
+
+	log.Println("Log consumer reconfigured for topic: logs")
+	return nil
 }
 
+// consumeOne polls for a single message and parses it, returning
+// (nil, nil, nil) for an empty poll or a record with no usable message, so
+// callers can loop until they get a real log chunk or an error. A record
+// that fails to unmarshal as JSON is routed to the DLQ (if attached)
+// instead of just logged, so malformed input isn't silently discarded.
+func (l *LogKafkaConsumer) consumeOne(timeout time.Duration) (*LogChunk, *kafka.Message, error) {
+	l.sessionMu.Lock()
+	session := l.session
+	l.sessionMu.Unlock()
+
+	msg, err := session.Poll(timeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read error: %w", err)
+	}
+	if msg == nil || len(msg.Value) == 0 {
+		return nil, nil, nil
+	}
+
+	var fluentBitLog map[string]interface{}
+	if err := json.Unmarshal(msg.Value, &fluentBitLog); err != nil {
+		log.Printf("Error parsing JSON: %v", err)
+		l.deadLetter(msg, "unmarshal", err)
+		return nil, nil, nil
+	}
+
+	chunk := parseFluentBitLog(fluentBitLog)
+	if chunk == nil {
+		return nil, nil, nil
+	}
+
+	return chunk, msg, nil
+}
+
+// deadLetter publishes a poison log record to the DLQ if one is attached,
+// logging (rather than failing) on a publish error; with no DLQProducer
+// attached the record is dropped, which is recorded as such.
+func (l *LogKafkaConsumer) deadLetter(msg *kafka.Message, stage string, cause error) {
+	topic := l.topic
+	if msg.TopicPartition.Topic != nil {
+		topic = *msg.TopicPartition.Topic
+	}
+
+	if l.dlq == nil {
+		RecordDropped(stage)
+		return
+	}
+
+	failure := FailureMeta{Error: cause.Error(), Stage: stage, Attempts: 1}
+	if err := l.dlq.Send(topic, msg.TopicPartition.Partition, int64(msg.TopicPartition.Offset), msg.Value, failure); err != nil {
+		log.Printf("failed to send poison log record to DLQ for topic %s: %v", topic, err)
+	}
+}
+
+// ConsumeLogMessage blocks until a usable log chunk is parsed, discarding
+// anything unparseable along the way. Its offset isn't committed; prefer
+// ConsumeLogBatch, which commits once storage succeeds.
 func (l *LogKafkaConsumer) ConsumeLogMessage() (*LogChunk, error) {
 	for {
-		msg, err := l.consumer.ReadMessage(-1)
+		chunk, _, err := l.consumeOne(-1)
 		if err != nil {
 			log.Printf("Error reading log message: %v", err)
 			continue
 		}
-
-		if len(msg.Value) == 0 {
-			continue
-		}
-
-		var fluentBitLog map[string]interface{}
-		if err := json.Unmarshal(msg.Value, &fluentBitLog); err != nil {
-			log.Printf("Error parsing JSON: %v", err)
+		if chunk == nil {
 			continue
 		}
+		return chunk, nil
+	}
+}
 
-		var message string
-		var messageFound bool
+// parseFluentBitLog extracts a LogChunk from a decoded Fluent Bit record,
+// falling back from the "message" field to "log" (and, for bare "service: x"
+// lines, synthesizing a readable message). It returns nil when no usable
+// message can be recovered from the record.
+func parseFluentBitLog(fluentBitLog map[string]interface{}) *LogChunk {
+	var message string
+	var messageFound bool
 
-		if msgValue, ok := fluentBitLog["message"]; ok {
-			if msgStr, isString := msgValue.(string); isString && strings.TrimSpace(msgStr) != "" {
-				message = strings.TrimSpace(msgStr)
-				messageFound = true
-			}
+	if msgValue, ok := fluentBitLog["message"]; ok {
+		if msgStr, isString := msgValue.(string); isString && strings.TrimSpace(msgStr) != "" {
+			message = strings.TrimSpace(msgStr)
+			messageFound = true
 		}
+	}
 
-		if !messageFound {
-			if logValue, ok := fluentBitLog["log"]; ok {
-				if logStr, isString := logValue.(string); isString {
-					logStr = strings.TrimSpace(logStr)
-					if logStr != "" && logStr != "{" && logStr != "}" && logStr != "[" && logStr != "]" {
-						if strings.Contains(logStr, "service") && strings.Contains(logStr, ":") {
-							parts := strings.Split(logStr, ":")
-							if len(parts) >= 2 {
-								serviceName := strings.Trim(strings.TrimSpace(parts[1]), `"`)
-								if serviceName != "" {
-									message = fmt.Sprintf("Service event: %s", serviceName)
-									messageFound = true
-								}
+	if !messageFound {
+		if logValue, ok := fluentBitLog["log"]; ok {
+			if logStr, isString := logValue.(string); isString {
+				logStr = strings.TrimSpace(logStr)
+				if logStr != "" && logStr != "{" && logStr != "}" && logStr != "[" && logStr != "]" {
+					if strings.Contains(logStr, "service") && strings.Contains(logStr, ":") {
+						parts := strings.Split(logStr, ":")
+						if len(parts) >= 2 {
+							serviceName := strings.Trim(strings.TrimSpace(parts[1]), `"`)
+							if serviceName != "" {
+								message = fmt.Sprintf("Service event: %s", serviceName)
+								messageFound = true
 							}
-						} else {
-							message = logStr
-							messageFound = true
 						}
+					} else {
+						message = logStr
+						messageFound = true
 					}
 				}
 			}
 		}
+	}
 
-		if !messageFound || message == "" {
-			continue
-		}
+	if !messageFound || message == "" {
+		return nil
+	}
 
-		level, _ := fluentBitLog["level"].(string)
-		if level == "" {
-			level = "INFO"
-		}
+	level, _ := fluentBitLog["level"].(string)
+	if level == "" {
+		level = "INFO"
+	}
+
+	logType, _ := fluentBitLog["type"].(string)
+	if logType == "" {
+		logType = "system"
+	}
+
+	source, _ := fluentBitLog["source"].(string)
+	if source == "" {
+		source = "unknown"
+	}
 
-		logType, _ := fluentBitLog["type"].(string)
-		if logType == "" {
-			logType = "system"
+	timestamp := time.Now()
+	if originalTimestamp, ok := fluentBitLog["original_timestamp"].(string); ok {
+		if parsedTime, err := time.Parse("2006-01-02T15:04:05.000Z", originalTimestamp); err == nil {
+			timestamp = parsedTime
+		} else if parsedTime, err := time.Parse(time.RFC3339, originalTimestamp); err == nil {
+			timestamp = parsedTime
 		}
+	} else if tsValue, ok := fluentBitLog["@timestamp"]; ok {
+		switch ts := tsValue.(type) {
+		case float64:
+			timestamp = time.Unix(int64(ts), int64((ts-float64(int64(ts)))*1e9))
+		case int64:
+			timestamp = time.Unix(ts, 0)
+		}
+	}
 
-		source, _ := fluentBitLog["source"].(string)
-		if source == "" {
-			source = "unknown"
+	details := make(map[string]interface{})
+	for key, value := range fluentBitLog {
+		if strings.HasPrefix(key, "detail_") {
+			detailKey := strings.TrimPrefix(key, "detail_")
+			details[detailKey] = value
 		}
+	}
 
-		timestamp := time.Now()
-		if originalTimestamp, ok := fluentBitLog["original_timestamp"].(string); ok {
-			if parsedTime, err := time.Parse("2006-01-02T15:04:05.000Z", originalTimestamp); err == nil {
-				timestamp = parsedTime
-			} else if parsedTime, err := time.Parse(time.RFC3339, originalTimestamp); err == nil {
-				timestamp = parsedTime
-			}
-		} else if tsValue, ok := fluentBitLog["@timestamp"]; ok {
-			switch ts := tsValue.(type) {
-			case float64:
-				timestamp = time.Unix(int64(ts), int64((ts-float64(int64(ts)))*1e9))
-			case int64:
-				timestamp = time.Unix(ts, 0)
-			}
+	if collector, ok := fluentBitLog["collector"].(string); ok {
+		details["collector"] = collector
+	}
+	if logFilePath, ok := fluentBitLog["log_file_path"].(string); ok {
+		details["log_file_path"] = logFilePath
+	}
+
+	return &LogChunk{
+		Message:   message,
+		Timestamp: timestamp,
+		Level:     level,
+		Type:      logType,
+		Source:    source,
+		Details:   details,
+	}
+}
+
+// ConsumeLogBatch collects up to maxSize parsed log messages, returning
+// early once timeout elapses so a quiet topic doesn't stall a batch
+// indefinitely. It always blocks for at least one message. Offsets aren't
+// committed until the caller calls CommitBatch.
+func (l *LogKafkaConsumer) ConsumeLogBatch(maxSize int, timeout time.Duration) (*LogBatch, error) {
+	if maxSize <= 0 {
+		maxSize = 1
+	}
+
+	var first *LogChunk
+	var firstMsg *kafka.Message
+	for first == nil {
+		var err error
+		first, firstMsg, err = l.consumeOne(-1)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		details := make(map[string]interface{})
-		for key, value := range fluentBitLog {
-			if strings.HasPrefix(key, "detail_") {
-				detailKey := strings.TrimPrefix(key, "detail_")
-				details[detailKey] = value
-			}
+	batch := &LogBatch{
+		Chunks:     []LogChunk{*first},
+		Partitions: []int32{firstMsg.TopicPartition.Partition},
+		Offsets:    []int64{int64(firstMsg.TopicPartition.Offset)},
+		messages:   []*kafka.Message{firstMsg},
+	}
+
+	deadline := time.Now().Add(timeout)
+	for len(batch.Chunks) < maxSize {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
 		}
 
-		if collector, ok := fluentBitLog["collector"].(string); ok {
-			details["collector"] = collector
+		next, nextMsg, err := l.consumeOne(remaining)
+		if err != nil {
+			break
 		}
-		if logFilePath, ok := fluentBitLog["log_file_path"].(string); ok {
-			details["log_file_path"] = logFilePath
+		if next == nil {
+			continue
 		}
-
-		return &LogChunk{
-			Message:   message,
-			Timestamp: timestamp,
-			Level:     level,
-			Type:      logType,
-			Source:    source,
-			Details:   details,
-		}, nil
+		batch.Chunks = append(batch.Chunks, *next)
+		batch.Partitions = append(batch.Partitions, nextMsg.TopicPartition.Partition)
+		batch.Offsets = append(batch.Offsets, int64(nextMsg.TopicPartition.Offset))
+		batch.messages = append(batch.messages, nextMsg)
 	}
+
+	return batch, nil
+}
+
+// CommitBatch commits offsets for every message in batch. Call this only
+// after batch has been fully embedded and stored.
+func (l *LogKafkaConsumer) CommitBatch(batch *LogBatch) error {
+	l.sessionMu.Lock()
+	session := l.session
+	l.sessionMu.Unlock()
+	return session.CommitMessages(batch.messages)
 }
 
 func (l *LogKafkaConsumer) Close() error {
-	if l.consumer != nil {
-		return l.consumer.Close()
+	l.sessionMu.Lock()
+	session := l.session
+	l.sessionMu.Unlock()
+	if session != nil {
+		return session.Close()
 	}
 	return nil
-}
\ No newline at end of file
+}
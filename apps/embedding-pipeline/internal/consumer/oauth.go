@@ -0,0 +1,52 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// OAuthTokenSource keeps a rolling OAuth2 client-credentials token and
+// pushes it into a *kafka.Consumer via SetOAuthBearerToken whenever
+// librdkafka asks for a refresh, so a SASL/OAUTHBEARER connection never
+// presents an expired token.
+type OAuthTokenSource struct {
+	cfg *clientcredentials.Config
+}
+
+func NewOAuthTokenSource(cfg config.OAuthConfig) *OAuthTokenSource {
+	return &OAuthTokenSource{
+		cfg: &clientcredentials.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			TokenURL:     cfg.TokenURL,
+			Scopes:       cfg.Scopes,
+		},
+	}
+}
+
+// Refresh fetches a fresh token and pushes it into c. Call it once up
+// front so the initial connection has a token, and again every time c
+// raises a kafka.OAuthBearerTokenRefresh event.
+func (s *OAuthTokenSource) Refresh(c *kafka.Consumer) error {
+	token, err := s.cfg.Token(context.Background())
+	if err != nil {
+		c.SetOAuthBearerTokenFailure(err.Error())
+		return fmt.Errorf("failed to fetch OAuth2 token: %w", err)
+	}
+
+	expiry := token.Expiry
+	if expiry.IsZero() {
+		expiry = time.Now().Add(time.Hour)
+	}
+
+	return c.SetOAuthBearerToken(kafka.OAuthBearerToken{
+		TokenValue: token.AccessToken,
+		Expiration: expiry,
+		Principal:  s.cfg.ClientID,
+	})
+}
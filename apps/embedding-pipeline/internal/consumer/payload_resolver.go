@@ -0,0 +1,67 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// PayloadResolver fetches a chunk's Text from object storage when the
+// producer spilled it there instead of inlining it in the Kafka message,
+// so that resolution stays transparent to everything downstream of
+// ChunkKafkaConsumer.consumeOne.
+type PayloadResolver struct {
+	client *minio.Client
+}
+
+func NewPayloadResolver(cfg config.ObjectStoreConfig) (*PayloadResolver, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create object store client: %w", err)
+	}
+
+	return &PayloadResolver{client: client}, nil
+}
+
+// Resolve fetches the chunk body ref points at.
+func (r *PayloadResolver) Resolve(ref *PayloadRef) (string, error) {
+	bucket, key, err := parsePayloadURI(ref.PayloadURI)
+	if err != nil {
+		return "", err
+	}
+
+	obj, err := r.client.GetObject(context.Background(), bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch payload %s: %w", ref.PayloadURI, err)
+	}
+	defer obj.Close()
+
+	body, err := io.ReadAll(obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to read payload %s: %w", ref.PayloadURI, err)
+	}
+
+	return string(body), nil
+}
+
+func parsePayloadURI(uri string) (bucket, key string, err error) {
+	rest := strings.TrimPrefix(uri, "s3://")
+	if rest == uri {
+		return "", "", fmt.Errorf("unsupported payload URI scheme: %s", uri)
+	}
+
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("malformed payload URI: %s", uri)
+	}
+
+	return parts[0], parts[1], nil
+}
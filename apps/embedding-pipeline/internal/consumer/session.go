@@ -0,0 +1,228 @@
+package consumer
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// PartitionerBalanceStrategy decides which partition a keyed message belongs
+// on. Producers and the copartitioning logic here must agree on the same
+// strategy for a key (such as a document title) to reliably land on one
+// partition, and therefore one consumer-group worker.
+type PartitionerBalanceStrategy interface {
+	Partition(key string, numPartitions int32) int32
+}
+
+// DocumentPartitioner copartitions by document: every chunk carrying the
+// same key (chunk.Source.DocumentTitle, or an explicit key a producer sets)
+// hashes to the same partition, so one worker owns every chunk of a
+// document and can dedupe or order them without cross-worker coordination.
+type DocumentPartitioner struct{}
+
+func (DocumentPartitioner) Partition(key string, numPartitions int32) int32 {
+	if numPartitions <= 0 {
+		return 0
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int32(h.Sum32() % uint32(numPartitions))
+}
+
+// SetupFunc runs when partitions are assigned to this consumer, before any
+// message from them reaches Session.Poll.
+type SetupFunc func(partitions []kafka.TopicPartition) error
+
+// CleanupFunc runs when partitions are about to be revoked, whether by a
+// rebalance or on shutdown, so callers can flush any state scoped to those
+// partitions (e.g. a Qdrant buffer) before another worker starts reading
+// them and before the consumer loses ownership.
+type CleanupFunc func(partitions []kafka.TopicPartition) error
+
+// Session wraps a *kafka.Consumer configured for cooperative-sticky
+// rebalancing and manual offset commits. Cooperative-sticky means a
+// rebalance only reassigns the partitions that actually need to move
+// instead of stopping every consumer in the group, so a redeploy of one
+// replica doesn't pause the whole pipeline; Session's rebalance callback
+// turns librdkafka's incremental assign/revoke events into the Setup/
+// Cleanup hooks callers register.
+type Session struct {
+	consumer *kafka.Consumer
+	setup    SetupFunc
+	cleanup  CleanupFunc
+	oauth    *OAuthTokenSource
+}
+
+// NewSession creates the underlying consumer group member, subscribes it to
+// topics, and wires setup/cleanup into its rebalance events. Either hook may
+// be nil. security configures how the consumer authenticates to the
+// cluster; its zero value is plaintext, unauthenticated Kafka.
+func NewSession(bootstrapServers, groupID, autoOffsetReset string, security config.KafkaSecurityConfig, topics []string, setup SetupFunc, cleanup CleanupFunc) (*Session, error) {
+	cm := &kafka.ConfigMap{
+		"bootstrap.servers":               bootstrapServers,
+		"group.id":                        groupID,
+		"auto.offset.reset":               autoOffsetReset,
+		"enable.auto.commit":              false,
+		"partition.assignment.strategy":   "cooperative-sticky",
+		"go.application.rebalance.enable": true,
+	}
+	if err := applySecurity(cm, security); err != nil {
+		return nil, fmt.Errorf("failed to apply Kafka security config: %w", err)
+	}
+
+	c, err := kafka.NewConsumer(cm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session consumer: %w", err)
+	}
+
+	s := &Session{consumer: c, setup: setup, cleanup: cleanup}
+
+	if security.SASLMechanism == "OAUTHBEARER" {
+		s.oauth = NewOAuthTokenSource(security.OAuth)
+		if err := s.oauth.Refresh(c); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed initial OAuth2 token fetch: %w", err)
+		}
+	}
+
+	if err := c.SubscribeTopics(topics, s.rebalanceCb); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to subscribe to topics: %w", err)
+	}
+
+	return s, nil
+}
+
+// applySecurity translates security into the kafka.ConfigMap entries
+// librdkafka expects. A zero-value Protocol leaves cm untouched, so
+// existing plaintext callers don't need to change.
+func applySecurity(cm *kafka.ConfigMap, security config.KafkaSecurityConfig) error {
+	if security.Protocol == "" || security.Protocol == "plaintext" {
+		return nil
+	}
+
+	if err := cm.SetKey("security.protocol", security.Protocol); err != nil {
+		return err
+	}
+
+	if security.SASLMechanism != "" {
+		if err := cm.SetKey("sasl.mechanisms", security.SASLMechanism); err != nil {
+			return err
+		}
+	}
+
+	tls := security.TLS
+	if tls.CAFile != "" {
+		if err := cm.SetKey("ssl.ca.location", tls.CAFile); err != nil {
+			return err
+		}
+	}
+	if tls.CertFile != "" {
+		if err := cm.SetKey("ssl.certificate.location", tls.CertFile); err != nil {
+			return err
+		}
+	}
+	if tls.KeyFile != "" {
+		if err := cm.SetKey("ssl.key.location", tls.KeyFile); err != nil {
+			return err
+		}
+	}
+	if tls.InsecureSkipVerify {
+		if err := cm.SetKey("enable.ssl.certificate.verification", false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rebalanceCb adapts librdkafka's cooperative-sticky assign/revoke events
+// into the caller's Setup/Cleanup hooks plus the matching incremental
+// assign/unassign call cooperative rebalancing requires.
+func (s *Session) rebalanceCb(c *kafka.Consumer, ev kafka.Event) error {
+	switch e := ev.(type) {
+	case kafka.AssignedPartitions:
+		if s.setup != nil {
+			if err := s.setup(e.Partitions); err != nil {
+				log.Printf("session setup hook failed for %v: %v", e.Partitions, err)
+			}
+		}
+		return c.IncrementalAssign(e.Partitions)
+
+	case kafka.RevokedPartitions:
+		if s.cleanup != nil {
+			if err := s.cleanup(e.Partitions); err != nil {
+				log.Printf("session cleanup hook failed for %v: %v", e.Partitions, err)
+			}
+		}
+		if c.AssignmentLost() {
+			// Partitions were already reassigned elsewhere; there's nothing
+			// left for us to unassign.
+			return nil
+		}
+		return c.IncrementalUnassign(e.Partitions)
+	}
+
+	return nil
+}
+
+// Poll reads the next message, if any arrives before timeout. A negative
+// timeout blocks indefinitely, matching librdkafka's own convention.
+// Rebalance events that arrive in the meantime are handled by the rebalance
+// callback registered in NewSession and never reach the caller.
+func (s *Session) Poll(timeout time.Duration) (*kafka.Message, error) {
+	timeoutMs := -1
+	if timeout >= 0 {
+		timeoutMs = int(timeout.Milliseconds())
+	}
+
+	ev := s.consumer.Poll(timeoutMs)
+	if ev == nil {
+		return nil, nil
+	}
+
+	switch e := ev.(type) {
+	case *kafka.Message:
+		return e, nil
+	case kafka.Error:
+		return nil, fmt.Errorf("consumer error: %w", e)
+	case kafka.OAuthBearerTokenRefresh:
+		if s.oauth != nil {
+			if err := s.oauth.Refresh(s.consumer); err != nil {
+				log.Printf("OAuth2 token refresh failed: %v", err)
+			}
+		}
+		return nil, nil
+	default:
+		return nil, nil
+	}
+}
+
+// CommitMessages commits the offsets of msgs. Call this only once every
+// message in the batch has been embedded and stored, so a crash between
+// read and commit is recovered by re-reading the batch rather than by
+// silently losing it.
+func (s *Session) CommitMessages(msgs []*kafka.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	offsets := make([]kafka.TopicPartition, len(msgs))
+	for i, msg := range msgs {
+		tp := msg.TopicPartition
+		tp.Offset++
+		offsets[i] = tp
+	}
+
+	_, err := s.consumer.CommitOffsets(offsets)
+	return err
+}
+
+func (s *Session) Close() error {
+	return s.consumer.Close()
+}
@@ -1,9 +1,21 @@
 package consumer
 
 type ChunkOutput struct {
-	Text          string        `json:"text"`
+	Text          string        `json:"text,omitempty"`
 	Source        SourceInfo    `json:"source"`
 	ChunkMetadata ChunkMetadata `json:"chunk_metadata"`
+
+	// PayloadRef is set instead of Text when the producer spilled the
+	// chunk body to object storage. ChunkKafkaConsumer.consumeOne resolves
+	// it transparently before handing the chunk to a caller.
+	PayloadRef *PayloadRef `json:"payload_ref,omitempty"`
+}
+
+// PayloadRef points at a chunk body stored in an S3-compatible bucket.
+type PayloadRef struct {
+	PayloadURI string `json:"payload_uri"`
+	Size       int64  `json:"size"`
+	ETag       string `json:"etag"`
 }
 
 type SourceInfo struct {
@@ -0,0 +1,58 @@
+package dedup
+
+import (
+	"errors"
+	"fmt"
+
+	badger "github.com/dgraph-io/badger/v4"
+)
+
+// BadgerStore persists the content-hash -> point-ID mapping in a local
+// BadgerDB, for deployments that prefer Badger's LSM-tree storage over
+// BoltDB's B+tree (better write throughput at the cost of background
+// compaction).
+type BadgerStore struct {
+	db *badger.DB
+}
+
+func NewBadgerStore(path string) (*BadgerStore, error) {
+	db, err := badger.Open(badger.DefaultOptions(path).WithLogger(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Badger dedup store at %s: %w", path, err)
+	}
+
+	return &BadgerStore{db: db}, nil
+}
+
+func (s *BadgerStore) Get(hash string) (string, bool, error) {
+	var pointID string
+	var found bool
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(hash))
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return item.Value(func(value []byte) error {
+			pointID = string(value)
+			found = true
+			return nil
+		})
+	})
+
+	return pointID, found, err
+}
+
+func (s *BadgerStore) Put(hash, pointID string) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(hash), []byte(pointID))
+	})
+}
+
+func (s *BadgerStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,60 @@
+package dedup
+
+import (
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var bucketName = []byte("content_hashes")
+
+// BoltStore persists the content-hash -> point-ID mapping in a local BoltDB
+// file, for single-node deployments that don't want a round trip to Qdrant
+// just to check for a duplicate.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open BoltDB dedup store at %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketName)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create dedup bucket: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func (s *BoltStore) Get(hash string) (string, bool, error) {
+	var pointID string
+	var found bool
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(bucketName).Get([]byte(hash))
+		if value != nil {
+			pointID = string(value)
+			found = true
+		}
+		return nil
+	})
+
+	return pointID, found, err
+}
+
+func (s *BoltStore) Put(hash, pointID string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Put([]byte(hash), []byte(pointID))
+	})
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,114 @@
+// Package dedup lets the embedding pipeline recognize a chunk it has
+// already embedded and stored, so re-ingesting the same document doesn't
+// pay for a second embedding call or leave duplicate points in Qdrant.
+package dedup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// ContentHash is a stable SHA-256 digest over the parts of a chunk that
+// determine its embedding: normalized text, the document it came from, and
+// its position within that document. Two ingests of the same document
+// produce the same hash for the same chunk even if unrelated metadata
+// (timestamps, summaries) differs between runs.
+func ContentHash(text, documentTitle string, chunkIndex int) string {
+	normalized := strings.ToLower(strings.TrimSpace(text))
+
+	h := sha256.New()
+	h.Write([]byte(normalized))
+	h.Write([]byte{0})
+	h.Write([]byte(documentTitle))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.Itoa(chunkIndex)))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Store looks up and records the Qdrant point ID a content hash was last
+// embedded as. Implementations back either a local embedded database
+// (BoltStore, BadgerStore) or a lookup against Qdrant's own payload index
+// (storage.QdrantHashStore), so deployments can choose based on what they
+// already run.
+type Store interface {
+	Get(hash string) (pointID string, found bool, err error)
+	Put(hash, pointID string) error
+	Close() error
+}
+
+// Deduplicator checks a two-tier cache before falling through to the
+// (slower, possibly remote) persistent Store: an in-process LRU absorbs
+// the common case of reprocessing the same recent document, so most
+// lookups never leave the process.
+type Deduplicator struct {
+	lru        *lru.Cache[string, string]
+	persistent Store
+}
+
+// New wraps persistent with an in-process LRU of lruSize entries. Pass a nil
+// persistent to run LRU-only (e.g. for tests or a deployment that accepts
+// losing dedup state on restart).
+func New(persistent Store, lruSize int) (*Deduplicator, error) {
+	if lruSize <= 0 {
+		lruSize = 100_000
+	}
+
+	cache, err := lru.New[string, string](lruSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dedup LRU: %w", err)
+	}
+
+	return &Deduplicator{lru: cache, persistent: persistent}, nil
+}
+
+// Check reports whether hash has already been embedded, and if so, the
+// point ID it was stored as. A persistent-tier hit is promoted into the LRU
+// so the next lookup for the same hash doesn't leave the process.
+func (d *Deduplicator) Check(hash string) (pointID string, found bool, err error) {
+	if pointID, ok := d.lru.Get(hash); ok {
+		return pointID, true, nil
+	}
+
+	if d.persistent == nil {
+		return "", false, nil
+	}
+
+	pointID, found, err = d.persistent.Get(hash)
+	if err != nil {
+		return "", false, fmt.Errorf("dedup persistent lookup failed: %w", err)
+	}
+	if found {
+		d.lru.Add(hash, pointID)
+	}
+
+	return pointID, found, nil
+}
+
+// Record remembers that hash was embedded and stored as pointID, in both
+// the LRU and the persistent tier.
+func (d *Deduplicator) Record(hash, pointID string) error {
+	d.lru.Add(hash, pointID)
+
+	if d.persistent == nil {
+		return nil
+	}
+
+	if err := d.persistent.Put(hash, pointID); err != nil {
+		return fmt.Errorf("dedup persistent write failed: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Deduplicator) Close() error {
+	if d.persistent == nil {
+		return nil
+	}
+	return d.persistent.Close()
+}
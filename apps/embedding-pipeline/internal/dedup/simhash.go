@@ -0,0 +1,71 @@
+package dedup
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+)
+
+// shingleSize is k in the "k=5 shingles" scheme: a near-duplicate is
+// detected by comparing overlapping runs of shingleSize words rather than
+// whole-document text, so two documents differing only by a page
+// header/footer still hash close together.
+const shingleSize = 5
+
+// SimHash computes a 64-bit SimHash over text's shingles: each shingle is
+// hashed with FNV-64a, and each of the 64 bit positions sums +1 if that
+// bit is set in the shingle's hash or -1 if it isn't; the sign of each
+// position's running sum becomes the corresponding bit of the result. Two
+// texts that share most of their shingles end up with SimHashes a small
+// Hamming distance apart.
+func SimHash(text string) uint64 {
+	shingles := shinglesOf(strings.Fields(strings.ToLower(text)), shingleSize)
+	if len(shingles) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	for _, shingle := range shingles {
+		h := fnv.New64a()
+		h.Write([]byte(shingle))
+		sum := h.Sum64()
+
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<uint(bit)) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var result uint64
+	for bit := 0; bit < 64; bit++ {
+		if weights[bit] > 0 {
+			result |= 1 << uint(bit)
+		}
+	}
+	return result
+}
+
+// shinglesOf returns every overlapping run of k tokens. A text shorter
+// than k tokens is its own single shingle rather than producing none.
+func shinglesOf(tokens []string, k int) []string {
+	if len(tokens) == 0 {
+		return nil
+	}
+	if len(tokens) < k {
+		return []string{strings.Join(tokens, " ")}
+	}
+
+	shingles := make([]string, 0, len(tokens)-k+1)
+	for i := 0; i+k <= len(tokens); i++ {
+		shingles = append(shingles, strings.Join(tokens[i:i+k], " "))
+	}
+	return shingles
+}
+
+// HammingDistance counts the differing bits between two SimHashes.
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,66 @@
+package dedup
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var dedupHitsTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "dedup_hits_total",
+	Help: "Chunks matched to a near-duplicate via SimHash and stored as a reference instead of being re-embedded.",
+})
+
+// RecordHit increments dedup_hits_total. Call it once per chunk that's
+// skipped in favor of a near-duplicate already in the index.
+func RecordHit() {
+	dedupHitsTotal.Inc()
+}
+
+// simHashEntry is an indexed SimHash along with the point it was computed
+// for and the embedding that point was stored with, so a near-duplicate hit
+// can reuse that embedding instead of generating a new one.
+type simHashEntry struct {
+	hash    uint64
+	pointID string
+	vector  []float32
+}
+
+// SimHashIndex finds a near-duplicate of a SimHash within a Hamming
+// distance. Entries are bucketed by their hash's top 16 bits, so a lookup
+// only scans entries sharing that prefix instead of the whole index.
+type SimHashIndex struct {
+	mu      sync.Mutex
+	buckets map[uint16][]simHashEntry
+}
+
+func NewSimHashIndex() *SimHashIndex {
+	return &SimHashIndex{buckets: make(map[uint16][]simHashEntry)}
+}
+
+func bucketKey(hash uint64) uint16 {
+	return uint16(hash >> 48)
+}
+
+// Lookup returns the point ID and embedding of the first indexed hash
+// within maxDistance Hamming distance of hash, if any.
+func (idx *SimHashIndex) Lookup(hash uint64, maxDistance int) (pointID string, vector []float32, found bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, entry := range idx.buckets[bucketKey(hash)] {
+		if HammingDistance(hash, entry.hash) <= maxDistance {
+			return entry.pointID, entry.vector, true
+		}
+	}
+	return "", nil, false
+}
+
+// Add records hash as belonging to pointID, which was stored with vector.
+func (idx *SimHashIndex) Add(hash uint64, pointID string, vector []float32) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	key := bucketKey(hash)
+	idx.buckets[key] = append(idx.buckets[key], simHashEntry{hash: hash, pointID: pointID, vector: vector})
+}
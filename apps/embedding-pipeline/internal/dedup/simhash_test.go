@@ -0,0 +1,129 @@
+package dedup
+
+import "testing"
+
+func TestSimHashNearDuplicates(t *testing.T) {
+	base := "the quarterly financial report shows steady revenue growth across all regional divisions this year with particularly strong performance in the northeast and southwest territories driving overall profitability higher than analysts had originally projected for the fiscal period"
+
+	a := SimHash(base)
+	b := SimHash(base + " page 2 of 10")
+	c := SimHash("a stray cat wandered through the quiet garden looking for scraps of food near the old wooden fence")
+
+	if d := HammingDistance(a, b); d > 15 {
+		t.Errorf("near-duplicate texts (differing only by a trailing page marker) hashed too far apart: distance=%d", d)
+	}
+	if d := HammingDistance(a, c); d <= 15 {
+		t.Errorf("unrelated texts hashed too close together: distance=%d", d)
+	}
+}
+
+func TestSimHashEmptyText(t *testing.T) {
+	if got := SimHash(""); got != 0 {
+		t.Errorf("SimHash(\"\") = %d, want 0", got)
+	}
+	if got := SimHash("   "); got != 0 {
+		t.Errorf("SimHash of whitespace-only text = %d, want 0", got)
+	}
+}
+
+func TestSimHashDeterministic(t *testing.T) {
+	text := "deterministic hashing is required for dedup to work at all"
+	if SimHash(text) != SimHash(text) {
+		t.Error("SimHash is not deterministic for identical input")
+	}
+}
+
+func TestShinglesOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens []string
+		k      int
+		want   []string
+	}{
+		{
+			name:   "fewer tokens than k produces one shingle",
+			tokens: []string{"a", "b"},
+			k:      5,
+			want:   []string{"a b"},
+		},
+		{
+			name:   "exact overlap window",
+			tokens: []string{"a", "b", "c"},
+			k:      2,
+			want:   []string{"a b", "b c"},
+		},
+		{
+			name:   "no tokens",
+			tokens: []string{},
+			k:      5,
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shinglesOf(tt.tokens, tt.k)
+			if len(got) != len(tt.want) {
+				t.Fatalf("shinglesOf(%v, %d) = %v, want %v", tt.tokens, tt.k, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("shinglesOf(%v, %d)[%d] = %q, want %q", tt.tokens, tt.k, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b uint64
+		want int
+	}{
+		{name: "identical", a: 0xFF, b: 0xFF, want: 0},
+		{name: "all bits differ", a: 0x0, b: 0xFFFFFFFFFFFFFFFF, want: 64},
+		{name: "single bit", a: 0b1000, b: 0b0000, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HammingDistance(tt.a, tt.b); got != tt.want {
+				t.Errorf("HammingDistance(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimHashIndexLookup(t *testing.T) {
+	// Lookup only scans entries sharing a hash's top-16-bit bucket prefix,
+	// so these hashes are constructed to share that prefix (and thus land
+	// in the same bucket) rather than derived from SimHash of real text,
+	// which gives no such guarantee.
+	const base uint64 = 0x1234_0000_0000_0000
+	idx := NewSimHashIndex()
+	idx.Add(base, "point-1", []float32{1, 2, 3})
+
+	pointID, vector, found := idx.Lookup(base|0b111, 5)
+	if !found {
+		t.Fatal("expected a near-duplicate hit within the Hamming threshold")
+	}
+	if pointID != "point-1" {
+		t.Errorf("Lookup returned pointID %q, want %q", pointID, "point-1")
+	}
+	if len(vector) != 3 {
+		t.Errorf("Lookup returned vector %v, want the indexed embedding", vector)
+	}
+
+	_, _, found = idx.Lookup(base|0b1111111, 3)
+	if found {
+		t.Error("expected no hit once the Hamming distance exceeds maxDistance")
+	}
+
+	// A hash with a different bucket prefix never gets compared, even if
+	// it's otherwise within the Hamming threshold.
+	_, _, found = idx.Lookup(0xABCD_0000_0000_0000, 5)
+	if found {
+		t.Error("expected no hit for a hash outside the indexed bucket")
+	}
+}
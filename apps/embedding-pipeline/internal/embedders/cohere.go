@@ -0,0 +1,105 @@
+package embedders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+)
+
+const cohereEmbeddingDimensions = 1024
+
+// CohereEmbedder calls Cohere's /v1/embed endpoint, which natively accepts
+// a batch of texts per request.
+type CohereEmbedder struct {
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+func newCohereEmbedder(cfg config.EmbeddingConfig) (*CohereEmbedder, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("cohere embedder requires an api key")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = "embed-english-v3.0"
+	}
+
+	return &CohereEmbedder{
+		apiKey: cfg.APIKey,
+		model:  model,
+		client: &http.Client{},
+	}, nil
+}
+
+func (ce *CohereEmbedder) Dimensions() int {
+	return cohereEmbeddingDimensions
+}
+
+func (ce *CohereEmbedder) GenerateEmbedding(text string) ([]float32, error) {
+	embeddings, err := ce.GenerateEmbeddingsBatch([]string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+func (ce *CohereEmbedder) GenerateEmbeddingsBatch(texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	reqBody := map[string]interface{}{
+		"model":           ce.model,
+		"texts":           texts,
+		"input_type":      "search_document",
+		"embedding_types": []string{"float"},
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", "https://api.cohere.com/v1/embed", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+ce.apiKey)
+
+	resp, err := ce.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &providerError{statusCode: resp.StatusCode, retryAfter: resp.Header.Get("Retry-After"), body: string(body)}
+	}
+
+	var response struct {
+		Embeddings struct {
+			Float [][]float32 `json:"float"`
+		} `json:"embeddings"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %w", err)
+	}
+
+	if len(response.Embeddings.Float) != len(texts) {
+		return nil, fmt.Errorf("cohere returned %d embeddings for %d inputs", len(response.Embeddings.Float), len(texts))
+	}
+
+	return response.Embeddings.Float, nil
+}
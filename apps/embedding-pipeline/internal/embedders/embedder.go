@@ -0,0 +1,50 @@
+// Package embedders generates vector embeddings for text through a
+// pluggable set of providers, all wrapped with retry/backoff and request
+// coalescing so the consumer path doesn't have to worry about provider
+// rate limits or flaky HTTP calls.
+package embedders
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+)
+
+// Embedder generates vector embeddings for text. GenerateEmbeddingsBatch
+// should be preferred over repeated GenerateEmbedding calls wherever
+// possible, so a Kafka poll of N chunks becomes one embedding request
+// instead of N.
+type Embedder interface {
+	GenerateEmbedding(text string) ([]float32, error)
+	GenerateEmbeddingsBatch(texts []string) ([][]float32, error)
+	Dimensions() int
+}
+
+// NewEmbedder builds the Embedder selected by cfg.Provider, wrapped with
+// exponential backoff and singleflight coalescing of identical in-flight
+// requests.
+func NewEmbedder(cfg config.EmbeddingConfig) (Embedder, error) {
+	var (
+		base Embedder
+		err  error
+	)
+
+	switch strings.ToLower(cfg.Provider) {
+	case "", "gemini":
+		base, err = newGeminiEmbedder(cfg)
+	case "openai":
+		base, err = newOpenAIEmbedder(cfg)
+	case "cohere":
+		base, err = newCohereEmbedder(cfg)
+	case "local":
+		base, err = newLocalEmbedder(cfg)
+	default:
+		return nil, fmt.Errorf("unknown embedding provider %q", cfg.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newResilientEmbedder(base, cfg.MaxRetries), nil
+}
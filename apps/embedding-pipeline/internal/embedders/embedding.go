@@ -1,89 +0,0 @@
-package embedders
-
-import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-
-	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
-)
-
-type GoogleEmbedder struct {
-	apiKey string
-	model  string
-	client *http.Client
-}
-
-func NewGeminiEmbedderWithConfig(cfg config.GeminiConfig) (*GoogleEmbedder, error) {
-	return &GoogleEmbedder{
-		apiKey: cfg.APIKey,
-		model:  cfg.Model,
-		client: &http.Client{},
-	}, nil
-}
-
-func (ge *GoogleEmbedder) GenerateEmbedding(text string) ([]float32, error) {
-	if text == "" {
-		return nil, fmt.Errorf("input text cannot be empty")
-	}
-
-	apiURL := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:embedContent?key=%s",
-		ge.model, ge.apiKey)
-
-	reqBody := map[string]interface{}{
-		"content": map[string]interface{}{
-			"parts": []map[string]interface{}{
-				{
-					"text": text,
-				},
-			},
-		},
-		"taskType": "RETRIEVAL_DOCUMENT",
-	}
-
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("error marshaling request: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := ge.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var response struct {
-		Embedding struct {
-			Values []float32 `json:"values"`
-		} `json:"embedding"`
-	}
-
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshaling response: %w", err)
-	}
-
-	if len(response.Embedding.Values) == 0 {
-		return nil, fmt.Errorf("empty embedding returned")
-	}
-
-	return response.Embedding.Values, nil
-}
\ No newline at end of file
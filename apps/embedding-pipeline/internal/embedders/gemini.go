@@ -0,0 +1,159 @@
+package embedders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+)
+
+const geminiEmbeddingDimensions = 768
+
+// GoogleEmbedder calls the Gemini embedContent/batchEmbedContents APIs.
+type GoogleEmbedder struct {
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+func newGeminiEmbedder(cfg config.EmbeddingConfig) (*GoogleEmbedder, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("gemini embedder requires an api key")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = "models/text-embedding-004"
+	}
+
+	return &GoogleEmbedder{
+		apiKey: cfg.APIKey,
+		model:  model,
+		client: &http.Client{},
+	}, nil
+}
+
+// NewGeminiEmbedderWithConfig is kept for callers still constructing a
+// Gemini embedder directly from GeminiConfig rather than going through
+// NewEmbedder.
+func NewGeminiEmbedderWithConfig(cfg config.GeminiConfig) (*GoogleEmbedder, error) {
+	return newGeminiEmbedder(config.EmbeddingConfig{APIKey: cfg.APIKey, Model: cfg.Model})
+}
+
+func (ge *GoogleEmbedder) Dimensions() int {
+	return geminiEmbeddingDimensions
+}
+
+func (ge *GoogleEmbedder) GenerateEmbedding(text string) ([]float32, error) {
+	if text == "" {
+		return nil, fmt.Errorf("input text cannot be empty")
+	}
+
+	apiURL := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/%s:embedContent?key=%s",
+		ge.model, ge.apiKey)
+
+	reqBody := map[string]interface{}{
+		"content": map[string]interface{}{
+			"parts": []map[string]interface{}{
+				{"text": text},
+			},
+		},
+		"taskType": "RETRIEVAL_DOCUMENT",
+	}
+
+	var response struct {
+		Embedding struct {
+			Values []float32 `json:"values"`
+		} `json:"embedding"`
+	}
+
+	if err := ge.post(apiURL, reqBody, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Embedding.Values) == 0 {
+		return nil, fmt.Errorf("empty embedding returned")
+	}
+
+	return response.Embedding.Values, nil
+}
+
+// GenerateEmbeddingsBatch calls batchEmbedContents so a whole Kafka poll of
+// chunks becomes one HTTP round-trip instead of one per chunk.
+func (ge *GoogleEmbedder) GenerateEmbeddingsBatch(texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	apiURL := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/%s:batchEmbedContents?key=%s",
+		ge.model, ge.apiKey)
+
+	requests := make([]map[string]interface{}, len(texts))
+	for i, text := range texts {
+		requests[i] = map[string]interface{}{
+			"model": ge.model,
+			"content": map[string]interface{}{
+				"parts": []map[string]interface{}{
+					{"text": text},
+				},
+			},
+			"taskType": "RETRIEVAL_DOCUMENT",
+		}
+	}
+
+	var response struct {
+		Embeddings []struct {
+			Values []float32 `json:"values"`
+		} `json:"embeddings"`
+	}
+
+	if err := ge.post(apiURL, map[string]interface{}{"requests": requests}, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("batchEmbedContents returned %d embeddings for %d inputs", len(response.Embeddings), len(texts))
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for i, e := range response.Embeddings {
+		embeddings[i] = e.Values
+	}
+	return embeddings, nil
+}
+
+func (ge *GoogleEmbedder) post(url string, body interface{}, out interface{}) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ge.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &providerError{statusCode: resp.StatusCode, retryAfter: resp.Header.Get("Retry-After"), body: string(respBody)}
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("error unmarshaling response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package embedders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+)
+
+const localEmbeddingDimensions = 768
+
+// LocalEmbedder calls a self-hosted sentence-transformers/ONNX sidecar over
+// HTTP, for deployments that don't want to send text to a third-party API.
+type LocalEmbedder struct {
+	endpoint string
+	model    string
+	client   *http.Client
+}
+
+func newLocalEmbedder(cfg config.EmbeddingConfig) (*LocalEmbedder, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "http://localhost:8081/embed"
+	}
+
+	return &LocalEmbedder{
+		endpoint: endpoint,
+		model:    cfg.Model,
+		client:   &http.Client{},
+	}, nil
+}
+
+func (le *LocalEmbedder) Dimensions() int {
+	return localEmbeddingDimensions
+}
+
+func (le *LocalEmbedder) GenerateEmbedding(text string) ([]float32, error) {
+	embeddings, err := le.GenerateEmbeddingsBatch([]string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+func (le *LocalEmbedder) GenerateEmbeddingsBatch(texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	reqBody := map[string]interface{}{
+		"model": le.model,
+		"texts": texts,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", le.endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := le.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request to local embedding sidecar: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &providerError{statusCode: resp.StatusCode, retryAfter: resp.Header.Get("Retry-After"), body: string(body)}
+	}
+
+	var response struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %w", err)
+	}
+
+	if len(response.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("local embedding sidecar returned %d embeddings for %d inputs", len(response.Embeddings), len(texts))
+	}
+
+	return response.Embeddings, nil
+}
@@ -0,0 +1,118 @@
+package embedders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+)
+
+var openAIEmbeddingDimensions = map[string]int{
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+}
+
+// OpenAIEmbedder calls OpenAI's /v1/embeddings endpoint, which natively
+// accepts a batch of inputs per request.
+type OpenAIEmbedder struct {
+	apiKey     string
+	model      string
+	dimensions int
+	client     *http.Client
+}
+
+func newOpenAIEmbedder(cfg config.EmbeddingConfig) (*OpenAIEmbedder, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("openai embedder requires an api key")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = "text-embedding-3-small"
+	}
+
+	dimensions, ok := openAIEmbeddingDimensions[model]
+	if !ok {
+		dimensions = 1536
+	}
+
+	return &OpenAIEmbedder{
+		apiKey:     cfg.APIKey,
+		model:      model,
+		dimensions: dimensions,
+		client:     &http.Client{},
+	}, nil
+}
+
+func (oe *OpenAIEmbedder) Dimensions() int {
+	return oe.dimensions
+}
+
+func (oe *OpenAIEmbedder) GenerateEmbedding(text string) ([]float32, error) {
+	embeddings, err := oe.GenerateEmbeddingsBatch([]string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+func (oe *OpenAIEmbedder) GenerateEmbeddingsBatch(texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	reqBody := map[string]interface{}{
+		"model": oe.model,
+		"input": texts,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", "https://api.openai.com/v1/embeddings", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+oe.apiKey)
+
+	resp, err := oe.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &providerError{statusCode: resp.StatusCode, retryAfter: resp.Header.Get("Retry-After"), body: string(body)}
+	}
+
+	var response struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+			Index     int       `json:"index"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %w", err)
+	}
+
+	if len(response.Data) != len(texts) {
+		return nil, fmt.Errorf("openai returned %d embeddings for %d inputs", len(response.Data), len(texts))
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for _, d := range response.Data {
+		embeddings[d.Index] = d.Embedding
+	}
+	return embeddings, nil
+}
@@ -0,0 +1,152 @@
+package embedders
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"golang.org/x/sync/singleflight"
+)
+
+// providerError is returned by provider HTTP calls so the resilience layer
+// can tell a rate limit (retry, honoring Retry-After if present) apart from
+// a request that will never succeed.
+type providerError struct {
+	statusCode int
+	retryAfter string
+	body       string
+}
+
+func (e *providerError) Error() string {
+	return fmt.Sprintf("provider request failed with status %d: %s", e.statusCode, e.body)
+}
+
+func (e *providerError) isRetryable() bool {
+	return e.statusCode == http.StatusTooManyRequests || e.statusCode >= 500
+}
+
+// retryAfterDuration parses the Retry-After header, which providers send as
+// either a number of seconds or an HTTP date.
+func (e *providerError) retryAfterDuration() (time.Duration, bool) {
+	if e.retryAfter == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(e.retryAfter); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(e.retryAfter); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
+
+// resilientEmbedder wraps an Embedder with exponential backoff and jitter,
+// honors 429/Retry-After, and coalesces concurrent identical batch requests
+// via singleflight so a burst of duplicate work (e.g. reprocessing the same
+// Kafka batch after a rebalance) doesn't multiply provider calls.
+type resilientEmbedder struct {
+	inner      Embedder
+	maxRetries int
+	group      singleflight.Group
+}
+
+func newResilientEmbedder(inner Embedder, maxRetries int) *resilientEmbedder {
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+	return &resilientEmbedder{inner: inner, maxRetries: maxRetries}
+}
+
+func (r *resilientEmbedder) Dimensions() int {
+	return r.inner.Dimensions()
+}
+
+func (r *resilientEmbedder) GenerateEmbedding(text string) ([]float32, error) {
+	result, err, _ := r.group.Do(singleflightKey([]string{text}), func() (interface{}, error) {
+		return withRetry(r.maxRetries, func() ([]float32, error) {
+			return r.inner.GenerateEmbedding(text)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]float32), nil
+}
+
+func (r *resilientEmbedder) GenerateEmbeddingsBatch(texts []string) ([][]float32, error) {
+	result, err, _ := r.group.Do(singleflightKey(texts), func() (interface{}, error) {
+		return withRetry(r.maxRetries, func() ([][]float32, error) {
+			return r.inner.GenerateEmbeddingsBatch(texts)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([][]float32), nil
+}
+
+// withRetry runs fn with exponential backoff and jitter. A providerError
+// that isn't retryable (e.g. 400 bad request) stops retrying immediately;
+// a 429 with a Retry-After header waits at least that long before the next
+// attempt.
+func withRetry[T any](maxRetries int, fn func() (T, error)) (T, error) {
+	var result T
+
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = 500 * time.Millisecond
+	b.MaxInterval = 30 * time.Second
+	policy := backoff.WithMaxRetries(b, uint64(maxRetries))
+
+	op := func() error {
+		var err error
+		result, err = fn()
+		if err == nil {
+			return nil
+		}
+
+		if provErr, ok := err.(*providerError); ok {
+			if !provErr.isRetryable() {
+				return backoff.Permanent(err)
+			}
+			if wait, ok := provErr.retryAfterDuration(); ok {
+				b.NextBackOff()
+				return &retryAfterError{err: err, wait: wait}
+			}
+		}
+		return err
+	}
+
+	notify := func(err error, wait time.Duration) {
+		if raErr, ok := err.(*retryAfterError); ok {
+			time.Sleep(raErr.wait)
+		}
+	}
+
+	err := backoff.RetryNotify(op, policy, notify)
+	return result, err
+}
+
+type retryAfterError struct {
+	err  error
+	wait time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+// singleflightKey derives a stable key for a batch of texts so identical
+// concurrent requests (same texts, same order) are coalesced into one call.
+func singleflightKey(texts []string) string {
+	joined, err := json.Marshal(texts)
+	if err != nil {
+		return strings.Join(texts, "\x00")
+	}
+	sum := sha256.Sum256(joined)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,28 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileProvider resolves a reference as the name of a file under basePath,
+// matching the layout Docker and Kubernetes use when mounting secrets: one
+// file per key, directory per mount.
+type FileProvider struct {
+	basePath string
+}
+
+func NewFileProvider(basePath string) *FileProvider {
+	return &FileProvider{basePath: basePath}
+}
+
+func (p *FileProvider) Resolve(ref string) (string, error) {
+	path := filepath.Join(p.basePath, ref)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file %s: %w", path, err)
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
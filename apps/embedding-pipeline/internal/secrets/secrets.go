@@ -0,0 +1,49 @@
+// Package secrets resolves a credential reference (an environment
+// variable name, a mounted secret file, or a Vault KV v2 path) to its
+// plaintext value, so the embedding pipeline's config never has to hold
+// API keys in the clear. config.LoadConfig calls into this package for
+// every field tagged secret:"true" or prefixed "secret://".
+package secrets
+
+import "fmt"
+
+// Provider resolves a secret reference to its value. Implementations
+// don't interpret the reference's format beyond what their own backend
+// requires.
+type Provider interface {
+	Resolve(ref string) (string, error)
+}
+
+// BackendConfig selects and configures the active Provider. It's the
+// "secrets:" block of the application config.
+type BackendConfig struct {
+	// Backend selects the provider: "env" (the default), "file", or
+	// "vault".
+	Backend string `json:"backend"`
+
+	// FileBasePath is where FileProvider looks up a reference, joined as
+	// filepath.Join(FileBasePath, ref) — the layout Docker and Kubernetes
+	// secrets use when mounted as one file per key under a directory.
+	FileBasePath string `json:"file_base_path"`
+
+	// VaultAddr, VaultToken, and VaultMountPath configure VaultProvider's
+	// KV v2 lookups. VaultMountPath defaults to "secret", Vault's own
+	// default KV v2 mount name.
+	VaultAddr      string `json:"vault_addr"`
+	VaultToken     string `json:"vault_token"`
+	VaultMountPath string `json:"vault_mount_path"`
+}
+
+// New builds the Provider cfg.Backend selects.
+func New(cfg BackendConfig) (Provider, error) {
+	switch cfg.Backend {
+	case "", "env":
+		return NewEnvProvider(), nil
+	case "file":
+		return NewFileProvider(cfg.FileBasePath), nil
+	case "vault":
+		return NewVaultProvider(cfg.VaultAddr, cfg.VaultToken, cfg.VaultMountPath)
+	default:
+		return nil, fmt.Errorf("unknown secrets backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultVaultMountPath is Vault's own default mount name for a KV v2
+// secrets engine.
+const defaultVaultMountPath = "secret"
+
+// VaultProvider resolves a reference against a HashiCorp Vault KV v2
+// engine. A reference has the form "<path>#<field>", e.g.
+// "embedding-pipeline/gemini#api_key" resolves the "api_key" field of the
+// secret stored at "embedding-pipeline/gemini".
+type VaultProvider struct {
+	addr       string
+	token      string
+	mountPath  string
+	httpClient *http.Client
+}
+
+func NewVaultProvider(addr, token, mountPath string) (*VaultProvider, error) {
+	if addr == "" {
+		return nil, fmt.Errorf("vault secrets backend requires vault_addr")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("vault secrets backend requires vault_token")
+	}
+	if mountPath == "" {
+		mountPath = defaultVaultMountPath
+	}
+
+	return &VaultProvider{
+		addr:       strings.TrimRight(addr, "/"),
+		token:      token,
+		mountPath:  mountPath,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// vaultKV2Response mirrors the subset of Vault's KV v2 read response this
+// provider needs: the actual secret data is nested one level deeper than a
+// KV v1 engine would put it.
+type vaultKV2Response struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+func (p *VaultProvider) Resolve(ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok {
+		return "", fmt.Errorf("vault secret reference %q must be of the form \"<path>#<field>\"", ref)
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/data/%s", p.addr, p.mountPath, path)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault request for %s returned status %d", path, resp.StatusCode)
+	}
+
+	var decoded vaultKV2Response
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return "", fmt.Errorf("failed to decode vault response for %s: %w", path, err)
+	}
+
+	value, ok := decoded.Data.Data[field]
+	if !ok {
+		return "", fmt.Errorf("vault secret %s has no field %q", path, field)
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %s field %q is not a string", path, field)
+	}
+
+	return str, nil
+}
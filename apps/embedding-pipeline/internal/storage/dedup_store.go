@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// QdrantHashStore implements dedup.Store by looking content hashes up
+// directly in Qdrant's content_hash payload index instead of a separate
+// local database, for deployments that would rather not run BoltDB/Badger
+// alongside Qdrant.
+type QdrantHashStore struct {
+	client     *http.Client
+	baseURL    string
+	apiKey     string
+	collection string
+}
+
+func NewQdrantHashStore(dc *DocumentQdrantClient, collection string) *QdrantHashStore {
+	return &QdrantHashStore{
+		client:     dc.client,
+		baseURL:    dc.baseURL,
+		apiKey:     dc.apiKey,
+		collection: collection,
+	}
+}
+
+// Get scrolls for a single point whose content_hash payload field matches
+// hash, returning its point ID if one exists.
+func (s *QdrantHashStore) Get(hash string) (string, bool, error) {
+	reqBody := map[string]interface{}{
+		"filter": map[string]interface{}{
+			"must": []map[string]interface{}{
+				{"key": "content_hash", "match": map[string]interface{}{"value": hash}},
+			},
+		},
+		"limit":        1,
+		"with_payload": false,
+		"with_vector":  false,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", false, fmt.Errorf("error marshaling dedup lookup: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/points/scroll", s.baseURL, s.collection)
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("api-key", s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("error querying dedup lookup: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", false, fmt.Errorf("dedup lookup failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var out struct {
+		Result struct {
+			Points []struct {
+				ID interface{} `json:"id"`
+			} `json:"points"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", false, fmt.Errorf("error decoding dedup lookup response: %w", err)
+	}
+
+	if len(out.Result.Points) == 0 {
+		return "", false, nil
+	}
+
+	return fmt.Sprintf("%v", out.Result.Points[0].ID), true, nil
+}
+
+// Put is a no-op: a point already carries its own content_hash payload
+// field once stored, so there's nothing extra to persist here.
+func (s *QdrantHashStore) Put(hash, pointID string) error {
+	return nil
+}
+
+func (s *QdrantHashStore) Close() error {
+	return nil
+}
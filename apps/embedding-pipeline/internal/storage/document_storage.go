@@ -1,35 +1,89 @@
 package storage
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
 	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/consumer"
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/dedup"
+	"github.com/cenkalti/backoff/v4"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 type DocumentQdrantClient struct {
-	baseURL       string
-	apiKey        string
-	client        *http.Client
-	collections   map[string]string 
-	
-	documentBuffer        []consumer.EnrichedChunk
+	baseURL     string
+	apiKey      string
+	client      *http.Client
+	collections map[string]string
+
+	// sparseEncoder is non-nil when hybrid dense+sparse search is enabled,
+	// in which case collections get a named "sparse" vector alongside the
+	// named "dense" vector every collection has.
+	sparseEncoder SparseEncoder
+
+	// dedup is non-nil when content-hash deduplication is enabled. On a
+	// hit, storeDocumentChunksInCollection stores a lightweight reference
+	// point instead of a full duplicate.
+	dedup           *dedup.Deduplicator
+	dedupCollection string
+
+	documentBuffer        []bufferedChunk
 	documentBufferMu      sync.Mutex
 	documentBufferSize    int
 	documentFlushTimer    *time.Timer
 	documentFlushInterval time.Duration
+
+	// deadLetterDir, if non-empty, is where a document batch is persisted
+	// as JSONL after storeDocumentChunksInCollection exhausts its retries,
+	// so it can be inspected and replayed with ReplayDeadLetter.
+	deadLetterDir string
+
+	upsertAttemptsTotal    prometheus.Counter
+	upsertFailuresTotal    prometheus.Counter
+	deadletterWrittenTotal prometheus.Counter
+}
+
+// bufferedChunk pairs a chunk awaiting upsert with the deterministic point
+// ID it was assigned when added to the buffer, so a retried or replayed
+// upsert writes to the same point instead of minting a new one.
+type bufferedChunk struct {
+	chunk   consumer.EnrichedChunk
+	pointID string
 }
 
+// VectorSpec describes one named vector in a Qdrant collection. Dense
+// vectors set Size/Distance; sparse vectors set Sparse and leave
+// Size/Distance zero, since Qdrant's sparse vectors have no fixed dimension.
+type VectorSpec struct {
+	Size     int    `json:"size,omitempty"`
+	Distance string `json:"distance,omitempty"`
+	Sparse   bool   `json:"-"`
+}
+
+// VectorsSpec maps a named vector (e.g. "dense", "sparse") to its spec, for
+// collections that carry more than one vector per point.
+type VectorsSpec map[string]VectorSpec
+
+// QdrantPoint's Vector is either a []float32 (a single anonymous vector) or
+// a map[string]interface{} of named vectors (dense []float32 values and/or
+// sparse SparseVector payloads), matching whichever shape the collection
+// was created with.
 type QdrantPoint struct {
-	ID      interface{}            `json:"id"` 
-	Vector  []float32              `json:"vector"`
+	ID      interface{}            `json:"id"`
+	Vector  interface{}            `json:"vector"`
 	Payload map[string]interface{} `json:"payload"`
 }
 
@@ -44,30 +98,150 @@ type QdrantResponse struct {
 }
 
 func NewDocumentQdrantClientWithConfig(cfg config.QdrantConfig) (*DocumentQdrantClient, error) {
+	httpClient, err := newHTTPClient(cfg.TLS, cfg.OAuth, 30*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Qdrant HTTP client: %w", err)
+	}
+
 	client := &DocumentQdrantClient{
-		baseURL:       cfg.URL,
-		apiKey:        cfg.APIKey,
-		collections:   cfg.Collections,
-		documentBufferSize:    50, 
+		baseURL:               cfg.URL,
+		apiKey:                cfg.APIKey,
+		collections:           cfg.Collections,
+		documentBufferSize:    50,
 		documentFlushInterval: 5 * time.Second,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client:                httpClient,
+		deadLetterDir:         cfg.DeadLetterDir,
+		upsertAttemptsTotal: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "qdrant_upsert_attempts_total",
+			Help: "Attempts made to upsert a document batch into Qdrant, including retries.",
+		}),
+		upsertFailuresTotal: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "qdrant_upsert_failures_total",
+			Help: "Document batch upserts that failed after exhausting retries.",
+		}),
+		deadletterWrittenTotal: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "qdrant_deadletter_written_total",
+			Help: "Document chunks persisted to the dead-letter directory after a batch upsert failed permanently.",
+		}),
+	}
+
+	if cfg.HybridSearch {
+		encoder, err := NewBM25Encoder(cfg.BM25StatsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create BM25 sparse encoder: %w", err)
+		}
+		client.sparseEncoder = encoder
 	}
 
+	var dedupCollection string
 	for topic, collectionName := range cfg.Collections {
 		if topic != "logs" {
-			err := client.createCollectionIfNotExistsWithSize(collectionName, cfg.VectorSize)
+			err := client.createCollectionIfNotExistsWithVectors(collectionName, client.vectorsSpec(cfg.VectorSize))
 			if err != nil {
 				return nil, fmt.Errorf("failed to create collection %s for topic %s: %w", collectionName, topic, err)
 			}
+			if dedupCollection == "" {
+				dedupCollection = collectionName
+			}
 		}
 	}
+	client.dedupCollection = dedupCollection
+
+	if cfg.DedupEnabled {
+		persistent, err := newDedupPersistentStore(cfg, client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dedup store: %w", err)
+		}
+
+		deduplicator, err := dedup.New(persistent, cfg.DedupLRUSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create deduplicator: %w", err)
+		}
+		client.dedup = deduplicator
+	}
 
 	return client, nil
 }
 
-func (dc *DocumentQdrantClient) createCollectionIfNotExistsWithSize(collectionName string, vectorSize int) error {
+// newDedupPersistentStore builds the persistent tier backing dedup
+// lookups, per cfg.DedupBackend.
+func newDedupPersistentStore(cfg config.QdrantConfig, client *DocumentQdrantClient) (dedup.Store, error) {
+	switch cfg.DedupBackend {
+	case "", "qdrant":
+		return NewQdrantHashStore(client, client.dedupCollection), nil
+	case "bolt":
+		return dedup.NewBoltStore(cfg.DedupPath)
+	case "badger":
+		return dedup.NewBadgerStore(cfg.DedupPath)
+	default:
+		return nil, fmt.Errorf("unknown dedup backend %q", cfg.DedupBackend)
+	}
+}
+
+// vectorsSpec builds the named-vector layout for a document collection:
+// always a "dense" vector, plus a "sparse" vector when hybrid search is on.
+func (dc *DocumentQdrantClient) vectorsSpec(vectorSize int) VectorsSpec {
+	spec := VectorsSpec{
+		"dense": {Size: vectorSize, Distance: "Cosine"},
+	}
+	if dc.sparseEncoder != nil {
+		spec["sparse"] = VectorSpec{Sparse: true}
+	}
+	return spec
+}
+
+// collectionInfoResponse is the subset of GET /collections/{name} this
+// package inspects: just enough to tell a legacy single-anonymous-vector
+// collection apart from one already using named vectors.
+type collectionInfoResponse struct {
+	Result struct {
+		Config struct {
+			Params struct {
+				Vectors json.RawMessage `json:"vectors"`
+			} `json:"params"`
+		} `json:"config"`
+	} `json:"result"`
+}
+
+// checkCollectionSchema rejects reusing a collection that predates named
+// vectors: Qdrant represents a single anonymous vector's config as a JSON
+// object with "size"/"distance" fields directly under "vectors", while a
+// named-vectors collection nests those under per-name keys. There's no safe
+// way to add named vectors to an existing collection in place, so this
+// errors loudly instead of silently storing mismatched points; operators
+// migrating an old collection should recreate it under a new, versioned
+// collection name.
+func checkCollectionSchema(body []byte, spec VectorsSpec) error {
+	if len(spec) == 0 {
+		return nil
+	}
+
+	var info collectionInfoResponse
+	if err := json.Unmarshal(body, &info); err != nil {
+		return fmt.Errorf("failed to parse collection info: %w", err)
+	}
+
+	var legacy struct {
+		Size     int    `json:"size"`
+		Distance string `json:"distance"`
+	}
+	if err := json.Unmarshal(info.Result.Config.Params.Vectors, &legacy); err == nil && legacy.Size > 0 {
+		return fmt.Errorf("existing collection uses a legacy single anonymous vector (size %d), but this client expects named vectors %v; recreate the collection under a new, versioned name to adopt hybrid search", legacy.Size, vectorNames(spec))
+	}
+
+	return nil
+}
+
+func vectorNames(spec VectorsSpec) []string {
+	names := make([]string, 0, len(spec))
+	for name := range spec {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (dc *DocumentQdrantClient) createCollectionIfNotExistsWithVectors(collectionName string, spec VectorsSpec) error {
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/collections/%s", dc.baseURL, collectionName), nil)
 	if err != nil {
 		return err
@@ -84,19 +258,33 @@ func (dc *DocumentQdrantClient) createCollectionIfNotExistsWithSize(collectionNa
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		if err := checkCollectionSchema(body, spec); err != nil {
+			return fmt.Errorf("collection %s: %w", collectionName, err)
+		}
 		fmt.Printf("Collection '%s' already exists\n", collectionName)
-		return nil 
+		return nil
 	}
-	
+
 	if resp.StatusCode != http.StatusNotFound {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("unexpected status checking collection %s: %d, %s", collectionName, resp.StatusCode, string(body))
 	}
-	
-	createReq := map[string]interface{}{
-		"vectors": map[string]interface{}{
-			"size":     vectorSize,
-			"distance": "Cosine",
+
+	denseVectors := make(map[string]interface{})
+	sparseVectors := make(map[string]interface{})
+
+	for name, vs := range spec {
+		if vs.Sparse {
+			sparseVectors[name] = map[string]interface{}{
+				"modifier": "idf",
+			}
+			continue
+		}
+
+		denseVectors[name] = map[string]interface{}{
+			"size":     vs.Size,
+			"distance": vs.Distance,
 			"hnsw_config": map[string]interface{}{
 				"m":            16,
 				"ef_construct": 200,
@@ -108,7 +296,14 @@ func (dc *DocumentQdrantClient) createCollectionIfNotExistsWithSize(collectionNa
 				},
 			},
 			"on_disk": true,
-		},
+		}
+	}
+
+	createReq := map[string]interface{}{
+		"vectors": denseVectors,
+	}
+	if len(sparseVectors) > 0 {
+		createReq["sparse_vectors"] = sparseVectors
 	}
 
 	jsonData, err := json.Marshal(createReq)
@@ -136,13 +331,17 @@ func (dc *DocumentQdrantClient) createCollectionIfNotExistsWithSize(collectionNa
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("failed to create collection %s (status %d): %s", collectionName, resp.StatusCode, string(body))
 	}
-	
-	fmt.Printf("Successfully created collection '%s' with vector size %d\n", collectionName, vectorSize)
+
+	fmt.Printf("Successfully created collection '%s' with vectors %v\n", collectionName, spec)
 	return nil
 }
 
-func (dc *DocumentQdrantClient) generateValidPointID() string {
-	return uuid.New().String()
+// generatePointID derives a deterministic Qdrant point ID from the chunk's
+// content hash, so re-adding the same chunk to the buffer (e.g. after a
+// retry or a replay) always targets the same point instead of minting a
+// fresh one and risking a duplicate.
+func (dc *DocumentQdrantClient) generatePointID(enrichedChunk consumer.EnrichedChunk) string {
+	return uuid.NewSHA1(uuid.Nil, []byte(dc.contentHash(enrichedChunk))).String()
 }
 
 func (dc *DocumentQdrantClient) createDocumentPayload(enrichedChunk consumer.EnrichedChunk) map[string]interface{} {
@@ -163,6 +362,7 @@ func (dc *DocumentQdrantClient) createDocumentPayload(enrichedChunk consumer.Enr
 		"chunk_index":    enrichedChunk.ChunkMetadata.ChunkIndex,
 		"timestamp":      enrichedChunk.ChunkMetadata.Timestamp,
 		"kafka_topic":    enrichedChunk.KafkaTopic,
+		"content_hash":   dc.contentHash(enrichedChunk),
 	}
 
 	if enrichedChunk.Source.PageNumber != nil {
@@ -176,6 +376,45 @@ func (dc *DocumentQdrantClient) createDocumentPayload(enrichedChunk consumer.Enr
 	return payload
 }
 
+// buildVector returns the named "dense" embedding alone when hybrid search
+// is disabled, or a named "dense"/"sparse" map so Qdrant's Query API can fuse
+// both signals (RRF or weighted sum) at search time.
+func (dc *DocumentQdrantClient) buildVector(enrichedChunk consumer.EnrichedChunk) (interface{}, error) {
+	if dc.sparseEncoder == nil {
+		return map[string]interface{}{"dense": enrichedChunk.Embedding}, nil
+	}
+
+	sparse, err := dc.sparseEncoder.Encode(enrichedChunk.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"dense": enrichedChunk.Embedding,
+		"sparse": map[string]interface{}{
+			"indices": sparse.Indices,
+			"values":  sparse.Values,
+		},
+	}, nil
+}
+
+func (dc *DocumentQdrantClient) contentHash(enrichedChunk consumer.EnrichedChunk) string {
+	return dedup.ContentHash(enrichedChunk.Text, enrichedChunk.Source.DocumentTitle, enrichedChunk.ChunkMetadata.ChunkIndex)
+}
+
+// referencePayload builds a minimal payload for a dedup-hit point: enough
+// to trace this occurrence back to its source document without storing the
+// text or metadata again.
+func referencePayload(enrichedChunk consumer.EnrichedChunk, hash, duplicateOf string) map[string]interface{} {
+	return map[string]interface{}{
+		"document_title": enrichedChunk.Source.DocumentTitle,
+		"chunk_index":    enrichedChunk.ChunkMetadata.ChunkIndex,
+		"kafka_topic":    enrichedChunk.KafkaTopic,
+		"content_hash":   hash,
+		"duplicate_of":   duplicateOf,
+	}
+}
+
 func (dc *DocumentQdrantClient) getCollectionForTopic(kafkaTopic string) string {
 	if collectionName, exists := dc.collections[kafkaTopic]; exists {
 		return collectionName
@@ -184,11 +423,11 @@ func (dc *DocumentQdrantClient) getCollectionForTopic(kafkaTopic string) string
 }
 
 func (dc *DocumentQdrantClient) AddDocumentToBuffer(enrichedChunk consumer.EnrichedChunk) error {
+	buffered := bufferedChunk{chunk: enrichedChunk, pointID: dc.generatePointID(enrichedChunk)}
+
 	dc.documentBufferMu.Lock()
-	defer dc.documentBufferMu.Unlock()
-	
-	dc.documentBuffer = append(dc.documentBuffer, enrichedChunk)
-	
+	dc.documentBuffer = append(dc.documentBuffer, buffered)
+
 	if dc.documentFlushTimer != nil {
 		dc.documentFlushTimer.Stop()
 	}
@@ -197,51 +436,69 @@ func (dc *DocumentQdrantClient) AddDocumentToBuffer(enrichedChunk consumer.Enric
 			fmt.Printf("Error flushing document buffer: %v\n", err)
 		}
 	})
-	
+
+	var toFlush []bufferedChunk
 	if len(dc.documentBuffer) >= dc.documentBufferSize {
-		return dc.flushDocumentBufferLocked()
+		toFlush = dc.snapshotAndClearDocumentBufferLocked()
 	}
-	
-	return nil
+	dc.documentBufferMu.Unlock()
+
+	if toFlush == nil {
+		return nil
+	}
+	return dc.storeDocumentsInternal(context.Background(), toFlush)
 }
 
 func (dc *DocumentQdrantClient) flushDocumentBuffer() error {
 	dc.documentBufferMu.Lock()
-	defer dc.documentBufferMu.Unlock()
-	return dc.flushDocumentBufferLocked()
+	toFlush := dc.snapshotAndClearDocumentBufferLocked()
+	dc.documentBufferMu.Unlock()
+
+	if toFlush == nil {
+		return nil
+	}
+	return dc.storeDocumentsInternal(context.Background(), toFlush)
 }
 
-func (dc *DocumentQdrantClient) flushDocumentBufferLocked() error {
+// snapshotAndClearDocumentBufferLocked copies out the current buffer and
+// resets it in place, so the outbound HTTP upsert can run without holding
+// documentBufferMu for the whole round trip (previously up to 30s,
+// blocking every concurrent AddDocumentToBuffer caller). The caller must
+// hold documentBufferMu and must not call it again before releasing the
+// lock.
+func (dc *DocumentQdrantClient) snapshotAndClearDocumentBufferLocked() []bufferedChunk {
 	if len(dc.documentBuffer) == 0 {
 		return nil
 	}
-	
-	chunks := make([]consumer.EnrichedChunk, len(dc.documentBuffer))
+
+	chunks := make([]bufferedChunk, len(dc.documentBuffer))
 	copy(chunks, dc.documentBuffer)
 	dc.documentBuffer = dc.documentBuffer[:0]
-	
+
 	if dc.documentFlushTimer != nil {
 		dc.documentFlushTimer.Stop()
 	}
-	
-	return dc.storeDocumentsInternal(chunks)
+
+	return chunks
 }
 
-func (dc *DocumentQdrantClient) storeDocumentsInternal(enrichedChunks []consumer.EnrichedChunk) error {
-	if len(enrichedChunks) == 0 {
+func (dc *DocumentQdrantClient) storeDocumentsInternal(ctx context.Context, chunks []bufferedChunk) error {
+	if len(chunks) == 0 {
 		return nil
 	}
 
-	collectionGroups := make(map[string][]consumer.EnrichedChunk)
-	
-	for _, chunk := range enrichedChunks {
-		collectionName := dc.getCollectionForTopic(chunk.KafkaTopic)
-		collectionGroups[collectionName] = append(collectionGroups[collectionName], chunk)
+	collectionGroups := make(map[string][]bufferedChunk)
+
+	for _, bc := range chunks {
+		collectionName := dc.getCollectionForTopic(bc.chunk.KafkaTopic)
+		collectionGroups[collectionName] = append(collectionGroups[collectionName], bc)
 	}
 
-	for collectionName, chunks := range collectionGroups {
-		err := dc.storeDocumentChunksInCollection(collectionName, chunks)
-		if err != nil {
+	for collectionName, group := range collectionGroups {
+		if err := dc.upsertWithRetry(ctx, collectionName, group); err != nil {
+			if dlqErr := dc.writeDeadLetter(collectionName, group, err); dlqErr != nil {
+				fmt.Printf("failed to write dead-letter batch for collection %s: %v\n", collectionName, dlqErr)
+			}
 			return fmt.Errorf("failed to store document chunks in collection %s: %w", collectionName, err)
 		}
 	}
@@ -249,20 +506,106 @@ func (dc *DocumentQdrantClient) storeDocumentsInternal(enrichedChunks []consumer
 	return nil
 }
 
-func (dc *DocumentQdrantClient) storeDocumentChunksInCollection(collectionName string, enrichedChunks []consumer.EnrichedChunk) error {
+// qdrantUpsertError classifies a failed upsert attempt as retryable (a
+// transient 5xx/429 from Qdrant, or a network error reaching it) or
+// permanent (anything else, e.g. a 400 from a malformed request).
+type qdrantUpsertError struct {
+	statusCode int
+	body       string
+	network    bool
+}
+
+func (e *qdrantUpsertError) Error() string {
+	if e.network {
+		return fmt.Sprintf("network error sending batch request: %s", e.body)
+	}
+	return fmt.Sprintf("batch upsert failed (status %d): %s", e.statusCode, e.body)
+}
+
+func (e *qdrantUpsertError) isRetryable() bool {
+	return e.network || e.statusCode == http.StatusTooManyRequests || e.statusCode >= 500
+}
+
+// upsertWithRetry wraps storeDocumentChunksInCollection in exponential
+// backoff with jitter, retrying only on the retryable failures
+// qdrantUpsertError reports. It gives up after 6 total attempts (the
+// initial attempt plus 5 retries) or when ctx is done, whichever comes
+// first.
+func (dc *DocumentQdrantClient) upsertWithRetry(ctx context.Context, collectionName string, chunks []bufferedChunk) error {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = 100 * time.Millisecond
+	b.MaxInterval = 30 * time.Second
+	policy := backoff.WithContext(backoff.WithMaxRetries(b, 5), ctx)
+
+	op := func() error {
+		dc.upsertAttemptsTotal.Inc()
+
+		err := dc.storeDocumentChunksInCollection(collectionName, chunks)
+		if err == nil {
+			return nil
+		}
+
+		if upsertErr, ok := err.(*qdrantUpsertError); ok && !upsertErr.isRetryable() {
+			return backoff.Permanent(err)
+		}
+		return err
+	}
+
+	if err := backoff.Retry(op, policy); err != nil {
+		dc.upsertFailuresTotal.Inc()
+		return err
+	}
+	return nil
+}
+
+func (dc *DocumentQdrantClient) storeDocumentChunksInCollection(collectionName string, chunks []bufferedChunk) error {
 	var points []QdrantPoint
+	// newHashes records the (hash -> pointID) pairs to Record once the
+	// upsert below actually succeeds, so a dedup entry is never recorded
+	// for a point that never made it into Qdrant.
+	newHashes := make(map[string]string)
+
+	for _, bc := range chunks {
+		enrichedChunk := bc.chunk
+		pointID := bc.pointID
+		hash := dc.contentHash(enrichedChunk)
+
+		if dc.dedup != nil {
+			if existingID, found, err := dc.dedup.Check(hash); err != nil {
+				fmt.Printf("dedup lookup failed for chunk %q, storing in full: %v\n", enrichedChunk.Source.DocumentTitle, err)
+			} else if found {
+				// pointID is derived from the same content hash, so a hit on
+				// this very ID means the chunk is already stored in full here;
+				// a reference point would overwrite it.
+				if existingID == pointID {
+					continue
+				}
+				points = append(points, QdrantPoint{
+					ID:      pointID,
+					Vector:  nil,
+					Payload: referencePayload(enrichedChunk, hash, existingID),
+				})
+				continue
+			}
+		}
 
-	for _, enrichedChunk := range enrichedChunks {
-		pointID := dc.generateValidPointID()
 		payload := dc.createDocumentPayload(enrichedChunk)
 
+		vector, err := dc.buildVector(enrichedChunk)
+		if err != nil {
+			return fmt.Errorf("failed to build vector for chunk %q: %w", enrichedChunk.Source.DocumentTitle, err)
+		}
+
 		point := QdrantPoint{
 			ID:      pointID,
-			Vector:  enrichedChunk.Embedding,
+			Vector:  vector,
 			Payload: payload,
 		}
 
 		points = append(points, point)
+		if dc.dedup != nil {
+			newHashes[hash] = pointID
+		}
 	}
 
 	upsertReq := QdrantUpsertRequest{
@@ -287,21 +630,125 @@ func (dc *DocumentQdrantClient) storeDocumentChunksInCollection(collectionName s
 
 	resp, err := dc.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("error sending batch request: %w", err)
+		return &qdrantUpsertError{network: true, body: err.Error()}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to store batch document embeddings in collection %s (status %d): %s", collectionName, resp.StatusCode, string(body))
+		return &qdrantUpsertError{statusCode: resp.StatusCode, body: string(body)}
+	}
+
+	fmt.Printf("Successfully stored %d document embeddings in collection '%s'\n", len(chunks), collectionName)
+
+	for hash, pointID := range newHashes {
+		if err := dc.dedup.Record(hash, pointID); err != nil {
+			fmt.Printf("failed to record dedup entry for hash %s: %v\n", hash, err)
+		}
+	}
+
+	return nil
+}
+
+// DeadLetterRecord is one line of a dead-letter JSONL file: the original
+// enriched chunk intact, the point ID it was assigned, and why it never
+// made it into Qdrant.
+type DeadLetterRecord struct {
+	CollectionName string                 `json:"collection_name"`
+	PointID        string                 `json:"point_id"`
+	Chunk          consumer.EnrichedChunk `json:"chunk"`
+	Error          string                 `json:"error"`
+	FailedAt       time.Time              `json:"failed_at"`
+}
+
+// writeDeadLetter persists a batch that exhausted upsertWithRetry's
+// attempts as JSONL under deadLetterDir, one record per chunk, so it can
+// be inspected and later replayed with ReplayDeadLetter. A no-op when
+// deadLetterDir isn't configured.
+func (dc *DocumentQdrantClient) writeDeadLetter(collectionName string, chunks []bufferedChunk, cause error) error {
+	if dc.deadLetterDir == "" {
+		return nil
+	}
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(dc.deadLetterDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create dead-letter directory %s: %w", dc.deadLetterDir, err)
+	}
+
+	path := filepath.Join(dc.deadLetterDir, fmt.Sprintf("%s-%d.jsonl", collectionName, time.Now().UnixNano()))
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create dead-letter file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	failedAt := time.Now()
+	enc := json.NewEncoder(f)
+	for _, bc := range chunks {
+		record := DeadLetterRecord{
+			CollectionName: collectionName,
+			PointID:        bc.pointID,
+			Chunk:          bc.chunk,
+			Error:          cause.Error(),
+			FailedAt:       failedAt,
+		}
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf("failed to encode dead-letter record: %w", err)
+		}
+	}
+
+	dc.deadletterWrittenTotal.Add(float64(len(chunks)))
+	fmt.Printf("Wrote %d failed document chunks for collection '%s' to dead-letter file %s\n", len(chunks), collectionName, path)
+	return nil
+}
+
+// ReplayDeadLetter re-attempts the upsert for every record in a JSONL file
+// written by writeDeadLetter, grouping them back into one batch per
+// collection so they go through the same retry path as a live upsert.
+// Records keep their original point ID, so a successful replay overwrites
+// the same point a later successful live upsert would have used.
+func (dc *DocumentQdrantClient) ReplayDeadLetter(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open dead-letter file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	byCollection := make(map[string][]bufferedChunk)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record DeadLetterRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("failed to parse dead-letter record in %s: %w", path, err)
+		}
+		byCollection[record.CollectionName] = append(byCollection[record.CollectionName], bufferedChunk{
+			chunk:   record.Chunk,
+			pointID: record.PointID,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read dead-letter file %s: %w", path, err)
+	}
+
+	for collectionName, chunks := range byCollection {
+		if err := dc.upsertWithRetry(context.Background(), collectionName, chunks); err != nil {
+			return fmt.Errorf("failed to replay dead-letter batch for collection %s: %w", collectionName, err)
+		}
 	}
 
-	fmt.Printf("Successfully stored %d document embeddings in collection '%s'\n", len(enrichedChunks), collectionName)
 	return nil
 }
 
 func (dc *DocumentQdrantClient) StoreDocuments(enrichedChunks []consumer.EnrichedChunk) error {
-	return dc.storeDocumentsInternal(enrichedChunks)
+	chunks := make([]bufferedChunk, len(enrichedChunks))
+	for i, enrichedChunk := range enrichedChunks {
+		chunks[i] = bufferedChunk{chunk: enrichedChunk, pointID: dc.generatePointID(enrichedChunk)}
+	}
+	return dc.storeDocumentsInternal(context.Background(), chunks)
 }
 
 func (dc *DocumentQdrantClient) StoreDocument(enrichedChunk consumer.EnrichedChunk) error {
@@ -316,12 +763,13 @@ func (dc *DocumentQdrantClient) CreateDocumentPayloadIndexes() error {
 	documentFields := []string{
 		"category", "complexity", "document_type", "language",
 		"sentiment", "topic", "entities", "keywords", "kafka_topic",
+		"content_hash",
 	}
 
 	for topic, collectionName := range dc.collections {
 		if topic != "logs" {
 			fmt.Printf("Creating payload indexes for document collection '%s' (topic: %s)\n", collectionName, topic)
-			
+
 			for _, field := range documentFields {
 				url := fmt.Sprintf("%s/collections/%s/index", dc.baseURL, collectionName)
 
@@ -365,6 +813,172 @@ func (dc *DocumentQdrantClient) CreateDocumentPayloadIndexes() error {
 	return nil
 }
 
+// vectorQueryResponse is the /points/query response shape for a single
+// named-vector search (no prefetch/fusion involved).
+type vectorQueryResponse struct {
+	Result struct {
+		Points []struct {
+			ID      interface{}            `json:"id"`
+			Score   float64                `json:"score"`
+			Payload map[string]interface{} `json:"payload"`
+		} `json:"points"`
+	} `json:"result"`
+}
+
+// namedVectorQuery runs a plain kNN search against a single named vector in
+// collectionName, with no server-side fusion.
+func (dc *DocumentQdrantClient) namedVectorQuery(collectionName, vectorName string, query interface{}, limit int) ([]SearchResult, error) {
+	queryReq := map[string]interface{}{
+		"query":        query,
+		"using":        vectorName,
+		"limit":        limit,
+		"with_payload": true,
+	}
+
+	jsonData, err := json.Marshal(queryReq)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling %s vector query: %w", vectorName, err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/points/query", dc.baseURL, collectionName)
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s vector query request: %w", vectorName, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if dc.apiKey != "" {
+		req.Header.Set("api-key", dc.apiKey)
+	}
+
+	resp, err := dc.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending %s vector query: %w", vectorName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%s vector query failed in collection %s (status %d): %s", vectorName, collectionName, resp.StatusCode, string(body))
+	}
+
+	var queryResp vectorQueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&queryResp); err != nil {
+		return nil, fmt.Errorf("error decoding %s vector query response: %w", vectorName, err)
+	}
+
+	results := make([]SearchResult, len(queryResp.Result.Points))
+	for i, point := range queryResp.Result.Points {
+		results[i] = SearchResult{ID: point.ID, Score: point.Score, Payload: point.Payload}
+	}
+	return results, nil
+}
+
+// normalizeScores min-max normalizes results' scores into [0, 1] and returns
+// them keyed by result ID, leaving results itself unchanged, so dense cosine similarities and sparse BM25 scores (wildly different
+// scales) can be weighted against each other.
+func normalizeScores(results []SearchResult) map[interface{}]float64 {
+	normalized := make(map[interface{}]float64, len(results))
+	if len(results) == 0 {
+		return normalized
+	}
+
+	min, max := results[0].Score, results[0].Score
+	for _, r := range results {
+		if r.Score < min {
+			min = r.Score
+		}
+		if r.Score > max {
+			max = r.Score
+		}
+	}
+
+	spread := max - min
+	for _, r := range results {
+		if spread == 0 {
+			normalized[r.ID] = 1
+			continue
+		}
+		normalized[r.ID] = (r.Score - min) / spread
+	}
+	return normalized
+}
+
+// HybridSearch runs the dense embedding and a BM25 sparse encoding of text
+// as two independent named-vector queries, then fuses them client-side with
+// a weighted sum: alpha weights the (min-max normalized) dense score, 1-alpha
+// the sparse score. This gives callers direct control over the dense/sparse
+// tradeoff per query, unlike QdrantClient.HybridSearch (the logs path),
+// which lets Qdrant's own RRF/DBSF prefetch fusion pick the weighting.
+// Alpha is clamped to [0, 1]; if hybrid search wasn't enabled for this
+// client, the sparse leg is skipped and this is equivalent to a dense-only
+// search.
+func (dc *DocumentQdrantClient) HybridSearch(collectionName, text string, denseVector []float32, k int, alpha float64) ([]SearchResult, error) {
+	if alpha < 0 {
+		alpha = 0
+	} else if alpha > 1 {
+		alpha = 1
+	}
+
+	fetchLimit := k * 4
+	if fetchLimit < k {
+		fetchLimit = k
+	}
+
+	denseResults, err := dc.namedVectorQuery(collectionName, "dense", denseVector, fetchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("dense leg of hybrid search failed: %w", err)
+	}
+
+	if dc.sparseEncoder == nil {
+		if len(denseResults) > k {
+			denseResults = denseResults[:k]
+		}
+		return denseResults, nil
+	}
+
+	sparse, err := dc.sparseEncoder.Encode(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sparse query vector: %w", err)
+	}
+
+	sparseResults, err := dc.namedVectorQuery(collectionName, "sparse", map[string]interface{}{
+		"indices": sparse.Indices,
+		"values":  sparse.Values,
+	}, fetchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("sparse leg of hybrid search failed: %w", err)
+	}
+
+	denseScores := normalizeScores(denseResults)
+	sparseScores := normalizeScores(sparseResults)
+
+	payloads := make(map[interface{}]map[string]interface{}, len(denseResults)+len(sparseResults))
+	for _, r := range denseResults {
+		payloads[r.ID] = r.Payload
+	}
+	for _, r := range sparseResults {
+		if _, exists := payloads[r.ID]; !exists {
+			payloads[r.ID] = r.Payload
+		}
+	}
+
+	fused := make([]SearchResult, 0, len(payloads))
+	for id, payload := range payloads {
+		fused = append(fused, SearchResult{
+			ID:      id,
+			Score:   alpha*denseScores[id] + (1-alpha)*sparseScores[id],
+			Payload: payload,
+		})
+	}
+
+	sort.Slice(fused, func(i, j int) bool { return fused[i].Score > fused[j].Score })
+	if len(fused) > k {
+		fused = fused[:k]
+	}
+	return fused, nil
+}
+
 func (dc *DocumentQdrantClient) AddToBuffer(enrichedChunk consumer.EnrichedChunk) error {
 	return dc.AddDocumentToBuffer(enrichedChunk)
 }
@@ -375,4 +989,4 @@ func (dc *DocumentQdrantClient) StoreEmbeddings(enrichedChunks []consumer.Enrich
 
 func (dc *DocumentQdrantClient) StoreEmbedding(enrichedChunk consumer.EnrichedChunk) error {
 	return dc.StoreDocument(enrichedChunk)
-}
\ No newline at end of file
+}
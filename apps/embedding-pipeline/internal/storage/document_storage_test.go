@@ -0,0 +1,55 @@
+package storage
+
+import "testing"
+
+func TestNormalizeScores(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []SearchResult
+		want    map[interface{}]float64
+	}{
+		{
+			name:    "empty input",
+			results: nil,
+			want:    map[interface{}]float64{},
+		},
+		{
+			name: "single result normalizes to 1",
+			results: []SearchResult{
+				{ID: "a", Score: 0.42},
+			},
+			want: map[interface{}]float64{"a": 1},
+		},
+		{
+			name: "equal scores all normalize to 1",
+			results: []SearchResult{
+				{ID: "a", Score: 0.7},
+				{ID: "b", Score: 0.7},
+			},
+			want: map[interface{}]float64{"a": 1, "b": 1},
+		},
+		{
+			name: "min-max spread maps to [0, 1]",
+			results: []SearchResult{
+				{ID: "a", Score: 10},
+				{ID: "b", Score: 5},
+				{ID: "c", Score: 0},
+			},
+			want: map[interface{}]float64{"a": 1, "b": 0.5, "c": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeScores(tt.results)
+			if len(got) != len(tt.want) {
+				t.Fatalf("normalizeScores(%v) = %v, want %v", tt.results, got, tt.want)
+			}
+			for id, want := range tt.want {
+				if got[id] != want {
+					t.Errorf("normalizeScores(%v)[%v] = %v, want %v", tt.results, id, got[id], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// newHTTPClient builds the *http.Client a Qdrant client talks through,
+// configuring mTLS and/or OAuth2 client-credentials auth from the same
+// TLSConfig/OAuthConfig shapes the Kafka consumers use, so a deployment
+// configures credentials once and both the Kafka and Qdrant connections
+// pick them up.
+func newHTTPClient(tlsCfg config.TLSConfig, oauthCfg config.OAuthConfig, timeout time.Duration) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if tlsCfg.CAFile != "" || tlsCfg.CertFile != "" || tlsCfg.InsecureSkipVerify {
+		tc := &tls.Config{InsecureSkipVerify: tlsCfg.InsecureSkipVerify}
+
+		if tlsCfg.CAFile != "" {
+			caCert, err := os.ReadFile(tlsCfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file %s: %w", tlsCfg.CAFile, err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(caCert) {
+				return nil, fmt.Errorf("failed to parse CA file %s", tlsCfg.CAFile)
+			}
+			tc.RootCAs = pool
+		}
+
+		if tlsCfg.CertFile != "" && tlsCfg.KeyFile != "" {
+			cert, err := tls.LoadX509KeyPair(tlsCfg.CertFile, tlsCfg.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %w", err)
+			}
+			tc.Certificates = []tls.Certificate{cert}
+		}
+
+		transport.TLSClientConfig = tc
+	}
+
+	client := &http.Client{Timeout: timeout, Transport: transport}
+
+	if oauthCfg.TokenURL != "" {
+		ccCfg := &clientcredentials.Config{
+			ClientID:     oauthCfg.ClientID,
+			ClientSecret: oauthCfg.ClientSecret,
+			TokenURL:     oauthCfg.TokenURL,
+			Scopes:       oauthCfg.Scopes,
+		}
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, client)
+		client = ccCfg.Client(ctx)
+	}
+
+	return client, nil
+}
@@ -14,11 +14,23 @@ import (
 )
 
 type QdrantClient struct {
-	baseURL    string
-	apiKey     string
-	client     *http.Client
+	// connMu guards baseURL, apiKey, client, fusionAlgorithm, and
+	// prefetchLimit, which Reconfigure can swap out from under in-flight
+	// requests and HybridSearch calls at any time.
+	connMu          sync.Mutex
+	baseURL         string
+	apiKey          string
+	client          *http.Client
+	fusionAlgorithm string
+	prefetchLimit   int
+
 	collection string
 
+	// sparseEncoder is non-nil when hybrid dense+sparse search is enabled,
+	// in which case the collection gets a named "sparse" vector alongside
+	// its named "dense" vector.
+	sparseEncoder SparseEncoder
+
 	buffer        []QdrantPoint
 	bufferMu      sync.Mutex
 	bufferSize    int
@@ -26,25 +38,59 @@ type QdrantClient struct {
 	flushInterval time.Duration
 }
 
+// connSnapshot returns a consistent copy of the connection settings
+// Reconfigure can change, so callers can make their HTTP requests outside
+// connMu instead of holding it for the duration of the round trip.
+func (c *QdrantClient) connSnapshot() (baseURL, apiKey string, client *http.Client, fusionAlgorithm string, prefetchLimit int) {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	return c.baseURL, c.apiKey, c.client, c.fusionAlgorithm, c.prefetchLimit
+}
+
 type EmbeddedData struct {
-	ID        string                 `json:"id,omitempty"`       
-	Embedding []float32              `json:"embedding"`           
-	Payload   map[string]interface{} `json:"payload"`            
+	ID        string                 `json:"id,omitempty"`
+	Embedding []float32              `json:"embedding"`
+	Payload   map[string]interface{} `json:"payload"`
+
+	// SparseEmbedding, when set, is stored alongside Embedding as a named
+	// "sparse" vector (SPLADE/BM25-style term weights keyed by hashed term
+	// index) so HybridSearch can fuse lexical and semantic signals.
+	SparseEmbedding map[uint32]float32 `json:"sparse_embedding,omitempty"`
 }
 
 func NewQdrantClient(cfg config.QdrantConfig, collectionName string) (*QdrantClient, error) {
+	httpClient, err := newHTTPClient(cfg.TLS, cfg.OAuth, 30*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Qdrant HTTP client: %w", err)
+	}
+
 	client := &QdrantClient{
-		baseURL:       cfg.URL,
-		apiKey:        cfg.APIKey,
-		collection:    collectionName,
-		bufferSize:    50,
-		flushInterval: 5 * time.Second,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		baseURL:         cfg.URL,
+		apiKey:          cfg.APIKey,
+		collection:      collectionName,
+		bufferSize:      50,
+		flushInterval:   5 * time.Second,
+		client:          httpClient,
+		fusionAlgorithm: cfg.FusionAlgorithm,
+		prefetchLimit:   cfg.PrefetchLimit,
+	}
+
+	if client.fusionAlgorithm == "" {
+		client.fusionAlgorithm = "rrf"
+	}
+	if client.prefetchLimit <= 0 {
+		client.prefetchLimit = 100
 	}
 
-	err := client.createCollectionIfNotExists(cfg.VectorSize)
+	if cfg.HybridSearch {
+		encoder, err := NewBM25Encoder(cfg.BM25StatsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create BM25 sparse encoder: %w", err)
+		}
+		client.sparseEncoder = encoder
+	}
+
+	err = client.createCollectionIfNotExists(cfg.VectorSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create collection %s: %w", collectionName, err)
 	}
@@ -52,17 +98,31 @@ func NewQdrantClient(cfg config.QdrantConfig, collectionName string) (*QdrantCli
 	return client, nil
 }
 
+// vectorsSpec builds the named-vector layout for this client's collection:
+// always a "dense" vector, plus a "sparse" vector when hybrid search is on.
+func (c *QdrantClient) vectorsSpec(vectorSize int) VectorsSpec {
+	spec := VectorsSpec{
+		"dense": {Size: vectorSize, Distance: "Cosine"},
+	}
+	if c.sparseEncoder != nil {
+		spec["sparse"] = VectorSpec{Sparse: true}
+	}
+	return spec
+}
+
 func (c *QdrantClient) createCollectionIfNotExists(vectorSize int) error {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/collections/%s", c.baseURL, c.collection), nil)
+	baseURL, apiKey, client, _, _ := c.connSnapshot()
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/collections/%s", baseURL, c.collection), nil)
 	if err != nil {
 		return err
 	}
 
-	if c.apiKey != "" {
-		req.Header.Set("api-key", c.apiKey)
+	if apiKey != "" {
+		req.Header.Set("api-key", apiKey)
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return err
 	}
@@ -78,10 +138,20 @@ func (c *QdrantClient) createCollectionIfNotExists(vectorSize int) error {
 		return fmt.Errorf("unexpected status checking collection %s: %d, %s", c.collection, resp.StatusCode, string(body))
 	}
 
-	createReq := map[string]interface{}{
-		"vectors": map[string]interface{}{
-			"size":     vectorSize,
-			"distance": "Cosine",
+	denseVectors := make(map[string]interface{})
+	sparseVectors := make(map[string]interface{})
+
+	for name, vs := range c.vectorsSpec(vectorSize) {
+		if vs.Sparse {
+			sparseVectors[name] = map[string]interface{}{
+				"modifier": "idf",
+			}
+			continue
+		}
+
+		denseVectors[name] = map[string]interface{}{
+			"size":     vs.Size,
+			"distance": vs.Distance,
 			"hnsw_config": map[string]interface{}{
 				"m":            16,
 				"ef_construct": 200,
@@ -93,7 +163,14 @@ func (c *QdrantClient) createCollectionIfNotExists(vectorSize int) error {
 				},
 			},
 			"on_disk": true,
-		},
+		}
+	}
+
+	createReq := map[string]interface{}{
+		"vectors": denseVectors,
+	}
+	if len(sparseVectors) > 0 {
+		createReq["sparse_vectors"] = sparseVectors
 	}
 
 	jsonData, err := json.Marshal(createReq)
@@ -101,17 +178,17 @@ func (c *QdrantClient) createCollectionIfNotExists(vectorSize int) error {
 		return err
 	}
 
-	req, err = http.NewRequest("PUT", fmt.Sprintf("%s/collections/%s", c.baseURL, c.collection), bytes.NewBuffer(jsonData))
+	req, err = http.NewRequest("PUT", fmt.Sprintf("%s/collections/%s", baseURL, c.collection), bytes.NewBuffer(jsonData))
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	if c.apiKey != "" {
-		req.Header.Set("api-key", c.apiKey)
+	if apiKey != "" {
+		req.Header.Set("api-key", apiKey)
 	}
 
-	resp, err = c.client.Do(req)
+	resp, err = client.Do(req)
 	if err != nil {
 		return err
 	}
@@ -126,10 +203,52 @@ func (c *QdrantClient) createCollectionIfNotExists(vectorSize int) error {
 	return nil
 }
 
+// Store and FlushBuffer below, together with Close, let *QdrantClient
+// satisfy the Sink interface.
 func (c *QdrantClient) Store(data EmbeddedData) error {
 	return c.addToBuffer(data)
 }
 
+// Close releases the client's idle HTTP connections. It does not flush the
+// buffer; call FlushBuffer first if pending writes need to land.
+func (c *QdrantClient) Close() error {
+	_, _, client, _, _ := c.connSnapshot()
+	client.CloseIdleConnections()
+	return nil
+}
+
+// Reconfigure applies cfg.Qdrant's connection settings (URL, APIKey,
+// TLS/OAuth, via a rebuilt HTTP client) and hybrid-search fusion tuning
+// (FusionAlgorithm, PrefetchLimit) to c in place. VectorSize is
+// deliberately left alone: changing it would mean recreating the
+// collection out from under live traffic, which Reconfigure won't do
+// silently.
+func (c *QdrantClient) Reconfigure(cfg *config.Config) error {
+	httpClient, err := newHTTPClient(cfg.Qdrant.TLS, cfg.Qdrant.OAuth, 30*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild Qdrant HTTP client: %w", err)
+	}
+
+	fusionAlgorithm := cfg.Qdrant.FusionAlgorithm
+	if fusionAlgorithm == "" {
+		fusionAlgorithm = "rrf"
+	}
+	prefetchLimit := cfg.Qdrant.PrefetchLimit
+	if prefetchLimit <= 0 {
+		prefetchLimit = 100
+	}
+
+	c.connMu.Lock()
+	c.baseURL = cfg.Qdrant.URL
+	c.apiKey = cfg.Qdrant.APIKey
+	c.client = httpClient
+	c.fusionAlgorithm = fusionAlgorithm
+	c.prefetchLimit = prefetchLimit
+	c.connMu.Unlock()
+
+	return nil
+}
+
 func (c *QdrantClient) StoreBatch(dataPoints []EmbeddedData) error {
 	if len(dataPoints) == 0 {
 		return nil
@@ -203,11 +322,44 @@ func (c *QdrantClient) createPoint(data EmbeddedData) QdrantPoint {
 
 	return QdrantPoint{
 		ID:      id,
-		Vector:  data.Embedding,
+		Vector:  c.buildVector(data),
 		Payload: data.Payload,
 	}
 }
 
+// buildVector returns the named "dense" embedding alone when data carries no
+// sparse component, or a named "dense"/"sparse" map so Qdrant's Query API can
+// fuse both signals (RRF or DBSF) at search time.
+func (c *QdrantClient) buildVector(data EmbeddedData) interface{} {
+	if len(data.SparseEmbedding) == 0 {
+		return map[string]interface{}{"dense": data.Embedding}
+	}
+
+	sparse := sparseVectorFromMap(data.SparseEmbedding)
+
+	return map[string]interface{}{
+		"dense": data.Embedding,
+		"sparse": map[string]interface{}{
+			"indices": sparse.Indices,
+			"values":  sparse.Values,
+		},
+	}
+}
+
+// sparseVectorFromMap converts the EmbeddedData-friendly term->weight map
+// into Qdrant's parallel indices/values wire shape.
+func sparseVectorFromMap(m map[uint32]float32) SparseVector {
+	vector := SparseVector{
+		Indices: make([]uint32, 0, len(m)),
+		Values:  make([]float32, 0, len(m)),
+	}
+	for index, value := range m {
+		vector.Indices = append(vector.Indices, index)
+		vector.Values = append(vector.Values, value)
+	}
+	return vector
+}
+
 func (c *QdrantClient) upsertPoints(points []QdrantPoint) error {
 	upsertReq := QdrantUpsertRequest{
 		Points: points,
@@ -218,18 +370,20 @@ func (c *QdrantClient) upsertPoints(points []QdrantPoint) error {
 		return fmt.Errorf("error marshaling upsert request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/collections/%s/points", c.baseURL, c.collection)
+	baseURL, apiKey, client, _, _ := c.connSnapshot()
+
+	url := fmt.Sprintf("%s/collections/%s/points", baseURL, c.collection)
 	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	if c.apiKey != "" {
-		req.Header.Set("api-key", c.apiKey)
+	if apiKey != "" {
+		req.Header.Set("api-key", apiKey)
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("error sending request: %w", err)
 	}
@@ -244,11 +398,128 @@ func (c *QdrantClient) upsertPoints(points []QdrantPoint) error {
 	return nil
 }
 
+// SearchResult is one scored hit from HybridSearch.
+type SearchResult struct {
+	ID      interface{}            `json:"id"`
+	Score   float64                `json:"score"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+// queryPrefetch is one leg of a /points/query prefetch-and-fusion request:
+// an initial ranked candidate list over a single named vector, fused with
+// the other legs' results afterward.
+type queryPrefetch struct {
+	Query interface{} `json:"query"`
+	Using string      `json:"using"`
+	Limit int         `json:"limit"`
+}
+
+type queryFusion struct {
+	Fusion string `json:"fusion"`
+}
+
+type hybridQueryRequest struct {
+	Prefetch    []queryPrefetch        `json:"prefetch"`
+	Query       queryFusion            `json:"query"`
+	Limit       int                    `json:"limit"`
+	Filter      map[string]interface{} `json:"filter,omitempty"`
+	WithPayload bool                   `json:"with_payload"`
+}
+
+type hybridQueryResponse struct {
+	Result struct {
+		Points []struct {
+			ID      interface{}            `json:"id"`
+			Score   float64                `json:"score"`
+			Payload map[string]interface{} `json:"payload"`
+		} `json:"points"`
+	} `json:"result"`
+}
+
+// HybridSearch issues a prefetch-and-fusion query against Qdrant's
+// /points/query endpoint: dense and sparse legs are each ranked up to
+// c.prefetchLimit candidates, then fused (RRF or DBSF, per c.fusionAlgorithm)
+// into the top k. An optional filter narrows both legs identically. Sparse
+// may be nil to fall back to a dense-only query.
+func (c *QdrantClient) HybridSearch(dense []float32, sparse map[uint32]float32, k int, filter ...map[string]interface{}) ([]SearchResult, error) {
+	baseURL, apiKey, client, fusionAlgorithm, prefetchLimit := c.connSnapshot()
+
+	prefetch := []queryPrefetch{
+		{Query: dense, Using: "dense", Limit: prefetchLimit},
+	}
+	if len(sparse) > 0 {
+		sparseVector := sparseVectorFromMap(sparse)
+		prefetch = append(prefetch, queryPrefetch{
+			Query: map[string]interface{}{
+				"indices": sparseVector.Indices,
+				"values":  sparseVector.Values,
+			},
+			Using: "sparse",
+			Limit: prefetchLimit,
+		})
+	}
+
+	queryReq := hybridQueryRequest{
+		Prefetch:    prefetch,
+		Query:       queryFusion{Fusion: fusionAlgorithm},
+		Limit:       k,
+		WithPayload: true,
+	}
+	if len(filter) > 0 {
+		queryReq.Filter = filter[0]
+	}
+
+	jsonData, err := json.Marshal(queryReq)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling hybrid query request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/points/query", baseURL, c.collection)
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating hybrid query request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("api-key", apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending hybrid query request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("hybrid query failed in collection %s (status %d): %s", c.collection, resp.StatusCode, string(body))
+	}
+
+	var queryResp hybridQueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&queryResp); err != nil {
+		return nil, fmt.Errorf("error decoding hybrid query response: %w", err)
+	}
+
+	results := make([]SearchResult, len(queryResp.Result.Points))
+	for i, point := range queryResp.Result.Points {
+		results[i] = SearchResult{
+			ID:      point.ID,
+			Score:   point.Score,
+			Payload: point.Payload,
+		}
+	}
+
+	return results, nil
+}
+
 func (c *QdrantClient) CreatePayloadIndexes(fields []string) error {
+	baseURL, apiKey, client, _, _ := c.connSnapshot()
+
 	fmt.Printf("Creating payload indexes for collection '%s'\n", c.collection)
 
 	for _, field := range fields {
-		url := fmt.Sprintf("%s/collections/%s/index", c.baseURL, c.collection)
+		url := fmt.Sprintf("%s/collections/%s/index", baseURL, c.collection)
 
 		payload := map[string]interface{}{
 			"field_name": field,
@@ -268,11 +539,11 @@ func (c *QdrantClient) CreatePayloadIndexes(fields []string) error {
 		}
 
 		req.Header.Set("Content-Type", "application/json")
-		if c.apiKey != "" {
-			req.Header.Set("api-key", c.apiKey)
+		if apiKey != "" {
+			req.Header.Set("api-key", apiKey)
 		}
 
-		resp, err := c.client.Do(req)
+		resp, err := client.Do(req)
 		if err != nil {
 			return fmt.Errorf("failed to send payload index request for field %s: %w", field, err)
 		}
@@ -286,4 +557,4 @@ func (c *QdrantClient) CreatePayloadIndexes(fields []string) error {
 
 	fmt.Printf("All payload indexes created successfully for collection '%s'\n", c.collection)
 	return nil
-}
\ No newline at end of file
+}
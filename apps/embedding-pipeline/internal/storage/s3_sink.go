@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/embedding-pipeline/internal/config"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Sink archives embedded records as gzip-compressed NDJSON objects in
+// S3-compatible object storage (AWS S3 or MinIO), for cold storage and
+// later Replay rather than online search. Records are grouped by their
+// kafka_topic payload field and rotated into a new object once that
+// topic's buffer hits rotateSize records or rotateInterval elapses since
+// the buffer was first opened, whichever comes first.
+type S3Sink struct {
+	client         *minio.Client
+	bucket         string
+	rotateSize     int
+	rotateInterval time.Duration
+
+	mu      sync.Mutex
+	buffers map[string][]EmbeddedData
+	opened  map[string]time.Time
+}
+
+func NewS3Sink(cfg config.ArchiveConfig) (*S3Sink, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 archive sink client: %w", err)
+	}
+
+	return &S3Sink{
+		client:         client,
+		bucket:         cfg.Bucket,
+		rotateSize:     cfg.RotateSize,
+		rotateInterval: time.Duration(cfg.RotateIntervalSecs) * time.Second,
+		buffers:        make(map[string][]EmbeddedData),
+		opened:         make(map[string]time.Time),
+	}, nil
+}
+
+func topicOf(data EmbeddedData) string {
+	if topic, ok := data.Payload["kafka_topic"].(string); ok && topic != "" {
+		return topic
+	}
+	return "unknown"
+}
+
+func (s *S3Sink) Store(data EmbeddedData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	topic := topicOf(data)
+	if _, open := s.opened[topic]; !open {
+		s.opened[topic] = time.Now()
+	}
+	s.buffers[topic] = append(s.buffers[topic], data)
+
+	if len(s.buffers[topic]) >= s.rotateSize || time.Since(s.opened[topic]) >= s.rotateInterval {
+		return s.rotateLocked(topic)
+	}
+
+	return nil
+}
+
+// FlushBuffer rotates every topic's buffer regardless of size or age, for
+// use on shutdown or partition revoke so nothing buffered is lost.
+func (s *S3Sink) FlushBuffer() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for topic := range s.buffers {
+		if err := s.rotateLocked(topic); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// rotateLocked gzip-NDJSON-encodes a topic's buffered records and uploads
+// them as one object keyed by topic/date/hour/uuid.jsonl.gz, then clears
+// the buffer. Callers must hold s.mu.
+func (s *S3Sink) rotateLocked(topic string) error {
+	records := s.buffers[topic]
+	if len(records) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	gz := gzip.NewWriter(&body)
+	enc := json.NewEncoder(gz)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			gz.Close()
+			return fmt.Errorf("failed to encode archive record: %w", err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to close gzip writer: %w", err)
+	}
+
+	now := time.Now().UTC()
+	key := fmt.Sprintf("%s/%s/%02d/%s.jsonl.gz", topic, now.Format("2006-01-02"), now.Hour(), uuid.New().String())
+
+	_, err := s.client.PutObject(context.Background(), s.bucket, key, &body, int64(body.Len()), minio.PutObjectOptions{
+		ContentType:     "application/x-ndjson",
+		ContentEncoding: "gzip",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload archive object %s: %w", key, err)
+	}
+
+	delete(s.buffers, topic)
+	delete(s.opened, topic)
+
+	fmt.Printf("Archived %d records to s3://%s/%s\n", len(records), s.bucket, key)
+	return nil
+}
+
+func (s *S3Sink) Close() error {
+	return s.FlushBuffer()
+}
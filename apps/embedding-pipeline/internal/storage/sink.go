@@ -0,0 +1,56 @@
+package storage
+
+import "errors"
+
+// Sink is a destination for embedded records. Implementations are expected
+// to buffer writes internally and flush on their own schedule; FlushBuffer
+// forces an immediate flush (e.g. on shutdown or partition revoke) and
+// Close releases any underlying connection.
+type Sink interface {
+	Store(data EmbeddedData) error
+	FlushBuffer() error
+	Close() error
+}
+
+// MultiSink fans Store/FlushBuffer/Close out to every configured sink,
+// trying all of them even if one fails so a stalled or erroring sink (e.g.
+// archival object storage being unreachable) doesn't stop writes from
+// reaching the others (e.g. the online Qdrant collection). Errors from
+// every sink are joined so callers can still tell the overall call failed.
+type MultiSink struct {
+	sinks []Sink
+}
+
+func NewMultiSink(sinks ...Sink) *MultiSink {
+	return &MultiSink{sinks: sinks}
+}
+
+func (m *MultiSink) Store(data EmbeddedData) error {
+	var errs []error
+	for _, sink := range m.sinks {
+		if err := sink.Store(data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MultiSink) FlushBuffer() error {
+	var errs []error
+	for _, sink := range m.sinks {
+		if err := sink.FlushBuffer(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MultiSink) Close() error {
+	var errs []error
+	for _, sink := range m.sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
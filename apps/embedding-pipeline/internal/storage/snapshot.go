@@ -0,0 +1,299 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/data/logs/logtail"
+)
+
+// Snapshot describes one Qdrant collection snapshot, as returned by the
+// snapshot list/create endpoints.
+type Snapshot struct {
+	Name         string `json:"name"`
+	CreationTime string `json:"creation_time"`
+	Size         int64  `json:"size"`
+}
+
+// Progress reports how a long-running streaming transfer is going, so a CLI
+// caller can render a terminal progress bar without the storage package
+// knowing anything about terminals.
+type Progress interface {
+	OnProgress(bytesDone, totalBytes int64)
+}
+
+// ProgressFunc adapts a plain function to Progress.
+type ProgressFunc func(bytesDone, totalBytes int64)
+
+func (f ProgressFunc) OnProgress(bytesDone, totalBytes int64) {
+	if f != nil {
+		f(bytesDone, totalBytes)
+	}
+}
+
+// noopProgress is used when a caller passes a nil Progress.
+type noopProgress struct{}
+
+func (noopProgress) OnProgress(int64, int64) {}
+
+// progressReader wraps an io.Reader and reports bytes read so far to a
+// Progress on every Read call.
+type progressReader struct {
+	r        io.Reader
+	total    int64
+	done     int64
+	progress Progress
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	if n > 0 {
+		pr.done += int64(n)
+		pr.progress.OnProgress(pr.done, pr.total)
+	}
+	return n, err
+}
+
+// CreateSnapshot triggers Qdrant to take a new snapshot of collection and
+// returns its metadata once the (synchronous) snapshot call completes.
+func (dc *DocumentQdrantClient) CreateSnapshot(collection string) (*Snapshot, error) {
+	url := fmt.Sprintf("%s/collections/%s/snapshots", dc.baseURL, collection)
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	dc.setAuthHeader(req)
+
+	resp, err := dc.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error creating snapshot for collection %s: %w", collection, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create snapshot for collection %s (status %d): %s", collection, resp.StatusCode, string(body))
+	}
+
+	var out struct {
+		Result Snapshot `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("error decoding snapshot response: %w", err)
+	}
+
+	return &out.Result, nil
+}
+
+// ListSnapshots returns every snapshot Qdrant currently holds for collection.
+func (dc *DocumentQdrantClient) ListSnapshots(collection string) ([]Snapshot, error) {
+	url := fmt.Sprintf("%s/collections/%s/snapshots", dc.baseURL, collection)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	dc.setAuthHeader(req)
+
+	resp, err := dc.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error listing snapshots for collection %s: %w", collection, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list snapshots for collection %s (status %d): %s", collection, resp.StatusCode, string(body))
+	}
+
+	var out struct {
+		Result []Snapshot `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("error decoding snapshot list response: %w", err)
+	}
+
+	return out.Result, nil
+}
+
+// DownloadSnapshot streams snapshot name for collection into w, reporting
+// progress as bytes arrive. progress may be nil.
+func (dc *DocumentQdrantClient) DownloadSnapshot(collection, name string, w io.Writer, progress Progress) error {
+	if progress == nil {
+		progress = noopProgress{}
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/snapshots/%s", dc.baseURL, collection, name)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	dc.setAuthHeader(req)
+
+	resp, err := dc.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error downloading snapshot %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to download snapshot %s (status %d): %s", name, resp.StatusCode, string(body))
+	}
+
+	reader := &progressReader{r: resp.Body, total: resp.ContentLength, progress: progress}
+	if _, err := io.Copy(w, reader); err != nil {
+		return fmt.Errorf("error streaming snapshot %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// UploadSnapshot reads r (a snapshot of size bytes) into an in-memory
+// multipart form, reporting progress as r is read, then uploads the form to
+// Qdrant as a new snapshot for collection. The whole snapshot is held in
+// memory before the request starts; the request body is not streamed from
+// r. progress may be nil.
+func (dc *DocumentQdrantClient) UploadSnapshot(collection string, r io.Reader, size int64, progress Progress) error {
+	if progress == nil {
+		progress = noopProgress{}
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("snapshot", "snapshot")
+	if err != nil {
+		return fmt.Errorf("error creating multipart form: %w", err)
+	}
+
+	reader := &progressReader{r: r, total: size, progress: progress}
+	if _, err := io.Copy(part, reader); err != nil {
+		return fmt.Errorf("error buffering snapshot upload: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("error closing multipart form: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/snapshots/upload", dc.baseURL, collection)
+	req, err := http.NewRequest("POST", url, body)
+	if err != nil {
+		return fmt.Errorf("error creating upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	dc.setAuthHeader(req)
+
+	resp, err := dc.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error uploading snapshot: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to upload snapshot for collection %s (status %d): %s", collection, resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// RestoreFromURL asks Qdrant to recover collection directly from a
+// snapshot at url (e.g. a signed GCS/S3 URL), without streaming the bytes
+// through this process first.
+func (dc *DocumentQdrantClient) RestoreFromURL(collection, url string) error {
+	reqBody, err := json.Marshal(map[string]string{"location": url})
+	if err != nil {
+		return fmt.Errorf("error marshaling restore request: %w", err)
+	}
+
+	recoverURL := fmt.Sprintf("%s/collections/%s/snapshots/recover", dc.baseURL, collection)
+	req, err := http.NewRequest("PUT", recoverURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("error creating restore request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	dc.setAuthHeader(req)
+
+	resp, err := dc.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error restoring collection %s from %s: %w", collection, url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to restore collection %s (status %d): %s", collection, resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+func (dc *DocumentQdrantClient) setAuthHeader(req *http.Request) {
+	if dc.apiKey != "" {
+		req.Header.Set("api-key", dc.apiKey)
+	}
+}
+
+// BackupScheduler periodically snapshots a set of collections and pushes
+// each snapshot to an object-storage sink, reusing the log shipper's Sink
+// abstraction rather than writing a separate GCS/S3 client.
+type BackupScheduler struct {
+	client      *DocumentQdrantClient
+	collections []string
+	sink        logtail.Sink
+	interval    time.Duration
+}
+
+func NewBackupScheduler(client *DocumentQdrantClient, collections []string, sink logtail.Sink, interval time.Duration) *BackupScheduler {
+	return &BackupScheduler{
+		client:      client,
+		collections: collections,
+		sink:        sink,
+		interval:    interval,
+	}
+}
+
+// Run snapshots every configured collection once per interval until ctx is
+// canceled, then returns ctx.Err(). A failed snapshot/upload for one
+// collection is printed and Run keeps going, so one bad collection doesn't
+// stop backups of the others.
+func (b *BackupScheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			for _, collection := range b.collections {
+				if err := b.backupOne(ctx, collection); err != nil {
+					fmt.Printf("backup of collection %s failed: %v\n", collection, err)
+				}
+			}
+		}
+	}
+}
+
+func (b *BackupScheduler) backupOne(ctx context.Context, collection string) error {
+	snapshot, err := b.client.CreateSnapshot(collection)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := b.client.DownloadSnapshot(collection, snapshot.Name, &buf, nil); err != nil {
+		return fmt.Errorf("failed to download snapshot %s: %w", snapshot.Name, err)
+	}
+
+	meta := logtail.SinkMeta{Path: snapshot.Name, LogType: "qdrant-snapshot-" + collection}
+	if err := b.sink.Write(ctx, buf.Bytes(), meta); err != nil {
+		return fmt.Errorf("failed to push snapshot %s to sink: %w", snapshot.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// SparseVector is Qdrant's sparse-vector wire shape: parallel index/value
+// arrays over a (possibly hashed) vocabulary.
+type SparseVector struct {
+	Indices []uint32  `json:"indices"`
+	Values  []float32 `json:"values"`
+}
+
+// SparseEncoder turns text into a sparse vector suitable for Qdrant's named
+// sparse-vector fields, so a point can carry both a dense embedding and a
+// lexical signal for hybrid retrieval.
+type SparseEncoder interface {
+	Encode(text string) (SparseVector, error)
+}
+
+var tokenPattern = regexp.MustCompile(`[a-zA-Z0-9]+`)
+
+func tokenize(text string) []string {
+	return tokenPattern.FindAllString(strings.ToLower(text), -1)
+}
+
+// hashTerm maps a term to a vector index via FNV-1a, so BM25Encoder doesn't
+// need a fixed, pre-shared vocabulary.
+func hashTerm(term string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(term))
+	return h.Sum32()
+}
+
+// bm25Stats is the corpus-level state BM25Encoder needs to score a term:
+// how many documents it's seen, how many of those contained each term, and
+// the running average document length.
+type bm25Stats struct {
+	DocCount   int            `json:"doc_count"`
+	DocFreq    map[string]int `json:"doc_freq"`
+	TotalTerms int            `json:"total_terms"`
+}
+
+// BM25Encoder computes BM25 term weights over a corpus whose document-
+// frequency statistics are persisted to a small JSON file, so IDF stays
+// accurate across restarts without a separate stats database.
+type BM25Encoder struct {
+	mu        sync.Mutex
+	statsPath string
+	stats     bm25Stats
+	k1        float64
+	b         float64
+}
+
+// NewBM25Encoder loads stats from statsPath if present, or starts a fresh
+// corpus otherwise.
+func NewBM25Encoder(statsPath string) (*BM25Encoder, error) {
+	e := &BM25Encoder{
+		statsPath: statsPath,
+		stats:     bm25Stats{DocFreq: make(map[string]int)},
+		k1:        1.2,
+		b:         0.75,
+	}
+
+	data, err := os.ReadFile(statsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return e, nil
+		}
+		return nil, fmt.Errorf("failed to read BM25 stats file %s: %w", statsPath, err)
+	}
+
+	if err := json.Unmarshal(data, &e.stats); err != nil {
+		return nil, fmt.Errorf("failed to parse BM25 stats file %s: %w", statsPath, err)
+	}
+	if e.stats.DocFreq == nil {
+		e.stats.DocFreq = make(map[string]int)
+	}
+
+	return e, nil
+}
+
+// Encode tokenizes text, folds it into the corpus-level document-frequency
+// stats, and scores each distinct term with BM25 using the updated stats.
+// Stats are persisted after every call, trading a bit of write overhead for
+// never losing corpus state on a crash.
+func (e *BM25Encoder) Encode(text string) (SparseVector, error) {
+	terms := tokenize(text)
+	if len(terms) == 0 {
+		return SparseVector{}, nil
+	}
+
+	termFreq := make(map[string]int, len(terms))
+	for _, term := range terms {
+		termFreq[term]++
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.DocCount++
+	e.stats.TotalTerms += len(terms)
+	for term := range termFreq {
+		e.stats.DocFreq[term]++
+	}
+
+	avgDocLen := float64(e.stats.TotalTerms) / float64(e.stats.DocCount)
+	docLen := float64(len(terms))
+
+	vector := SparseVector{
+		Indices: make([]uint32, 0, len(termFreq)),
+		Values:  make([]float32, 0, len(termFreq)),
+	}
+
+	for term, tf := range termFreq {
+		df := e.stats.DocFreq[term]
+		idf := math.Log(1 + (float64(e.stats.DocCount)-float64(df)+0.5)/(float64(df)+0.5))
+		numerator := float64(tf) * (e.k1 + 1)
+		denominator := float64(tf) + e.k1*(1-e.b+e.b*(docLen/avgDocLen))
+		score := idf * (numerator / denominator)
+		if score <= 0 {
+			continue
+		}
+
+		vector.Indices = append(vector.Indices, hashTerm(term))
+		vector.Values = append(vector.Values, float32(score))
+	}
+
+	if err := e.save(); err != nil {
+		return SparseVector{}, fmt.Errorf("failed to persist BM25 stats: %w", err)
+	}
+
+	return vector, nil
+}
+
+// save writes stats atomically (write-tmp, rename) so a crash mid-write
+// can't leave a truncated stats file behind.
+func (e *BM25Encoder) save() error {
+	if e.statsPath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(e.stats)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(e.statsPath)
+	tmp, err := os.CreateTemp(dir, ".bm25-stats-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, e.statsPath)
+}
+
+// SPLADEClient calls a self-hosted SPLADE sidecar over HTTP, for deployments
+// that want learned sparse vectors instead of BM25's hashed term weights.
+type SPLADEClient struct {
+	endpoint string
+	client   *http.Client
+}
+
+func NewSPLADEClient(endpoint string) *SPLADEClient {
+	return &SPLADEClient{
+		endpoint: endpoint,
+		client:   &http.Client{},
+	}
+}
+
+func (s *SPLADEClient) Encode(text string) (SparseVector, error) {
+	reqBody, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return SparseVector{}, fmt.Errorf("error marshaling SPLADE request: %w", err)
+	}
+
+	resp, err := s.client.Post(s.endpoint, "application/json", bytes.NewReader(reqBody))
+	if err != nil {
+		return SparseVector{}, fmt.Errorf("error calling SPLADE sidecar: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return SparseVector{}, fmt.Errorf("SPLADE sidecar returned status %d", resp.StatusCode)
+	}
+
+	var vector SparseVector
+	if err := json.NewDecoder(resp.Body).Decode(&vector); err != nil {
+		return SparseVector{}, fmt.Errorf("error decoding SPLADE response: %w", err)
+	}
+
+	return vector, nil
+}
@@ -1,14 +1,22 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/config"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/idempotency"
 	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/processor"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/uploads"
 )
 
 type APIResponse struct {
@@ -35,7 +43,41 @@ func main() {
 		log.Fatalf("Failed to create document processor: %v", err)
 	}
 
-	http.HandleFunc("/receive", handleReceiveDocument(docProcessor, cfg))
+	var idempotencyStore *idempotency.Store
+	if cfg.Processing.IdempotencyEnabled {
+		dbPath := cfg.Processing.IdempotencyDBPath
+		if dbPath == "" {
+			dbPath = "idempotency.db"
+		}
+		idempotencyStore, err = idempotency.NewStore(dbPath, cfg.Processing.IdempotencyTTL.Duration)
+		if err != nil {
+			log.Fatalf("Failed to open idempotency store: %v", err)
+		}
+		log.Printf("Idempotency cache enabled at %s (ttl=%s)", dbPath, cfg.Processing.IdempotencyTTL)
+	}
+
+	uploadManager := uploads.NewManager(docProcessor.StorageManager(), cfg.Processing.MaxConcurrentUploads)
+
+	if ttl := cfg.Processing.UploadSessionTTL.Duration; ttl > 0 {
+		interval := ttl / 2
+		if interval < time.Minute {
+			interval = time.Minute
+		}
+		reaper := uploads.NewReaper(uploadManager, ttl, interval)
+		go reaper.Run()
+		log.Printf("Upload session reaper enabled (ttl=%s, sweep every %s)", ttl, interval)
+	}
+
+	http.HandleFunc("/receive", handleReceiveDocument(docProcessor, cfg, idempotencyStore))
+	http.HandleFunc("/chunker/status", handleChunkerStatus(docProcessor))
+	http.HandleFunc("/receive/uploads", handleCreateUpload(uploadManager, cfg))
+	http.HandleFunc("/receive/uploads/", handleUploadByID(docProcessor, uploadManager, idempotencyStore))
+
+	adminPort := "3002"
+	if err := docProcessor.StartChunkerAdminServer(":" + adminPort); err != nil {
+		log.Fatalf("Failed to start chunker admin server: %v", err)
+	}
+	log.Printf("Chunker admin server (metrics, pprof) on port %s", adminPort)
 
 	port := "3001"
 	log.Printf("Starting server on port %s", port)
@@ -47,11 +89,11 @@ func main() {
 	}
 }
 
-func handleReceiveDocument(docProcessor *processor.DocumentProcessor, cfg *config.Config) http.HandlerFunc {
+func handleReceiveDocument(docProcessor *processor.DocumentProcessor, cfg *config.Config, idempotencyStore *idempotency.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Idempotency-Key")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -65,6 +107,17 @@ func handleReceiveDocument(docProcessor *processor.DocumentProcessor, cfg *confi
 
 		startTime := time.Now()
 
+		idempotencyKey := r.Header.Get("Idempotency-Key")
+		if idempotencyStore != nil && idempotencyKey != "" {
+			if cached, ok, err := idempotencyStore.Get("key:" + idempotencyKey); err != nil {
+				log.Printf("Idempotency lookup failed for key %q: %v", idempotencyKey, err)
+			} else if ok {
+				log.Printf("Idempotency-Key %q hit cached response", idempotencyKey)
+				writeJSONResponse(w, http.StatusOK, cached)
+				return
+			}
+		}
+
 		// Add file size validation
 		err := r.ParseMultipartForm(int64(cfg.Processing.MaxFileSize) << 20) // Convert MB to bytes
 		if err != nil {
@@ -81,6 +134,29 @@ func handleReceiveDocument(docProcessor *processor.DocumentProcessor, cfg *confi
 		}
 		defer file.Close()
 
+		var contentHashKey string
+		if idempotencyStore != nil {
+			hasher := sha256.New()
+			if _, err := io.Copy(hasher, file); err != nil {
+				log.Printf("Failed to hash uploaded document: %v", err)
+			} else {
+				contentHashKey = "sha256:" + hex.EncodeToString(hasher.Sum(nil))
+			}
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				log.Printf("Failed to rewind uploaded document after hashing: %v", err)
+			}
+
+			if contentHashKey != "" {
+				if cached, ok, err := idempotencyStore.Get(contentHashKey); err != nil {
+					log.Printf("Idempotency content-hash lookup failed: %v", err)
+				} else if ok {
+					log.Printf("Document %s matches a previously processed upload (%s)", header.Filename, contentHashKey)
+					writeJSONResponse(w, http.StatusOK, cached)
+					return
+				}
+			}
+		}
+
 		result, err := docProcessor.ProcessDocument(r.Context(), file, header)
 		if err != nil {
 			log.Printf("Failed to process document: %v", err)
@@ -96,7 +172,195 @@ func handleReceiveDocument(docProcessor *processor.DocumentProcessor, cfg *confi
 
 		log.Printf("Successfully processed document: %s", header.Filename)
 
-		sendSuccessResponse(w, "Document processed successfully", processingResponse)
+		body, err := buildSuccessResponse("Document processed successfully", processingResponse)
+		if err != nil {
+			log.Printf("Failed to encode success response: %v", err)
+			sendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+		if idempotencyStore != nil {
+			if idempotencyKey != "" {
+				if err := idempotencyStore.Put("key:"+idempotencyKey, body); err != nil {
+					log.Printf("Failed to cache response for Idempotency-Key %q: %v", idempotencyKey, err)
+				}
+			}
+			if contentHashKey != "" {
+				if err := idempotencyStore.Put(contentHashKey, body); err != nil {
+					log.Printf("Failed to cache response for %s: %v", contentHashKey, err)
+				}
+			}
+		}
+
+		writeJSONResponse(w, http.StatusOK, body)
+	}
+}
+
+// handleCreateUpload starts a tus-style resumable upload session. The
+// client declares the total upload size via the Upload-Length header; the
+// response's Location header points at the session's /receive/uploads/{id}
+// resource for subsequent PATCH/HEAD requests.
+func handleCreateUpload(uploadManager *uploads.Manager, cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		totalSize, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+		if err != nil || totalSize <= 0 {
+			sendErrorResponse(w, "Upload-Length header must be a positive integer", http.StatusBadRequest)
+			return
+		}
+
+		maxSize := cfg.Processing.MaxFileSize << 20
+		if totalSize > maxSize {
+			sendErrorResponse(w, fmt.Sprintf("Upload-Length exceeds the %d MB limit", cfg.Processing.MaxFileSize), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		filename := r.Header.Get("Upload-Filename")
+		if filename == "" {
+			filename = "upload"
+		}
+
+		session, err := uploadManager.Create(filename, r.Header.Get("Content-Type"), totalSize)
+		if err != nil {
+			if err == uploads.ErrTooManySessions {
+				sendErrorResponse(w, "Too many concurrent upload sessions", http.StatusServiceUnavailable)
+				return
+			}
+			log.Printf("Failed to create upload session: %v", err)
+			sendErrorResponse(w, "Failed to create upload session", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Location", "/receive/uploads/"+session.ID)
+		w.Header().Set("Upload-Offset", "0")
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+// handleUploadByID dispatches PATCH (append a chunk) and HEAD (query
+// progress) requests for a single resumable upload session.
+func handleUploadByID(docProcessor *processor.DocumentProcessor, uploadManager *uploads.Manager, idempotencyStore *idempotency.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/receive/uploads/")
+		if id == "" {
+			sendErrorResponse(w, "Missing upload ID", http.StatusBadRequest)
+			return
+		}
+
+		session, ok := uploadManager.Get(id)
+		if !ok {
+			sendErrorResponse(w, "Upload session not found", http.StatusNotFound)
+			return
+		}
+
+		switch r.Method {
+		case "HEAD":
+			w.Header().Set("Upload-Offset", strconv.FormatInt(session.Offset(), 10))
+			w.Header().Set("Upload-Length", strconv.FormatInt(session.TotalSize, 10))
+			w.WriteHeader(http.StatusOK)
+
+		case "PATCH":
+			expectedOffset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+			if err != nil {
+				sendErrorResponse(w, "Upload-Offset header must be an integer", http.StatusBadRequest)
+				return
+			}
+
+			newOffset, err := session.Write(expectedOffset, r.Body)
+			if err != nil {
+				if err == uploads.ErrOffsetMismatch {
+					w.Header().Set("Upload-Offset", strconv.FormatInt(newOffset, 10))
+					sendErrorResponse(w, "Upload offset mismatch", http.StatusConflict)
+					return
+				}
+				log.Printf("Failed to write upload chunk for %s: %v", id, err)
+				sendErrorResponse(w, "Failed to write upload chunk", http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Upload-Offset", strconv.FormatInt(newOffset, 10))
+
+			if !session.Complete() {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			uploadManager.Delete(id)
+			completeUpload(w, docProcessor, idempotencyStore, session)
+
+		default:
+			sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// completeUpload finalizes a fully-received resumable upload: it
+// short-circuits to a cached response if the content hash was already
+// processed, otherwise runs it through ProcessDocument and caches the
+// result under its content hash for future dedup.
+func completeUpload(w http.ResponseWriter, docProcessor *processor.DocumentProcessor, idempotencyStore *idempotency.Store, session *uploads.Session) {
+	contentHashKey := "sha256:" + session.ContentHash()
+
+	if idempotencyStore != nil {
+		if cached, ok, err := idempotencyStore.Get(contentHashKey); err != nil {
+			log.Printf("Idempotency content-hash lookup failed for upload %s: %v", session.ID, err)
+		} else if ok {
+			log.Printf("Upload %s matches a previously processed document (%s)", session.ID, contentHashKey)
+			session.Close()
+			writeJSONResponse(w, http.StatusOK, cached)
+			return
+		}
+	}
+
+	file, header, err := session.Finalize()
+	if err != nil {
+		log.Printf("Failed to finalize upload %s: %v", session.ID, err)
+		sendErrorResponse(w, "Failed to finalize upload", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	startTime := time.Now()
+	result, err := docProcessor.ProcessDocument(context.Background(), file, header)
+	if err != nil {
+		log.Printf("Failed to process completed upload %s: %v", session.ID, err)
+		sendErrorResponse(w, fmt.Sprintf("Failed to process document: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	processingResponse := ProcessingResponse{
+		FileName:       header.Filename,
+		ProcessingTime: time.Since(startTime).String(),
+		Data:           result,
+	}
+
+	body, err := buildSuccessResponse("Document processed successfully", processingResponse)
+	if err != nil {
+		log.Printf("Failed to encode success response for upload %s: %v", session.ID, err)
+		sendErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	if idempotencyStore != nil {
+		if err := idempotencyStore.Put(contentHashKey, body); err != nil {
+			log.Printf("Failed to cache response for %s: %v", contentHashKey, err)
+		}
+	}
+
+	writeJSONResponse(w, http.StatusOK, body)
+}
+
+func handleChunkerStatus(docProcessor *processor.DocumentProcessor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		sendSuccessResponse(w, "Chunker status", docProcessor.ChunkerStatus())
 	}
 }
 
@@ -115,6 +379,23 @@ func sendSuccessResponse(w http.ResponseWriter, message string, data any) {
 	}
 }
 
+// buildSuccessResponse marshals a success envelope to bytes instead of
+// writing it directly, so callers can cache it in the idempotency store
+// before (or instead of) sending it on this response.
+func buildSuccessResponse(message string, data any) ([]byte, error) {
+	return json.Marshal(APIResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
+func writeJSONResponse(w http.ResponseWriter, statusCode int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
+
 func sendErrorResponse(w http.ResponseWriter, message string, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -0,0 +1,93 @@
+// Command retry-replay runs continuously alongside the main ingestion
+// server, consuming "<topic>.retry" and re-chunking each section once its
+// ReplayAfter delay has elapsed. A section that fails again is sent
+// straight to the dead letter topic rather than re-enqueued, to bound how
+// long a truly-broken section keeps cycling through the retry topic.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/chunking"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/config"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+func main() {
+	configPath := flag.String("config", "config.json", "Path to configuration file")
+	sourceTopic := flag.String("topic", "documents", "Source topic whose <topic>.retry should be replayed")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	chunkingConfig := models.ChunkingConfig{
+		GeminiAPIKey:    cfg.Chunking.GeminiAPIKey,
+		GeminiModel:     cfg.Chunking.GeminiModel,
+		MaxRetries:      3,
+		MaxConcurrency:  5,
+		RateLimitRPS:    10,
+		RequestTimeout:  30 * time.Second,
+		DeadLetterTopic: *sourceTopic + ".dlq",
+		RetryTopic:      *sourceTopic + ".retry",
+	}
+
+	chunker := chunking.NewKafkaAgenticChunker(chunkingConfig)
+	if err := chunker.InitializeKafkaStreaming("kafka:29092", *sourceTopic, models.KafkaSecurityConfig{}); err != nil {
+		log.Fatalf("Failed to initialize Kafka streaming: %v", err)
+	}
+	defer chunker.Close()
+
+	reader, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": "kafka:29092",
+		"group.id":          "ingestion-retry-replay",
+		"auto.offset.reset": "earliest",
+	})
+	if err != nil {
+		log.Fatalf("Failed to create retry topic reader: %v", err)
+	}
+	defer reader.Close()
+
+	retryTopic := *sourceTopic + ".retry"
+	if err := reader.SubscribeTopics([]string{retryTopic}, nil); err != nil {
+		log.Fatalf("Failed to subscribe to %s: %v", retryTopic, err)
+	}
+
+	log.Printf("Replaying %s onto %s as delays elapse", retryTopic, *sourceTopic)
+
+	ctx := context.Background()
+	for {
+		msg, err := reader.ReadMessage(-1)
+		if err != nil {
+			log.Printf("Failed to read retry message: %v", err)
+			continue
+		}
+
+		var record chunking.RetryRecord
+		if err := json.Unmarshal(msg.Value, &record); err != nil {
+			log.Printf("Skipping unparseable retry record: %v", err)
+			continue
+		}
+
+		if wait := time.Until(record.ReplayAfter); wait > 0 {
+			time.Sleep(wait)
+		}
+
+		if err := chunker.ReplaySection(ctx, record); err != nil {
+			log.Printf("Replay failed for section %d, dead-lettering: %v", record.SectionIndex, err)
+			if dlqErr := chunker.DeadLetter(record, err); dlqErr != nil {
+				log.Printf("Failed to dead-letter section %d: %v", record.SectionIndex, dlqErr)
+			}
+			continue
+		}
+
+		log.Printf("Replayed section %d onto %s", record.SectionIndex, *sourceTopic)
+	}
+}
@@ -0,0 +1,36 @@
+package chunking
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// sectionBackoff is a full-jitter exponential backoff used for per-section
+// retries: delay(attempt) picks uniformly between 0 and
+// initial*2^(attempt-1), capped at max. This is deliberately separate from
+// callLLM's own quadratic backoff, which only covers a single Gemini/LLM
+// call — sectionBackoff covers the whole processSectionConcurrently ->
+// buildChunkOutput -> streamChunkToKafka pipeline for one section.
+type sectionBackoff struct {
+	initial time.Duration
+	max     time.Duration
+}
+
+func newSectionBackoff() sectionBackoff {
+	return sectionBackoff{
+		initial: 500 * time.Millisecond,
+		max:     30 * time.Second,
+	}
+}
+
+func (b sectionBackoff) delay(attempt int) time.Duration {
+	d := float64(b.initial) * math.Pow(2, float64(attempt-1))
+	if d > float64(b.max) {
+		d = float64(b.max)
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
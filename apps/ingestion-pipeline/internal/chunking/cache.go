@@ -0,0 +1,146 @@
+package chunking
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CachePolicy mirrors HTTP Cache-Control semantics for AI chunk analysis:
+//   - "" (default): read from cache if present, otherwise analyze and write back.
+//   - CacheNoCache: always re-analyze, but still write the fresh result back.
+//   - CacheNoStore: always re-analyze and never write the result back.
+//   - CacheOnlyIfCached: never call the model; return an error on a miss.
+type CachePolicy string
+
+const (
+	CacheDefault      CachePolicy = ""
+	CacheNoCache      CachePolicy = "no-cache"
+	CacheNoStore      CachePolicy = "no-store"
+	CacheOnlyIfCached CachePolicy = "only-if-cached"
+)
+
+// CacheStore is the pluggable backing store for cached analysis results.
+type CacheStore interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte) error
+}
+
+// AnalysisCache wraps a CacheStore with the content-addressed key derivation
+// used for cached ChunkAnalysis results.
+type AnalysisCache struct {
+	store CacheStore
+}
+
+func NewAnalysisCache(store CacheStore) *AnalysisCache {
+	return &AnalysisCache{store: store}
+}
+
+func analysisCacheKey(systemPrompt, userPrompt, model, version string) string {
+	sum := sha256.Sum256([]byte(systemPrompt + userPrompt + model + version))
+	return hex.EncodeToString(sum[:])
+}
+
+// LRUCache is a bounded in-memory CacheStore. It's the default store used
+// when no external cache (e.g. Redis) is configured.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *LRUCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).value, true, nil
+}
+
+func (c *LRUCache) Set(ctx context.Context, key string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.order.MoveToFront(elem)
+		elem.Value.(*lruEntry).value = value
+		return nil
+	}
+
+	elem := c.order.PushFront(&lruEntry{key: key, value: value})
+	c.items[key] = elem
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruEntry).key)
+		}
+	}
+	return nil
+}
+
+// RedisClient is the minimal surface AnalysisCache needs from a Redis
+// client. Callers wire up their own go-redis (or compatible) client that
+// satisfies this interface rather than this package importing one directly.
+type RedisClient interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+}
+
+// RedisStore adapts a RedisClient to CacheStore.
+type RedisStore struct {
+	client RedisClient
+	ttl    time.Duration
+}
+
+func NewRedisStore(client RedisClient, ttl time.Duration) *RedisStore {
+	return &RedisStore{client: client, ttl: ttl}
+}
+
+func (r *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.client.Get(ctx, key)
+	if err != nil {
+		if err == ErrCacheMiss {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if value == "" {
+		return nil, false, nil
+	}
+	return []byte(value), true, nil
+}
+
+func (r *RedisStore) Set(ctx context.Context, key string, value []byte) error {
+	return r.client.Set(ctx, key, string(value), r.ttl)
+}
+
+// ErrCacheMiss is the sentinel a RedisClient should return from Get when the
+// key isn't present, mirroring go-redis's redis.Nil convention.
+var ErrCacheMiss = fmt.Errorf("cache: key not found")
@@ -5,38 +5,112 @@ import (
 )
 
 type Config struct {
-	MaxChunkSize     int     `json:"max_chunk_size"`
-	MinChunkSize     int     `json:"min_chunk_size"`
-	OverlapSize      int     `json:"overlap_size"`
-	
-	GeminiAPIKey     string  `json:"gemini_api_key"`
-	GeminiModel      string  `json:"gemini_model"`
-	
-	ConfidenceThreshold float64 `json:"confidence_threshold"`
-	BatchSize          int     `json:"batch_size"`
-	MaxRetries         int     `json:"max_retries"`
-	RequestTimeout     time.Duration `json:"request_timeout"`
-	
-	QdrantURL          string `json:"qdrant_url"`
-	QdrantCollection   string `json:"qdrant_collection"`
-	
+	MaxChunkSize int `json:"max_chunk_size"`
+	MinChunkSize int `json:"min_chunk_size"`
+	OverlapSize  int `json:"overlap_size"`
+
+	GeminiAPIKey string `json:"gemini_api_key"`
+	GeminiModel  string `json:"gemini_model"`
+
+	// LLMProvider selects the backend AnalyzeChunk's structured analysis runs
+	// against: "gemini" (default, falls back to GeminiAPIKey/GeminiModel
+	// above when unset), "openai", "anthropic", or "ollama". LLMBaseURL
+	// overrides the provider's default endpoint, needed for Ollama (which has
+	// no public default) and useful for OpenAI/Anthropic-compatible proxies.
+	LLMProvider string `json:"llm_provider"`
+	LLMModel    string `json:"llm_model"`
+	LLMBaseURL  string `json:"llm_base_url"`
+	LLMAPIKey   string `json:"llm_api_key"`
+
+	EmbeddingProvider string `json:"embedding_provider"`
+	EmbeddingModel    string `json:"embedding_model"`
+	EmbeddingAPIKey   string `json:"embedding_api_key"`
+	EmbeddingEndpoint string `json:"embedding_endpoint"`
+
+	SparseEncoderType string `json:"sparse_encoder_type"`
+	SparseStatsPath   string `json:"sparse_stats_path"`
+
+	AnalysisCacheSize int `json:"analysis_cache_size"`
+
+	LateChunkingEnabled      bool `json:"late_chunking_enabled"`
+	LateChunkingWindowTokens int  `json:"late_chunking_window_tokens"`
+
+	// LocalChunkingMode selects a deterministic Chunker that runs in place of
+	// the StructuralSplitter/overlap path in performSemanticChunking: "" (the
+	// default) leaves that path untouched, "fixed_token" packs tiktoken
+	// windows with TokenOverlap tokens of overlap, "recursive_character"
+	// walks a separator hierarchy packing pieces up to MaxChunkSize, and
+	// "semantic" splits on embedding-distance outliers (falling back to
+	// recursive_character for any oversized sentence group).
+	LocalChunkingMode string `json:"local_chunking_mode"`
+
+	// TokenizerEncoding names the tiktoken encoding "fixed_token" mode uses
+	// (e.g. "cl100k_base"). Empty defaults to "cl100k_base".
+	TokenizerEncoding string `json:"tokenizer_encoding"`
+
+	// TokenOverlap is how many tokens consecutive "fixed_token" windows
+	// share.
+	TokenOverlap int `json:"token_overlap"`
+
+	// SemanticWindowSize bounds how many prior sentence-to-sentence
+	// distances "semantic" mode's rolling 95th-percentile threshold is
+	// computed over. Zero defaults to 20.
+	SemanticWindowSize int `json:"semantic_window_size"`
+
+	ConfidenceThreshold float64       `json:"confidence_threshold"`
+	BatchSize           int           `json:"batch_size"`
+	MaxRetries          int           `json:"max_retries"`
+	RequestTimeout      time.Duration `json:"request_timeout"`
+
+	QdrantURL        string `json:"qdrant_url"`
+	QdrantCollection string `json:"qdrant_collection"`
+
 	MaxConcurrentRequests int `json:"max_concurrent_requests"`
-	RateLimitRPS         int `json:"rate_limit_rps"`
+	RateLimitRPS          int `json:"rate_limit_rps"`
+
+	// PeerTransferAddr, if set, is the TransferService address Shutdown
+	// hands off in-flight chunk analysis to during a graceful shutdown.
+	// Leaving it empty disables hand-off; in-flight work is simply dropped.
+	PeerTransferAddr        string        `json:"peer_transfer_addr"`
+	GracefulShutdownTimeout time.Duration `json:"graceful_shutdown_timeout"`
+
+	// WebhookEndpoints maps a document's SourceType (see
+	// models.DocumentMetadata) to the webhook endpoints ChunkingStorageManager
+	// notifies once that document finishes chunking, so external systems that
+	// can't poll storage (Splunk HEC, Elastic ingest, a downstream
+	// orchestrator, the embedding pipeline itself) hear about it in near
+	// real time.
+	WebhookEndpoints map[string][]WebhookEndpointConfig `json:"webhook_endpoints"`
+
+	// ChunkIndexPath is where ChunkingStorageManager persists its
+	// ChunkIndex, the BoltDB-backed content-addressable record of chunks
+	// already written that SaveChunkedDocument/SaveChunksAsIndividualFiles
+	// consult to skip re-storing an unchanged chunk on re-ingestion.
+	ChunkIndexPath string `json:"chunk_index_path"`
 }
 
 func DefaultConfig() *Config {
 	return &Config{
-		MaxChunkSize:          1000,
-		MinChunkSize:          100,
-		OverlapSize:           50,
-		GeminiModel:          "gemini-1.5-pro",
-		ConfidenceThreshold:  0.7,
-		BatchSize:            10,
-		MaxRetries:           3,
-		RequestTimeout:       30 * time.Second,
-		QdrantCollection:     "document_chunks",
-		QdrantURL:           "http://localhost:6333",
-		MaxConcurrentRequests: 5,
-		RateLimitRPS:        10,
+		MaxChunkSize:             1000,
+		MinChunkSize:             100,
+		OverlapSize:              50,
+		GeminiModel:              "gemini-1.5-pro",
+		EmbeddingProvider:        "gemini",
+		EmbeddingModel:           "text-embedding-004",
+		SparseEncoderType:        "bm25",
+		SparseStatsPath:          "bm25_stats.json",
+		AnalysisCacheSize:        10000,
+		LateChunkingEnabled:      false,
+		LateChunkingWindowTokens: 2000,
+		ConfidenceThreshold:      0.7,
+		BatchSize:                10,
+		MaxRetries:               3,
+		RequestTimeout:           30 * time.Second,
+		QdrantCollection:         "document_chunks",
+		QdrantURL:                "http://localhost:6333",
+		MaxConcurrentRequests:    5,
+		RateLimitRPS:             10,
+		GracefulShutdownTimeout:  30 * time.Second,
+		ChunkIndexPath:           "chunk_index.db",
 	}
 }
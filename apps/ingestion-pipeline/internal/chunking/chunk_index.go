@@ -0,0 +1,297 @@
+package chunking
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	chunkIndexBucket = []byte("chunks")
+	chunkIndexMeta   = []byte("meta")
+)
+
+const chunkIndexDocumentsKey = "documents_total"
+
+// ChunkIndexEntry is one content-addressable chunk's bookkeeping: how it's
+// identified in Qdrant, which SourceType it belongs to (for UsageReport's
+// per-type rollup), its size, and how many writes have referenced it.
+type ChunkIndexEntry struct {
+	PointID    string `json:"point_id"`
+	SourceType string `json:"source_type"`
+	Bytes      int    `json:"bytes"`
+	RefCount   int    `json:"ref_count"`
+}
+
+// ChunkIndex is a content-addressable record of every chunk this pipeline
+// has stored, persisted in a small BoltDB file alongside the Manager's
+// own output so it survives a restart. Keys are ChunkKey(text,
+// embeddingModelVersion): re-ingesting a document whose chunks haven't
+// changed resolves to the same key, so ChunkingStorageManager can skip the
+// write and just bump a reference count instead of storing a duplicate,
+// and the point ID recorded under that key is reused as the chunk's Qdrant
+// point ID so re-ingestion is idempotent end to end.
+type ChunkIndex struct {
+	mu sync.Mutex
+	db *bolt.DB
+}
+
+func NewChunkIndex(path string) (*ChunkIndex, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open chunk index at %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(chunkIndexBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(chunkIndexMeta)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create chunk index buckets: %w", err)
+	}
+
+	return &ChunkIndex{db: db}, nil
+}
+
+// ChunkKey returns the content-addressable key that text and
+// embeddingModelVersion map to. Two chunks with identical text destined
+// for the same embedding model version always produce the same key,
+// whatever document or ingestion run they came from; a model version bump
+// deliberately produces a new key, since a re-embedded chunk needs a new
+// vector and shouldn't be treated as a duplicate of its old embedding.
+func ChunkKey(text, embeddingModelVersion string) string {
+	h := sha256.New()
+	h.Write([]byte(text))
+	h.Write([]byte{0})
+	h.Write([]byte(embeddingModelVersion))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Touch records a write of the chunk identified by key. If key is new,
+// entry is inserted with RefCount 1 and hit is false. If key already
+// exists, its stored RefCount is incremented and hit is true, telling the
+// caller the chunk's content was already written and the actual storage
+// write can be skipped. pointID is always the entry on file after the
+// call, so the caller can use it as the chunk's Qdrant point ID whether
+// this was an insert or a hit.
+func (ci *ChunkIndex) Touch(key string, entry ChunkIndexEntry) (pointID string, hit bool, err error) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	err = ci.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(chunkIndexBucket)
+		existing := bucket.Get([]byte(key))
+
+		if existing != nil {
+			var stored ChunkIndexEntry
+			if err := json.Unmarshal(existing, &stored); err != nil {
+				return fmt.Errorf("failed to decode chunk index entry %s: %w", key, err)
+			}
+			stored.RefCount++
+			pointID = stored.PointID
+			hit = true
+
+			data, err := json.Marshal(stored)
+			if err != nil {
+				return err
+			}
+			return bucket.Put([]byte(key), data)
+		}
+
+		entry.RefCount = 1
+		pointID = entry.PointID
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if err := bucket.Put([]byte(key), data); err != nil {
+			return err
+		}
+
+		return incrementMetaCounter(tx, chunkIndexDocumentsKey, 0)
+	})
+
+	return pointID, hit, err
+}
+
+// IncrementDocuments bumps the index's document counter, used by
+// UsageReport's average-chunks-per-document figure. Called once per
+// SaveChunkedDocument call, not once per chunk.
+func (ci *ChunkIndex) IncrementDocuments() error {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	return ci.db.Update(func(tx *bolt.Tx) error {
+		return incrementMetaCounter(tx, chunkIndexDocumentsKey, 1)
+	})
+}
+
+func incrementMetaCounter(tx *bolt.Tx, key string, delta int) error {
+	bucket := tx.Bucket(chunkIndexMeta)
+	current := 0
+	if value := bucket.Get([]byte(key)); value != nil {
+		if err := json.Unmarshal(value, &current); err != nil {
+			return err
+		}
+	}
+
+	if delta == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(current + delta)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(key), data)
+}
+
+// Release decrements key's reference count, e.g. when a document that
+// previously produced this chunk is deleted or re-chunked differently. It
+// never removes the entry itself: Compact does that in one pass so Qdrant
+// deletions can be batched rather than issued one at a time as refcounts
+// hit zero.
+func (ci *ChunkIndex) Release(key string) error {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	return ci.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(chunkIndexBucket)
+		existing := bucket.Get([]byte(key))
+		if existing == nil {
+			return nil
+		}
+
+		var stored ChunkIndexEntry
+		if err := json.Unmarshal(existing, &stored); err != nil {
+			return err
+		}
+		if stored.RefCount > 0 {
+			stored.RefCount--
+		}
+
+		data, err := json.Marshal(stored)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(key), data)
+	})
+}
+
+// SourceTypeUsage is one SourceType's contribution to a UsageReport.
+type SourceTypeUsage struct {
+	UniqueChunks int   `json:"unique_chunks"`
+	Bytes        int64 `json:"bytes"`
+	References   int   `json:"references"`
+}
+
+// UsageReport summarizes the index's current state: per-SourceType
+// storage counts, overall totals, and a dedup ratio (how much repeat
+// writes outnumber the unique chunks they resolved to — 1.0 means no
+// re-ingestion has ever produced a repeat write).
+type UsageReport struct {
+	BySourceType        map[string]SourceTypeUsage `json:"by_source_type"`
+	TotalUniqueChunks   int                        `json:"total_unique_chunks"`
+	TotalBytes          int64                      `json:"total_bytes"`
+	DedupRatio          float64                    `json:"dedup_ratio"`
+	AverageChunksPerDoc float64                    `json:"average_chunks_per_document"`
+}
+
+// UsageReport walks the index and rolls it up into a UsageReport.
+func (ci *ChunkIndex) UsageReport() (UsageReport, error) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	report := UsageReport{BySourceType: make(map[string]SourceTypeUsage)}
+	totalReferences := 0
+	documents := 0
+
+	err := ci.db.View(func(tx *bolt.Tx) error {
+		if value := tx.Bucket(chunkIndexMeta).Get([]byte(chunkIndexDocumentsKey)); value != nil {
+			if err := json.Unmarshal(value, &documents); err != nil {
+				return err
+			}
+		}
+
+		return tx.Bucket(chunkIndexBucket).ForEach(func(_, value []byte) error {
+			var entry ChunkIndexEntry
+			if err := json.Unmarshal(value, &entry); err != nil {
+				return err
+			}
+
+			usage := report.BySourceType[entry.SourceType]
+			usage.UniqueChunks++
+			usage.Bytes += int64(entry.Bytes)
+			usage.References += entry.RefCount
+			report.BySourceType[entry.SourceType] = usage
+
+			report.TotalUniqueChunks++
+			report.TotalBytes += int64(entry.Bytes)
+			totalReferences += entry.RefCount
+			return nil
+		})
+	})
+	if err != nil {
+		return UsageReport{}, err
+	}
+
+	if report.TotalUniqueChunks > 0 {
+		report.DedupRatio = float64(totalReferences) / float64(report.TotalUniqueChunks)
+	}
+	if documents > 0 {
+		report.AverageChunksPerDoc = float64(report.TotalUniqueChunks) / float64(documents)
+	}
+
+	return report, nil
+}
+
+// Compact walks the index, removes every entry whose RefCount has dropped
+// to zero, and returns the Qdrant point IDs those entries held so the
+// caller can issue the matching delete calls, keeping vector storage in
+// sync with the file store.
+func (ci *ChunkIndex) Compact() (deletedPointIDs []string, err error) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	err = ci.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(chunkIndexBucket)
+		var staleKeys [][]byte
+
+		scanErr := bucket.ForEach(func(key, value []byte) error {
+			var entry ChunkIndexEntry
+			if err := json.Unmarshal(value, &entry); err != nil {
+				return err
+			}
+			if entry.RefCount <= 0 {
+				staleKeys = append(staleKeys, append([]byte(nil), key...))
+				deletedPointIDs = append(deletedPointIDs, entry.PointID)
+			}
+			return nil
+		})
+		if scanErr != nil {
+			return scanErr
+		}
+
+		for _, key := range staleKeys {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return deletedPointIDs, err
+}
+
+func (ci *ChunkIndex) Close() error {
+	return ci.db.Close()
+}
@@ -3,115 +3,205 @@ package chunking
 import (
 	"context"
 	"crypto/md5"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode/utf8"
 
-	"github.com/NEMYSESx/orbit/apps/ingestion-pipeline/internal/models"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/qdrant"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/transfer"
 	"golang.org/x/time/rate"
 )
 
+// ErrDraining is returned by ProcessDocument once Shutdown has begun, and by
+// documents whose analysis was interrupted mid-flight by a shutdown.
+var ErrDraining = errors.New("agentic chunker is draining for shutdown")
+
+// inFlightDocument tracks a document's progress through analyzeChunksWithAI
+// so that Shutdown can hand off whatever chunks completed before the
+// shutdown signal, along with a resumption cursor, to a peer instance.
+type inFlightDocument struct {
+	mu        sync.Mutex
+	document  *models.ExtractedContent
+	total     int
+	completed []ChunkPayload
+}
+
+func (d *inFlightDocument) addCompleted(chunk ChunkPayload) {
+	d.mu.Lock()
+	d.completed = append(d.completed, chunk)
+	d.mu.Unlock()
+}
+
 type AgenticChunker struct {
-	config      *Config
-	geminiClient *GeminiClient
-	rateLimiter *rate.Limiter
-	semaphore   chan struct{}
+	config        *Config
+	llmAnalyzer   LLMAnalyzer
+	embedder      Embedder
+	sparseEncoder SparseEncoder
+	qdrantClient  *qdrant.Client
+	analysisCache *AnalysisCache
+	rateLimiter   *rate.Limiter
+	semaphore     chan struct{}
+
+	// localChunker, when non-nil (config.LocalChunkingMode set),
+	// replaces the StructuralSplitter/overlap path in
+	// performSemanticChunking with a deterministic fixed_token,
+	// recursive_character, or semantic split.
+	localChunker Chunker
+
+	draining atomic.Bool
+	inFlight sync.Map // source ID -> *inFlightDocument
 }
 
 func NewAgenticChunker(config *Config) *AgenticChunker {
+	embedder, err := NewEmbedder(config)
+	if err != nil {
+		log.Printf("failed to initialize embedder, falling back to Gemini default: %v", err)
+		embedder = NewGeminiEmbedder(config.GeminiAPIKey, config.EmbeddingModel, config.RequestTimeout)
+	}
+
+	sparseEncoder, err := NewSparseEncoder(config)
+	if err != nil {
+		log.Printf("failed to initialize sparse encoder, continuing dense-only: %v", err)
+		sparseEncoder = nil
+	}
+
+	sparseVectorName := ""
+	if sparseEncoder != nil {
+		sparseVectorName = "sparse"
+	}
+
+	llmAnalyzer, err := NewLLMAnalyzer(config)
+	if err != nil {
+		log.Printf("failed to initialize LLM analyzer, falling back to Gemini default: %v", err)
+		llmAnalyzer = NewGeminiClient(config.GeminiAPIKey, config.GeminiModel, config.RequestTimeout)
+	}
+
+	localChunker, err := NewChunker(config, embedder)
+	if err != nil {
+		log.Printf("failed to initialize local chunker, falling back to structural splitter: %v", err)
+		localChunker = nil
+	}
+
+	qdrantClient := qdrant.NewClient(qdrant.Config{
+		BaseURL:          config.QdrantURL,
+		Collection:       config.QdrantCollection,
+		VectorSize:       embedder.Dimensions(),
+		Distance:         "Cosine",
+		SparseVectorName: sparseVectorName,
+		BatchSize:        config.BatchSize,
+		MaxRetries:       config.MaxRetries,
+		RequestTimeout:   config.RequestTimeout,
+	})
+
 	return &AgenticChunker{
-		config:       config,
-		geminiClient: NewGeminiClient(config.GeminiAPIKey, config.GeminiModel, config.RequestTimeout),
-		rateLimiter:  rate.NewLimiter(rate.Limit(config.RateLimitRPS), config.RateLimitRPS),
-		semaphore:    make(chan struct{}, config.MaxConcurrentRequests),
+		config:        config,
+		llmAnalyzer:   llmAnalyzer,
+		embedder:      embedder,
+		sparseEncoder: sparseEncoder,
+		qdrantClient:  qdrantClient,
+		analysisCache: NewAnalysisCache(NewLRUCache(config.AnalysisCacheSize)),
+		rateLimiter:   rate.NewLimiter(rate.Limit(config.RateLimitRPS), config.RateLimitRPS),
+		semaphore:     make(chan struct{}, config.MaxConcurrentRequests),
+		localChunker:  localChunker,
 	}
 }
 
-func (ac *AgenticChunker) ProcessDocument(ctx context.Context, document *models.ExtractedContent) (*ChunkedDocument, error) {
+func (ac *AgenticChunker) ProcessDocument(ctx context.Context, document *models.ExtractedContent, policy CachePolicy) (*ChunkedDocument, error) {
+	if ac.draining.Load() {
+		return nil, fmt.Errorf("%w: not accepting new documents", ErrDraining)
+	}
+
 	startTime := time.Now()
-	
+
 	log.Printf("Starting agentic chunking for document: %s", document.Metadata.ID)
 
-	chunks, err := ac.performSemanticChunking(document.CleanText)
+	chunks, err := ac.performSemanticChunking(ctx, document)
 	if err != nil {
 		return nil, fmt.Errorf("semantic chunking failed: %w", err)
 	}
 
-	analyzedChunks, err := ac.analyzeChunksWithAI(ctx, chunks, document)
+	inflight := &inFlightDocument{document: document, total: len(chunks)}
+	ac.inFlight.Store(document.Metadata.SourceID, inflight)
+	defer ac.inFlight.Delete(document.Metadata.SourceID)
+
+	analyzedChunks, cacheHits, cacheMisses, err := ac.analyzeChunksWithAI(ctx, chunks, document, policy, inflight)
 	if err != nil {
 		return nil, fmt.Errorf("AI analysis failed: %w", err)
 	}
 
-	qdrantPoints, err := ac.generateQdrantPoints(analyzedChunks, document)
+	if len(analyzedChunks) < len(chunks) {
+		return nil, fmt.Errorf("%w: %d/%d chunks analyzed before shutdown, handed off to peer", ErrDraining, len(analyzedChunks), len(chunks))
+	}
+
+	qdrantPoints, err := ac.generateQdrantPoints(ctx, analyzedChunks, document)
 	if err != nil {
 		return nil, fmt.Errorf("point generation failed: %w", err)
 	}
 
+	if err := ac.persistPoints(ctx, qdrantPoints); err != nil {
+		return nil, fmt.Errorf("qdrant persist failed: %w", err)
+	}
+
 	processingDuration := time.Since(startTime)
 	summary := ac.calculateProcessingSummary(qdrantPoints, processingDuration)
+	summary.CacheHits = cacheHits
+	summary.CacheMisses = cacheMisses
 
 	result := &ChunkedDocument{
 		OriginalDocument:  *document,
-		Chunks:           qdrantPoints,
+		Chunks:            qdrantPoints,
 		ProcessingSummary: summary,
 	}
 
-	log.Printf("Successfully processed document %s: %d chunks in %v", 
+	log.Printf("Successfully processed document %s: %d chunks in %v",
 		document.Metadata.ID, len(qdrantPoints), processingDuration)
 
 	return result, nil
 }
 
-func (ac *AgenticChunker) performSemanticChunking(text string) ([]string, error) {
-	paragraphs := ac.splitByParagraphs(text)
-	
-	var chunks []string
-	var currentChunk strings.Builder
-	currentTokens := 0
-
-	for _, paragraph := range paragraphs {
-		sentences := ac.splitBySentences(paragraph)
-		
-		for _, sentence := range sentences {
-			sentenceTokens := ac.estimateTokenCount(sentence)
-			
-			if currentTokens+sentenceTokens > ac.config.MaxChunkSize && currentChunk.Len() > 0 {
-				if currentTokens >= ac.config.MinChunkSize {
-					chunks = append(chunks, strings.TrimSpace(currentChunk.String()))
-				}
-				
-				currentChunk.Reset()
-				currentTokens = 0
-			}
-			
-			if currentChunk.Len() > 0 {
-				currentChunk.WriteString(" ")
-			}
-			currentChunk.WriteString(sentence)
-			currentTokens += sentenceTokens
+// performSemanticChunking dispatches to ac.localChunker when
+// config.LocalChunkingMode selected one, or else to the format-aware
+// StructuralSplitter based on the document's content type. Prose documents
+// keep the original sentence-overlap behavior (except when
+// LateChunkingEnabled, which skips overlap so lateChunkEmbed can find each
+// chunk verbatim in the document); markdown and code documents
+// are split on heading/function boundaries instead and carry structural
+// breadcrumbs, so they skip overlap (blending text across a heading or
+// function boundary isn't useful context).
+func (ac *AgenticChunker) performSemanticChunking(ctx context.Context, document *models.ExtractedContent) ([]StructuralChunk, error) {
+	if ac.localChunker != nil {
+		chunks, err := ac.localChunker.Chunk(ctx, document.CleanText)
+		if err != nil {
+			return nil, fmt.Errorf("local chunker failed: %w", err)
 		}
+		return chunks, nil
 	}
 
-	if currentChunk.Len() > 0 && currentTokens >= ac.config.MinChunkSize {
-		chunks = append(chunks, strings.TrimSpace(currentChunk.String()))
+	contentType := document.Metadata.ContentType
+	splitter := NewStructuralSplitter(ac.config)
+
+	if isMarkdownContentType(contentType) || isCodeContentType(contentType) {
+		return splitter.Split(contentType, document.CleanText), nil
 	}
 
-	return ac.applyOverlap(chunks), nil
-}
+	prose := splitter.splitProseText(document.CleanText)
+	if !ac.config.LateChunkingEnabled {
+		prose = ac.applyOverlap(prose)
+	}
 
-func (ac *AgenticChunker) splitByParagraphs(text string) []string {
-	paragraphs := regexp.MustCompile(`\n\s*\n`).Split(text, -1)
-	var result []string
-	for _, p := range paragraphs {
-		if trimmed := strings.TrimSpace(p); trimmed != "" {
-			result = append(result, trimmed)
-		}
+	chunks := make([]StructuralChunk, len(prose))
+	for i, text := range prose {
+		chunks[i] = StructuralChunk{Text: text, Role: "paragraph"}
 	}
-	return result
+	return chunks, nil
 }
 
 func (ac *AgenticChunker) splitBySentences(text string) []string {
@@ -160,11 +250,13 @@ func (ac *AgenticChunker) applyOverlap(chunks []string) []string {
 	return overlappedChunks
 }
 
-func (ac *AgenticChunker) analyzeChunksWithAI(ctx context.Context, chunks []string, document *models.ExtractedContent) ([]ChunkPayload, error) {
+func (ac *AgenticChunker) analyzeChunksWithAI(ctx context.Context, chunks []StructuralChunk, document *models.ExtractedContent, policy CachePolicy, inflight *inFlightDocument) ([]ChunkPayload, int, int, error) {
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var analyzedChunks []ChunkPayload
 	var errors []error
+	cacheHits := 0
+	cacheMisses := 0
 
 	systemPrompt := `You are an expert document analysis agent. Analyze the given text chunk and provide structured metadata.
 
@@ -180,9 +272,29 @@ Respond with valid JSON only, no additional text.`
 
 	for i, chunk := range chunks {
 		wg.Add(1)
-		go func(index int, chunkText string) {
+		go func(index int, sc StructuralChunk) {
 			defer wg.Done()
 
+			// Once draining begins, stop starting new per-chunk analysis so
+			// the semaphore can empty out; whatever hasn't started yet is
+			// left for Shutdown to hand off to a peer.
+			if ac.draining.Load() {
+				return
+			}
+
+			chunkText := sc.Text
+
+			if sc.Role != "" && sc.Role != "paragraph" {
+				analysis := ac.structuralAnalysis(sc, chunkText)
+				chunkPayload := ac.buildChunkPayload(document, index, chunkText, sc.Breadcrumb, analysis)
+
+				mu.Lock()
+				analyzedChunks = append(analyzedChunks, chunkPayload)
+				mu.Unlock()
+				inflight.addCompleted(chunkPayload)
+				return
+			}
+
 			ac.semaphore <- struct{}{}
 			defer func() { <-ac.semaphore }()
 
@@ -211,46 +323,69 @@ Analyze this chunk and respond with JSON in this exact format:
     "agent_confidence": 0.0-1.0
 }`, document.Metadata.Title, document.Metadata.SourceType, index+1, len(chunks), chunkText)
 
+			cacheKey := analysisCacheKey(systemPrompt, userPrompt, ac.llmAnalyzer.Model(), "1.0")
+
 			var analysis *ChunkAnalysis
 			var err error
-
-			for attempt := 0; attempt < ac.config.MaxRetries; attempt++ {
-				analysis, err = ac.geminiClient.AnalyzeChunk(ctx, systemPrompt, userPrompt)
-				if err == nil {
-					break
+			hit := false
+
+			if policy != CacheNoCache && policy != CacheNoStore {
+				if cached, found, cacheErr := ac.analysisCache.store.Get(ctx, cacheKey); cacheErr == nil && found {
+					var cachedAnalysis ChunkAnalysis
+					if jsonErr := json.Unmarshal(cached, &cachedAnalysis); jsonErr == nil {
+						analysis = &cachedAnalysis
+						hit = true
+					}
 				}
-				time.Sleep(time.Duration(attempt+1) * time.Second)
 			}
 
-			if err != nil {
-				log.Printf("Failed to analyze chunk %d after %d attempts: %v", index, ac.config.MaxRetries, err)
-				analysis = ac.createDefaultAnalysis(chunkText)
+			if analysis == nil && policy == CacheOnlyIfCached {
+				mu.Lock()
+				errors = append(errors, fmt.Errorf("cache miss for chunk %d with only-if-cached policy", index))
+				cacheMisses++
+				mu.Unlock()
+				return
+			}
+
+			if analysis == nil {
+				for attempt := 0; attempt < ac.config.MaxRetries; attempt++ {
+					analysis, err = ac.llmAnalyzer.AnalyzeChunk(ctx, systemPrompt, userPrompt)
+					if err == nil {
+						break
+					}
+					time.Sleep(time.Duration(attempt+1) * time.Second)
+				}
+
+				if err != nil {
+					log.Printf("Failed to analyze chunk %d after %d attempts: %v", index, ac.config.MaxRetries, err)
+					analysis = ac.createDefaultAnalysis(chunkText)
+				}
 			}
 
 			analysis = ac.validateAnalysis(analysis, chunkText)
 
-			chunkPayload := ChunkPayload{
-				Text:             chunkText,
-				SourceID:         document.Metadata.SourceID,
-				SourceType:       document.Metadata.SourceType,
-				Title:            document.Metadata.Title,
-				Filepath:         document.Metadata.Filepath,
-				LastModifiedDate: document.Metadata.LastModifiedDate,
-				SourceLocation: SourceLocation{
-					ChunkIndex: &index,
-				},
-				Analysis: *analysis,
-				ProcessingMetadata: ProcessingMetadata{
-					ChunkID:           ac.generateChunkID(document.Metadata.ID, index),
-					ProcessedAt:       time.Now(),
-					ProcessingVersion: "1.0",
-					TokenCount:        ac.estimateTokenCount(chunkText),
-				},
+			if policy != CacheNoStore {
+				if encoded, encErr := json.Marshal(analysis); encErr == nil {
+					if setErr := ac.analysisCache.store.Set(ctx, cacheKey, encoded); setErr != nil {
+						log.Printf("failed to write analysis cache for chunk %d: %v", index, setErr)
+					}
+				}
+			}
+
+			mu.Lock()
+			if hit {
+				cacheHits++
+			} else {
+				cacheMisses++
 			}
+			mu.Unlock()
+
+			chunkPayload := ac.buildChunkPayload(document, index, chunkText, sc.Breadcrumb, analysis)
 
 			mu.Lock()
 			analyzedChunks = append(analyzedChunks, chunkPayload)
 			mu.Unlock()
+			inflight.addCompleted(chunkPayload)
 		}(i, chunk)
 	}
 
@@ -260,7 +395,52 @@ Analyze this chunk and respond with JSON in this exact format:
 		log.Printf("Encountered %d errors during chunk analysis", len(errors))
 	}
 
-	return analyzedChunks, nil
+	return analyzedChunks, cacheHits, cacheMisses, nil
+}
+
+// structuralAnalysis builds a ChunkAnalysis directly from a structurally
+// certain role (heading, table_data, code_snippet) without calling the LLM,
+// since the splitter already knows what the chunk is.
+func (ac *AgenticChunker) structuralAnalysis(sc StructuralChunk, chunkText string) *ChunkAnalysis {
+	summary := sc.Breadcrumb.TableCaption
+	if summary == "" && sc.Breadcrumb.FunctionName != "" {
+		summary = "function " + sc.Breadcrumb.FunctionName
+	}
+	if summary == "" && len(sc.Breadcrumb.HeadingPath) > 0 {
+		summary = strings.Join(sc.Breadcrumb.HeadingPath, " > ")
+	}
+
+	return &ChunkAnalysis{
+		ChunkRole:              sc.Role,
+		KeyEntities:            ac.extractSimpleEntities(chunkText),
+		Topics:                 []string{"structural"},
+		OriginalContextSummary: summary,
+		LevelOfDetail:          "specific",
+		AgentConfidence:        0.95,
+		ProcessingTimestamp:    time.Now(),
+	}
+}
+
+func (ac *AgenticChunker) buildChunkPayload(document *models.ExtractedContent, index int, chunkText string, breadcrumb SourceLocation, analysis *ChunkAnalysis) ChunkPayload {
+	location := breadcrumb
+	location.ChunkIndex = &index
+
+	return ChunkPayload{
+		Text:             chunkText,
+		SourceID:         document.Metadata.SourceID,
+		SourceType:       document.Metadata.SourceType,
+		Title:            document.Metadata.Title,
+		Filepath:         document.Metadata.Filepath,
+		LastModifiedDate: document.Metadata.LastModifiedDate,
+		SourceLocation:   location,
+		Analysis:         *analysis,
+		ProcessingMetadata: ProcessingMetadata{
+			ChunkID:           ac.generateChunkID(document.Metadata.ID, index),
+			ProcessedAt:       time.Now(),
+			ProcessingVersion: "1.0",
+			TokenCount:        ac.estimateTokenCount(chunkText),
+		},
+	}
 }
 
 func (ac *AgenticChunker) validateAnalysis(analysis *ChunkAnalysis, chunkText string) *ChunkAnalysis {
@@ -309,12 +489,12 @@ func (ac *AgenticChunker) validateAnalysis(analysis *ChunkAnalysis, chunkText st
 func (ac *AgenticChunker) createDefaultAnalysis(chunkText string) *ChunkAnalysis {
 	return &ChunkAnalysis{
 		ChunkRole:              "paragraph",
-		KeyEntities:           ac.extractSimpleEntities(chunkText),
-		Topics:                []string{"general"},
+		KeyEntities:            ac.extractSimpleEntities(chunkText),
+		Topics:                 []string{"general"},
 		OriginalContextSummary: chunkText[:min(200, len(chunkText))],
-		LevelOfDetail:         "detailed",
-		AgentConfidence:       0.3,
-		ProcessingTimestamp:   time.Now(),
+		LevelOfDetail:          "detailed",
+		AgentConfidence:        0.3,
+		ProcessingTimestamp:    time.Now(),
 	}
 }
 
@@ -324,27 +504,211 @@ func (ac *AgenticChunker) extractSimpleEntities(text string) []string {
 	return matches
 }
 
-func (ac *AgenticChunker) generateQdrantPoints(chunks []ChunkPayload, document *models.ExtractedContent) ([]QdrantPoint, error) {
-	var points []QdrantPoint
+func (ac *AgenticChunker) generateQdrantPoints(ctx context.Context, chunks []ChunkPayload, document *models.ExtractedContent) ([]QdrantPoint, error) {
+	vectors, err := ac.embedChunkVectors(ctx, chunks, document)
+	if err != nil {
+		return nil, fmt.Errorf("embedding generation failed: %w", err)
+	}
 
+	points := make([]QdrantPoint, len(chunks))
 	for i, chunk := range chunks {
-		vector := make([]float64, 384)
-		for j := range vector {
-			vector[j] = float64(i) / float64(len(chunks)) 
-		}
+		chunk.ProcessingMetadata.EmbeddingModel = ac.embedder.Model()
+		chunk.ProcessingMetadata.EmbeddingDimension = len(vectors[i])
 
-		point := QdrantPoint{
-			ID:      chunk.ProcessingMetadata.ChunkID,
-			Vector:  vector,
-			Payload: chunk,
+		if ac.sparseEncoder != nil {
+			sparse, err := ac.sparseEncoder.Encode(chunk.SourceType, chunk.Text)
+			if err != nil {
+				log.Printf("failed to compute sparse vector for chunk %s: %v", chunk.ProcessingMetadata.ChunkID, err)
+			} else {
+				chunk.SparseVector = sparse
+			}
 		}
 
-		points = append(points, point)
+		points[i] = QdrantPoint{
+			ID:           chunk.ProcessingMetadata.ChunkID,
+			Vector:       vectors[i],
+			SparseVector: chunk.SparseVector,
+			Payload:      chunk,
+		}
 	}
 
 	return points, nil
 }
 
+// persistPoints bootstraps the target collection (idempotent, cheap to
+// call repeatedly) and upserts points in the client's configured batch
+// size, keyed by ProcessingMetadata.ChunkID so reprocessing a document is
+// idempotent rather than appending duplicates.
+func (ac *AgenticChunker) persistPoints(ctx context.Context, points []QdrantPoint) error {
+	if ac.qdrantClient == nil || len(points) == 0 {
+		return nil
+	}
+
+	if err := ac.qdrantClient.EnsureCollection(ctx); err != nil {
+		return fmt.Errorf("failed to ensure qdrant collection: %w", err)
+	}
+
+	upserts := make([]qdrant.Point, len(points))
+	for i, point := range points {
+		payload, err := chunkPayloadToMap(point.Payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode payload for chunk %s: %w", point.ID, err)
+		}
+
+		upsert := qdrant.Point{
+			ID:      point.ID,
+			Vectors: map[string][]float64{"dense": point.Vector},
+			Payload: payload,
+		}
+
+		if len(point.SparseVector.Indices) > 0 {
+			upsert.Sparse = map[string]qdrant.SparseVector{
+				"sparse": {
+					Indices: point.SparseVector.Indices,
+					Values:  point.SparseVector.Values,
+				},
+			}
+		}
+
+		upserts[i] = upsert
+	}
+
+	if err := ac.qdrantClient.Upsert(ctx, upserts...); err != nil {
+		return fmt.Errorf("failed to upsert points: %w", err)
+	}
+
+	return ac.qdrantClient.Flush(ctx)
+}
+
+func chunkPayloadToMap(payload ChunkPayload) (map[string]interface{}, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// embedChunkVectors produces one vector per chunk, using the late-chunking
+// mode when enabled and falling back to per-chunk embedding for any chunk
+// late chunking couldn't resolve a token span for (and entirely when the
+// mode is disabled).
+func (ac *AgenticChunker) embedChunkVectors(ctx context.Context, chunks []ChunkPayload, document *models.ExtractedContent) ([][]float64, error) {
+	if !ac.config.LateChunkingEnabled {
+		return ac.embedChunks(ctx, chunks)
+	}
+
+	vectors, err := ac.lateChunkEmbed(ctx, document.CleanText, chunks)
+	if err != nil {
+		return nil, fmt.Errorf("late chunking embedding failed: %w", err)
+	}
+
+	var missingChunks []ChunkPayload
+	var missingIndices []int
+	for i, v := range vectors {
+		if v == nil {
+			missingChunks = append(missingChunks, chunks[i])
+			missingIndices = append(missingIndices, i)
+		}
+	}
+
+	if len(missingChunks) == 0 {
+		return vectors, nil
+	}
+
+	log.Printf("late chunking couldn't resolve %d/%d chunks, falling back to per-chunk embedding", len(missingChunks), len(chunks))
+	fallbackVectors, err := ac.embedChunks(ctx, missingChunks)
+	if err != nil {
+		return nil, fmt.Errorf("fallback embedding failed: %w", err)
+	}
+
+	for i, idx := range missingIndices {
+		vectors[idx] = fallbackVectors[i]
+	}
+	return vectors, nil
+}
+
+// embedChunks embeds chunk text in batches of ac.config.BatchSize, reusing
+// the same semaphore/rate limiter as analyzeChunksWithAI and retrying each
+// batch with the same backoff schedule.
+func (ac *AgenticChunker) embedChunks(ctx context.Context, chunks []ChunkPayload) ([][]float64, error) {
+	batchSize := ac.config.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(chunks)
+	}
+	if batchSize == 0 {
+		return nil, nil
+	}
+
+	vectors := make([][]float64, len(chunks))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for start := 0; start < len(chunks); start += batchSize {
+		end := start + batchSize
+		if end > len(chunks) {
+			end = len(chunks)
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+
+			ac.semaphore <- struct{}{}
+			defer func() { <-ac.semaphore }()
+
+			if err := ac.rateLimiter.Wait(ctx); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("rate limit error embedding chunks %d-%d: %w", start, end, err)
+				}
+				mu.Unlock()
+				return
+			}
+
+			texts := make([]string, end-start)
+			for i := start; i < end; i++ {
+				texts[i-start] = chunks[i].Text
+			}
+
+			var batchVectors [][]float64
+			var err error
+			for attempt := 0; attempt < ac.config.MaxRetries; attempt++ {
+				batchVectors, err = ac.embedder.Embed(ctx, texts)
+				if err == nil {
+					break
+				}
+				time.Sleep(time.Duration(attempt+1) * time.Second)
+			}
+
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to embed chunks %d-%d after %d attempts: %w", start, end, ac.config.MaxRetries, err)
+				}
+				mu.Unlock()
+				return
+			}
+
+			for i, v := range batchVectors {
+				vectors[start+i] = v
+			}
+		}(start, end)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return vectors, nil
+}
+
 func (ac *AgenticChunker) generateChunkID(documentID string, chunkIndex int) string {
 	return fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("%s_%d", documentID, chunkIndex))))
 }
@@ -380,3 +744,76 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+// Shutdown drains the chunker for a graceful restart: it stops accepting new
+// documents, waits for every in-flight chunk analysis to release its
+// semaphore slot, and then hands any document that didn't finish analyzing
+// off to a peer instance over TransferService, along with a resumption
+// cursor so the peer can pick up where this instance left off instead of
+// re-running Tika extraction and re-analyzing already-completed chunks.
+//
+// ctx should carry a deadline (config.GracefulShutdownTimeout is the
+// suggested one); Shutdown gives up on draining the semaphore once ctx is
+// done.
+func (ac *AgenticChunker) Shutdown(ctx context.Context) error {
+	ac.draining.Store(true)
+
+	for i := 0; i < cap(ac.semaphore); i++ {
+		select {
+		case ac.semaphore <- struct{}{}:
+		case <-ctx.Done():
+			return fmt.Errorf("timed out draining semaphore: %w", ctx.Err())
+		}
+	}
+
+	if ac.config.PeerTransferAddr == "" {
+		return nil
+	}
+
+	client, err := transfer.NewClient(ac.config.PeerTransferAddr)
+	if err != nil {
+		return fmt.Errorf("failed to reach transfer peer: %w", err)
+	}
+	defer client.Close()
+
+	var transferErrs []error
+	ac.inFlight.Range(func(_, value interface{}) bool {
+		doc := value.(*inFlightDocument)
+
+		doc.mu.Lock()
+		completed := append([]ChunkPayload{}, doc.completed...)
+		total := doc.total
+		document := doc.document
+		doc.mu.Unlock()
+
+		if len(completed) >= total {
+			return true
+		}
+
+		completedJSON, err := json.Marshal(completed)
+		if err != nil {
+			transferErrs = append(transferErrs, fmt.Errorf("failed to marshal in-flight chunks for %s: %w", document.Metadata.SourceID, err))
+			return true
+		}
+
+		_, err = client.TransferChunks(ctx, &transfer.TransferChunksRequest{
+			SourceID:            document.Metadata.SourceID,
+			SourceType:          document.Metadata.SourceType,
+			Title:               document.Metadata.Title,
+			Filepath:            document.Metadata.Filepath,
+			OriginalText:        document.CleanText,
+			CompletedChunksJSON: completedJSON,
+			ResumptionCursor:    int32(len(completed)),
+			TotalChunks:         int32(total),
+		})
+		if err != nil {
+			transferErrs = append(transferErrs, fmt.Errorf("failed to transfer in-flight document %s: %w", document.Metadata.SourceID, err))
+		}
+		return true
+	})
+
+	if len(transferErrs) > 0 {
+		return fmt.Errorf("shutdown transfer errors: %v", transferErrs)
+	}
+	return nil
+}
@@ -1,29 +1,77 @@
 package chunking
 
 import (
-	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-type AgenticChunker struct {
-	apiKey             string
-	baseURL            string
-	client             *http.Client
+// defaultSpilloverThreshold is the default chunk size, in bytes, above which
+// a configured object store externalizes the chunk body instead of
+// inlining it in the Kafka message.
+const defaultSpilloverThreshold = 900 * 1024
+
+type KafkaAgenticChunker struct {
+	llmBackend         LLMBackend
 	maxRetries         int
 	sectionSize        int
 	maxCharsPerSection int
 	kafkaProducer      *kafka.Producer
 	kafkaTopic         string
+	bootstrapServers   string
+	kafkaSecurity      models.KafkaSecurityConfig
+
+	// adminServer serves /metrics and /debug/pprof/* when StartAdminServer
+	// has been called. Nil means no admin server is running.
+	adminServer *http.Server
+
+	objectStore        *minio.Client
+	objectStoreBucket  string
+	spilloverStrategy  string
+	spilloverThreshold int
+
+	oauthRefresher *producerOAuthRefresher
+
+	// maxSectionRetries bounds the per-section retry loop in
+	// processSectionWithRetry, kept separate from maxRetries (the Gemini/LLM
+	// API-level retry loop inside callLLM). deadLetterTopic/retryTopic default
+	// to "<kafkaTopic>.dlq"/".retry" once InitializeKafkaStreaming learns the
+	// topic, unless a config override was already set.
+	maxSectionRetries int
+	deadLetterTopic   string
+	retryTopic        string
+
+	processedCount    atomic.Int64
+	retriedCount      atomic.Int64
+	deadLetteredCount atomic.Int64
+
+	// schemaRegistryConfig is captured at construction and turned into
+	// serializer once InitializeKafkaStreaming knows the topic a subject
+	// name is derived from. A nil serializer means streamChunkToKafka falls
+	// back to plain json.Marshal.
+	schemaRegistryConfig models.SchemaRegistryConfig
+	serializer           Serializer
+
+	// transactional gates exactly-once section streaming: when set, worker
+	// creates its own transactional producer (see newTransactionalProducer)
+	// instead of sharing kafkaProducer, and produceSectionTransactionally
+	// wraps a section's chunks in a single Kafka transaction.
+	transactional bool
 }
 
 type sectionJob struct {
@@ -38,44 +86,293 @@ type sectionResult struct {
 	index  int
 }
 
-func NewAgenticChunker(config models.ChunkingConfig) *AgenticChunker {
-	return &AgenticChunker{
-		apiKey:  config.GeminiAPIKey,
-		baseURL: "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash-lite:generateContent",
-		client: &http.Client{
-			Timeout: 60 * time.Second,
-		},
-		maxRetries:         3,
-		maxCharsPerSection: 12000,
+func NewKafkaAgenticChunker(config models.ChunkingConfig) *KafkaAgenticChunker {
+	if config.RequestTimeout <= 0 {
+		config.RequestTimeout = 60 * time.Second
+	}
+
+	ac := &KafkaAgenticChunker{
+		maxRetries:           3,
+		maxCharsPerSection:   12000,
+		spilloverStrategy:    config.ObjectStore.Strategy,
+		spilloverThreshold:   config.ObjectStore.ThresholdBytes,
+		maxSectionRetries:    3,
+		deadLetterTopic:      config.DeadLetterTopic,
+		retryTopic:           config.RetryTopic,
+		schemaRegistryConfig: config.SchemaRegistry,
+		transactional:        config.Transactional,
+	}
+
+	backend, err := NewLLMBackend(config)
+	if err != nil {
+		fmt.Printf("failed to initialize LLM backend, chunking will fail until reconfigured: %v\n", err)
+	} else {
+		ac.llmBackend = backend
+	}
+
+	if ac.spilloverThreshold <= 0 {
+		ac.spilloverThreshold = defaultSpilloverThreshold
+	}
+
+	if config.ObjectStore.Endpoint != "" {
+		client, err := minio.New(config.ObjectStore.Endpoint, &minio.Options{
+			Creds:  credentials.NewStaticV4(config.ObjectStore.AccessKeyID, config.ObjectStore.SecretAccessKey, ""),
+			Secure: config.ObjectStore.UseSSL,
+		})
+		if err != nil {
+			fmt.Printf("failed to initialize object store client, chunks will stream inline: %v\n", err)
+		} else {
+			ac.objectStore = client
+			ac.objectStoreBucket = config.ObjectStore.Bucket
+		}
+	}
+
+	return ac
+}
+
+// shouldExternalize reports whether a chunk of textBytes should be spilled
+// to object storage instead of inlined in its Kafka message, per
+// spilloverStrategy: "inline_only" never does, "always_external" always
+// does, and anything else (the default, "spillover") does only once
+// textBytes exceeds spilloverThreshold. An unconfigured object store always
+// forces inline, regardless of strategy.
+func (ac *KafkaAgenticChunker) shouldExternalize(textBytes int) bool {
+	if ac.objectStore == nil {
+		return false
+	}
+
+	switch ac.spilloverStrategy {
+	case "inline_only":
+		return false
+	case "always_external":
+		return true
+	default:
+		return textBytes > ac.spilloverThreshold
+	}
+}
+
+// uploadChunkPayload spills chunk's text to the object store, keyed by
+// sha256(text)+chunk_id so identical chunk bodies from different chunk IDs
+// don't collide, and returns a reference the Kafka message can carry instead
+// of the text itself.
+func (ac *KafkaAgenticChunker) uploadChunkPayload(chunk models.Chunk) (*models.PayloadRef, error) {
+	hash := sha256.Sum256([]byte(chunk.Content))
+	key := fmt.Sprintf("%s-%s", hex.EncodeToString(hash[:]), chunk.ID)
+
+	info, err := ac.objectStore.PutObject(context.Background(), ac.objectStoreBucket, key, strings.NewReader(chunk.Content), int64(len(chunk.Content)), minio.PutObjectOptions{
+		ContentType: "text/plain",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload chunk payload: %w", err)
+	}
+
+	return &models.PayloadRef{
+		PayloadURI: fmt.Sprintf("s3://%s/%s", ac.objectStoreBucket, key),
+		Size:       info.Size,
+		ETag:       info.ETag,
+	}, nil
+}
+
+// buildChunkOutput assembles the Kafka message body for chunk: inline text
+// by default, or a PayloadRef when shouldExternalize decides the chunk
+// should be spilled to object storage first.
+func (ac *KafkaAgenticChunker) buildChunkOutput(chunk models.Chunk, sourceInfo models.SourceInfo) (models.ChunkOutput, error) {
+	output := models.ChunkOutput{
+		Text:          chunk.Content,
+		Source:        sourceInfo,
+		ChunkMetadata: chunk.Metadata,
+	}
+
+	if !ac.shouldExternalize(len(chunk.Content)) {
+		return output, nil
+	}
+
+	ref, err := ac.uploadChunkPayload(chunk)
+	if err != nil {
+		return models.ChunkOutput{}, err
 	}
+
+	output.Text = ""
+	output.PayloadRef = ref
+	return output, nil
 }
 
-func (ac *AgenticChunker) InitializeKafkaStreaming(bootstrapServers, topic string) error {
-	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+func (ac *KafkaAgenticChunker) InitializeKafkaStreaming(bootstrapServers, topic string, security models.KafkaSecurityConfig) error {
+	cm := &kafka.ConfigMap{
 		"bootstrap.servers": bootstrapServers,
 		"acks":              "all",
 		"retries":           "3",
 		"batch.size":        "16384",
 		"linger.ms":         "1",
 		"compression.type":  "snappy",
-	})
+
+		// Idempotent production is always on: it requires acks=all and
+		// retries>0 (both already set above) plus a bounded in-flight
+		// window, and dedupes broker-side retries of the same message.
+		"enable.idempotence":                    true,
+		"max.in.flight.requests.per.connection": "5",
+	}
+	if err := applyProducerSecurity(cm, security); err != nil {
+		return fmt.Errorf("failed to apply Kafka security config: %w", err)
+	}
+
+	producer, err := kafka.NewProducer(cm)
 	if err != nil {
 		return fmt.Errorf("failed to create Kafka producer: %w", err)
 	}
 
+	if security.SASLMechanism == "OAUTHBEARER" {
+		refresher, err := startProducerOAuthRefresher(producer, security.OAuth)
+		if err != nil {
+			producer.Close()
+			return fmt.Errorf("failed initial OAuth2 token fetch: %w", err)
+		}
+		ac.oauthRefresher = refresher
+	}
+
 	ac.kafkaProducer = producer
 	ac.kafkaTopic = topic
+	ac.bootstrapServers = bootstrapServers
+	ac.kafkaSecurity = security
+
+	if ac.deadLetterTopic == "" {
+		ac.deadLetterTopic = topic + ".dlq"
+	}
+	if ac.retryTopic == "" {
+		ac.retryTopic = topic + ".retry"
+	}
+
+	if ac.schemaRegistryConfig.URL != "" {
+		registry := NewSchemaRegistryClient(ac.schemaRegistryConfig.URL)
+
+		strategy := SubjectNamingStrategy(ac.schemaRegistryConfig.SubjectStrategy)
+		subject := subjectFor(strategy, topic, "ChunkOutput")
+
+		serializer, err := NewSerializer(ac.schemaRegistryConfig.Format, registry, subject)
+		if err != nil {
+			return fmt.Errorf("failed to initialize schema registry serializer: %w", err)
+		}
+
+		if ac.schemaRegistryConfig.Compatibility != "" {
+			if err := registry.SetCompatibility(subject, ac.schemaRegistryConfig.Compatibility); err != nil {
+				return fmt.Errorf("failed to set schema compatibility for %s: %w", subject, err)
+			}
+		}
+
+		ac.serializer = serializer
+	}
+
 	return nil
 }
 
-func (ac *AgenticChunker) Close() {
+// applyProducerSecurity translates security into the kafka.ConfigMap entries
+// librdkafka expects. A zero-value Protocol leaves cm untouched, so existing
+// plaintext callers don't need to change.
+func applyProducerSecurity(cm *kafka.ConfigMap, security models.KafkaSecurityConfig) error {
+	if security.Protocol == "" || security.Protocol == "plaintext" {
+		return nil
+	}
+
+	if err := cm.SetKey("security.protocol", security.Protocol); err != nil {
+		return err
+	}
+
+	if security.SASLMechanism != "" {
+		if err := cm.SetKey("sasl.mechanisms", security.SASLMechanism); err != nil {
+			return err
+		}
+	}
+	if security.SASLUsername != "" {
+		if err := cm.SetKey("sasl.username", security.SASLUsername); err != nil {
+			return err
+		}
+	}
+	if security.SASLPassword != "" {
+		if err := cm.SetKey("sasl.password", security.SASLPassword); err != nil {
+			return err
+		}
+	}
+
+	tls := security.TLS
+	if tls.CAFile != "" {
+		if err := cm.SetKey("ssl.ca.location", tls.CAFile); err != nil {
+			return err
+		}
+	}
+	if tls.CertFile != "" {
+		if err := cm.SetKey("ssl.certificate.location", tls.CertFile); err != nil {
+			return err
+		}
+	}
+	if tls.KeyFile != "" {
+		if err := cm.SetKey("ssl.key.location", tls.KeyFile); err != nil {
+			return err
+		}
+	}
+	if tls.InsecureSkipVerify {
+		if err := cm.SetKey("enable.ssl.certificate.verification", false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newTransactionalProducer builds a dedicated Kafka producer for one
+// ChunkTextStreaming worker, with its own transactional.id so concurrent
+// workers don't collide or serialize on a single producer's transaction
+// state (only one transaction can be in flight per producer instance). The
+// id is derived from this host's hostname plus workerIndex, per the
+// per-replica-per-worker uniqueness a transactional.id requires.
+func (ac *KafkaAgenticChunker) newTransactionalProducer(workerIndex int) (*kafka.Producer, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown-host"
+	}
+
+	cm := &kafka.ConfigMap{
+		"bootstrap.servers": ac.bootstrapServers,
+		"acks":              "all",
+		"retries":           "3",
+		"batch.size":        "16384",
+		"linger.ms":         "1",
+		"compression.type":  "snappy",
+
+		"enable.idempotence":                    true,
+		"max.in.flight.requests.per.connection": "5",
+		"transactional.id":                      fmt.Sprintf("%s-worker-%d", hostname, workerIndex),
+	}
+	if err := applyProducerSecurity(cm, ac.kafkaSecurity); err != nil {
+		return nil, fmt.Errorf("failed to apply Kafka security config: %w", err)
+	}
+
+	producer, err := kafka.NewProducer(cm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transactional Kafka producer: %w", err)
+	}
+
+	initCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := producer.InitTransactions(initCtx); err != nil {
+		producer.Close()
+		return nil, fmt.Errorf("failed to init transactions: %w", err)
+	}
+
+	return producer, nil
+}
+
+func (ac *KafkaAgenticChunker) Close() {
+	ac.closeAdminServer()
+	if ac.oauthRefresher != nil {
+		ac.oauthRefresher.Close()
+	}
 	if ac.kafkaProducer != nil {
 		ac.kafkaProducer.Flush(30 * 1000)
 		ac.kafkaProducer.Close()
 	}
 }
 
-func (ac *AgenticChunker) ChunkTextStreaming(ctx context.Context, text string, sourceInfo models.SourceInfo) error {
+func (ac *KafkaAgenticChunker) ChunkTextStreaming(ctx context.Context, text string, sourceInfo models.SourceInfo) error {
 	if strings.TrimSpace(text) == "" {
 		return fmt.Errorf("input text cannot be empty")
 	}
@@ -99,9 +396,11 @@ func (ac *AgenticChunker) ChunkTextStreaming(ctx context.Context, text string, s
 	var wg sync.WaitGroup
 	for i := 0; i < maxWorkers; i++ {
 		wg.Add(1)
-		go ac.worker(ctx, jobs, results, sourceInfo, &wg)
+		go ac.worker(ctx, jobs, results, sourceInfo, i, &wg)
 	}
 
+	queueDepth.Set(float64(len(sections)))
+
 	go func() {
 		defer close(jobs)
 		for i, section := range sections {
@@ -128,6 +427,8 @@ func (ac *AgenticChunker) ChunkTextStreaming(ctx context.Context, text string, s
 		}
 	}
 
+	queueDepth.Set(0)
+
 	if len(processingErrors) > 0 {
 		return fmt.Errorf("failed to process %d/%d sections: %v", len(processingErrors), processedSections, processingErrors[0])
 	}
@@ -136,16 +437,33 @@ func (ac *AgenticChunker) ChunkTextStreaming(ctx context.Context, text string, s
 	return nil
 }
 
+func (ac *KafkaAgenticChunker) streamChunkToKafka(ctx context.Context, producer *kafka.Producer, chunk models.ChunkOutput) error {
+	_, span := tracer.Start(ctx, "chunking.streamChunkToKafka", trace.WithAttributes(
+		attribute.String("source.document_title", chunk.Source.DocumentTitle),
+		attribute.Int("section.index", chunk.ChunkMetadata.ChunkIndex),
+	))
+	defer span.End()
+
+	start := time.Now()
+	defer func() {
+		kafkaProduceDuration.Observe(time.Since(start).Seconds())
+	}()
 
-func (ac *AgenticChunker) streamChunkToKafka(chunk models.ChunkOutput) error {
-	chunkJSON, err := json.Marshal(chunk)
+	var chunkJSON []byte
+	var err error
+	if ac.serializer != nil {
+		chunkJSON, err = ac.serializer.Serialize(chunk)
+	} else {
+		chunkJSON, err = json.Marshal(chunk)
+	}
 	if err != nil {
+		kafkaDeliveryFailuresTotal.Inc()
 		return fmt.Errorf("failed to marshal chunk: %w", err)
 	}
 
 	deliveryChan := make(chan kafka.Event, 1)
 
-	err = ac.kafkaProducer.Produce(&kafka.Message{
+	err = producer.Produce(&kafka.Message{
 		TopicPartition: kafka.TopicPartition{
 			Topic:     &ac.kafkaTopic,
 			Partition: kafka.PartitionAny,
@@ -159,6 +477,7 @@ func (ac *AgenticChunker) streamChunkToKafka(chunk models.ChunkOutput) error {
 	}, deliveryChan)
 
 	if err != nil {
+		kafkaDeliveryFailuresTotal.Inc()
 		return fmt.Errorf("failed to produce message: %w", err)
 	}
 
@@ -166,17 +485,20 @@ func (ac *AgenticChunker) streamChunkToKafka(chunk models.ChunkOutput) error {
 	case e := <-deliveryChan:
 		if msg, ok := e.(*kafka.Message); ok {
 			if msg.TopicPartition.Error != nil {
+				kafkaDeliveryFailuresTotal.Inc()
 				return fmt.Errorf("delivery failed: %w", msg.TopicPartition.Error)
 			}
 		}
 	case <-time.After(5 * time.Second):
+		kafkaDeliveryFailuresTotal.Inc()
 		return fmt.Errorf("delivery confirmation timeout")
 	}
 
+	chunksProducedTotal.Inc()
 	return nil
 }
 
-func (ac *AgenticChunker) calculateOptimalSectionSize(textLength int) int {
+func (ac *KafkaAgenticChunker) calculateOptimalSectionSize(textLength int) int {
 	if textLength > 100000 {
 		return 1800
 	} else if textLength > 50000 {
@@ -199,7 +521,7 @@ func (ac *AgenticChunker) calculateOptimalSectionSize(textLength int) int {
 	return optimalSectionSize
 }
 
-func (ac *AgenticChunker) divideIntoSections(text string) []string {
+func (ac *KafkaAgenticChunker) divideIntoSections(text string) []string {
 	words := strings.Fields(text)
 	var sections []string
 
@@ -232,7 +554,7 @@ func (ac *AgenticChunker) divideIntoSections(text string) []string {
 	return sections
 }
 
-func (ac *AgenticChunker) buildChunkingPrompt(text string) string {
+func (ac *KafkaAgenticChunker) buildChunkingPrompt(text string) string {
 	return fmt.Sprintf(`You are an expert text analyst. Please analyze the following text and break it into semantically meaningful chunks. Each chunk should represent a complete thought, concept, or topic.
 
 For each chunk, provide:
@@ -275,84 +597,48 @@ Text to analyze (length: %d characters):
 %s`, len(text), text)
 }
 
-func (ac *AgenticChunker) callGeminiAPI(ctx context.Context, prompt string) (string, error) {
-	reqBody := models.GeminiRequest{
-		Contents: []models.Content{
-			{
-				Parts: []models.Part{
-					{Text: prompt},
-				},
-			},
-		},
-	}
-
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+// callLLM runs prompt against ac.llmBackend, retrying on error with a
+// simple quadratic backoff. Status-code-specific handling (rate limits,
+// 5xx) now lives inside each LLMBackend implementation, behind the
+// Complete(ctx, prompt, schema) boundary.
+func (ac *KafkaAgenticChunker) callLLM(ctx context.Context, prompt string, sectionIndex int, documentTitle string) (string, error) {
+	ctx, span := tracer.Start(ctx, "chunking.callLLM", trace.WithAttributes(
+		attribute.String("source.document_title", documentTitle),
+		attribute.Int("section.index", sectionIndex),
+	))
+	defer span.End()
+
+	if ac.llmBackend == nil {
+		return "", fmt.Errorf("no LLM backend configured")
 	}
 
-	url := fmt.Sprintf("%s?key=%s", ac.baseURL, ac.apiKey)
-
 	var lastErr error
 	for attempt := 0; attempt <= ac.maxRetries; attempt++ {
 		if attempt > 0 {
+			geminiRetriesTotal.Inc()
 			backoffDuration := time.Duration(attempt*attempt) * time.Second
-			fmt.Printf("Retrying Gemini API call (attempt %d) after %v\n", attempt+1, backoffDuration)
+			fmt.Printf("Retrying LLM call (attempt %d) after %v\n", attempt+1, backoffDuration)
 			time.Sleep(backoffDuration)
 		}
 
-		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-		if err != nil {
-			lastErr = fmt.Errorf("failed to create request: %w", err)
-			continue
-		}
-
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := ac.client.Do(req)
+		callStart := time.Now()
+		response, err := ac.llmBackend.Complete(ctx, prompt, chunkResponseSchema)
+		geminiRequestDuration.Observe(time.Since(callStart).Seconds())
 		if err != nil {
-			lastErr = fmt.Errorf("failed to make request: %w", err)
+			lastErr = err
 			continue
 		}
 
-		if resp.StatusCode == http.StatusTooManyRequests {
-			resp.Body.Close()
-			lastErr = fmt.Errorf("rate limit exceeded")
-			continue
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			lastErr = fmt.Errorf("api request failed with status %d: %s", resp.StatusCode, string(body))
-			continue
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			lastErr = fmt.Errorf("failed to read response body: %w", err)
-			continue
-		}
-
-		var geminiResp models.GeminiResponse
-		if err := json.Unmarshal(body, &geminiResp); err != nil {
-			lastErr = fmt.Errorf("failed to unmarshal response: %w", err)
-			continue
-		}
-
-		if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
-			lastErr = fmt.Errorf("no content in gemini response")
-			continue
-		}
-
-		return geminiResp.Candidates[0].Content.Parts[0].Text, nil
+		return response, nil
 	}
 
 	return "", fmt.Errorf("failed after %d retries: %w", ac.maxRetries, lastErr)
 }
 
-func (ac *AgenticChunker) parseGeminiResponse(response string, sectionIndex int) ([]models.Chunk, error) {
+// parseChunkResponse parses an LLMBackend's raw completion into chunks.
+// Every backend normalizes its own structured-output settings to
+// chunkResponseSchema's shape, so this step is provider-agnostic.
+func (ac *KafkaAgenticChunker) parseChunkResponse(response string, sectionIndex int) ([]models.Chunk, error) {
 	response = strings.TrimSpace(response)
 
 	if strings.HasPrefix(response, "```json") {
@@ -365,30 +651,30 @@ func (ac *AgenticChunker) parseGeminiResponse(response string, sectionIndex int)
 
 	response = strings.TrimSpace(response)
 
-	var geminiResp models.GeminiChunkerResponse
-	if err := json.Unmarshal([]byte(response), &geminiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse gemini json response: %w", err)
+	var llmResp models.GeminiChunkerResponse
+	if err := json.Unmarshal([]byte(response), &llmResp); err != nil {
+		return nil, fmt.Errorf("failed to parse llm json response: %w", err)
 	}
 
-	chunks := make([]models.Chunk, 0, len(geminiResp.Chunks))
+	chunks := make([]models.Chunk, 0, len(llmResp.Chunks))
 	timestamp := time.Now().UTC().Format(time.RFC3339)
 
-	for i, geminiChunk := range geminiResp.Chunks {
+	for i, llmChunk := range llmResp.Chunks {
 		chunk := models.Chunk{
 			ID:      fmt.Sprintf("chunk_%d_%d_%d", time.Now().Unix(), sectionIndex, i),
-			Content: geminiChunk.Content,
+			Content: llmChunk.Content,
 			Metadata: models.ChunkMetadata{
-				Topic:       geminiChunk.Topic,
-				Keywords:    geminiChunk.Keywords,
-				Entities:    geminiChunk.Entities,
-				Summary:     geminiChunk.Summary,
-				Category:    geminiChunk.Category,
-				Sentiment:   geminiChunk.Sentiment,
-				Complexity:  geminiChunk.Complexity,
-				Language:    geminiChunk.Language,
-				WordCount:   len(strings.Fields(geminiChunk.Content)),
-				ChunkIndex:  i,
-				Timestamp:   timestamp,
+				Topic:      llmChunk.Topic,
+				Keywords:   llmChunk.Keywords,
+				Entities:   llmChunk.Entities,
+				Summary:    llmChunk.Summary,
+				Category:   llmChunk.Category,
+				Sentiment:  llmChunk.Sentiment,
+				Complexity: llmChunk.Complexity,
+				Language:   llmChunk.Language,
+				WordCount:  len(strings.Fields(llmChunk.Content)),
+				ChunkIndex: i,
+				Timestamp:  timestamp,
 			},
 		}
 		chunks = append(chunks, chunk)
@@ -397,23 +683,23 @@ func (ac *AgenticChunker) parseGeminiResponse(response string, sectionIndex int)
 	return chunks, nil
 }
 
-func (ac *AgenticChunker) processSubSection(ctx context.Context, sectionText string, sectionIndex, subIndex int) ([]models.Chunk, error) {
+func (ac *KafkaAgenticChunker) processSubSection(ctx context.Context, sectionText string, sectionIndex, subIndex int, documentTitle string) ([]models.Chunk, error) {
 	prompt := ac.buildChunkingPrompt(sectionText)
 
-	geminiResponse, err := ac.callGeminiAPI(ctx, prompt)
+	llmResponse, err := ac.callLLM(ctx, prompt, sectionIndex, documentTitle)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call Gemini API for section %d-%d: %w", sectionIndex, subIndex, err)
+		return nil, fmt.Errorf("failed to call LLM backend for section %d-%d: %w", sectionIndex, subIndex, err)
 	}
 
-	chunks, err := ac.parseGeminiResponse(geminiResponse, sectionIndex*1000+subIndex)
+	chunks, err := ac.parseChunkResponse(llmResponse, sectionIndex*1000+subIndex)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse Gemini response for section %d-%d: %w", sectionIndex, subIndex, err)
+		return nil, fmt.Errorf("failed to parse LLM response for section %d-%d: %w", sectionIndex, subIndex, err)
 	}
 
 	return chunks, nil
 }
 
-func (ac *AgenticChunker) splitLargeSection(text string) []string {
+func (ac *KafkaAgenticChunker) splitLargeSection(text string) []string {
 	words := strings.Fields(text)
 	var sections []string
 	maxWordsPerSubSection := ac.maxCharsPerSection / 6
@@ -431,13 +717,19 @@ func (ac *AgenticChunker) splitLargeSection(text string) []string {
 	return sections
 }
 
-func (ac *AgenticChunker) processSectionConcurrently(ctx context.Context, sectionText string, sectionIndex int) ([]models.Chunk, error) {
+func (ac *KafkaAgenticChunker) processSectionConcurrently(ctx context.Context, sectionText string, sectionIndex int, documentTitle string) ([]models.Chunk, error) {
+	ctx, span := tracer.Start(ctx, "chunking.processSectionConcurrently", trace.WithAttributes(
+		attribute.String("source.document_title", documentTitle),
+		attribute.Int("section.index", sectionIndex),
+	))
+	defer span.End()
+
 	if len(sectionText) > ac.maxCharsPerSection {
 		subSections := ac.splitLargeSection(sectionText)
 		var allChunks []models.Chunk
 
 		for i, subSection := range subSections {
-			chunks, err := ac.processSubSection(ctx, subSection, sectionIndex, i)
+			chunks, err := ac.processSubSection(ctx, subSection, sectionIndex, i, documentTitle)
 			if err != nil {
 				return nil, fmt.Errorf("failed to process subsection %d: %w", i, err)
 			}
@@ -446,43 +738,181 @@ func (ac *AgenticChunker) processSectionConcurrently(ctx context.Context, sectio
 		return allChunks, nil
 	}
 
-	return ac.processSubSection(ctx, sectionText, sectionIndex, 0)
+	return ac.processSubSection(ctx, sectionText, sectionIndex, 0, documentTitle)
 }
 
-func (ac *AgenticChunker) worker(ctx context.Context, jobs <-chan sectionJob, results chan<- sectionResult, sourceInfo models.SourceInfo, wg *sync.WaitGroup) {
+// sectionProducer processes and streams one section's chunks, either
+// non-transactionally against the shared kafkaProducer (processAndStreamSection)
+// or inside a single Kafka transaction on a worker-owned producer
+// (produceSectionTransactionally).
+type sectionProducer func(ctx context.Context, job sectionJob) error
+
+func (ac *KafkaAgenticChunker) worker(ctx context.Context, jobs <-chan sectionJob, results chan<- sectionResult, sourceInfo models.SourceInfo, workerIndex int, wg *sync.WaitGroup) {
 	defer wg.Done()
 
+	produce := sectionProducer(ac.processAndStreamSection)
+
+	if ac.transactional {
+		txProducer, err := ac.newTransactionalProducer(workerIndex)
+		if err != nil {
+			fmt.Printf("worker %d: failed to init transactional producer, falling back to non-transactional streaming: %v\n", workerIndex, err)
+		} else {
+			defer txProducer.Close()
+			produce = func(ctx context.Context, job sectionJob) error {
+				return ac.produceSectionTransactionally(ctx, job, txProducer)
+			}
+		}
+	}
+
 	for job := range jobs {
-		chunks, err := ac.processSectionConcurrently(ctx, job.text, job.index)
+		queueDepth.Dec()
+		activeWorkers.Inc()
+		err := ac.processSectionWithRetry(ctx, job, produce)
+		activeWorkers.Dec()
+		results <- sectionResult{err: err, index: job.index}
+	}
+}
+
+// processAndStreamSection chunks job's section text and streams each
+// resulting chunk to Kafka via the shared kafkaProducer. It's the default
+// unit processSectionWithRetry retries as a whole, so a section that chunked
+// fine but failed partway through streaming is retried from scratch rather
+// than left half-delivered.
+func (ac *KafkaAgenticChunker) processAndStreamSection(ctx context.Context, job sectionJob) error {
+	chunks, err := ac.processSectionConcurrently(ctx, job.text, job.index, job.sourceInfo.DocumentTitle)
+	if err != nil {
+		return err
+	}
+
+	for _, chunk := range chunks {
+		chunkOutput, err := ac.buildChunkOutput(chunk, job.sourceInfo)
+		if err != nil {
+			return fmt.Errorf("failed to build chunk output: %w", err)
+		}
+
+		if err := ac.streamChunkToKafka(ctx, ac.kafkaProducer, chunkOutput); err != nil {
+			return fmt.Errorf("failed to stream chunk to kafka: %w", err)
+		}
+
+		fmt.Printf("Streamed chunk %s to Kafka\n", chunkOutput.ChunkMetadata.Topic)
+	}
+
+	return nil
+}
+
+// produceSectionTransactionally is processAndStreamSection's exactly-once
+// counterpart: every chunk from job's section is produced on producer inside
+// a single Kafka transaction, so either all of them land or none do. producer
+// must already have had InitTransactions called on it (see
+// newTransactionalProducer); only one of these calls may run against a given
+// producer at a time.
+func (ac *KafkaAgenticChunker) produceSectionTransactionally(ctx context.Context, job sectionJob, producer *kafka.Producer) error {
+	chunks, err := ac.processSectionConcurrently(ctx, job.text, job.index, job.sourceInfo.DocumentTitle)
+	if err != nil {
+		return err
+	}
+
+	if err := producer.BeginTransaction(); err != nil {
+		return fmt.Errorf("failed to begin transaction for section %d: %w", job.index, err)
+	}
 
+	for _, chunk := range chunks {
+		chunkOutput, err := ac.buildChunkOutput(chunk, job.sourceInfo)
 		if err != nil {
-			results <- sectionResult{chunks: nil, err: err, index: job.index}
+			abortTransaction(producer, job.index)
+			return fmt.Errorf("failed to build chunk output: %w", err)
+		}
+
+		if err := ac.streamChunkToKafka(ctx, producer, chunkOutput); err != nil {
+			abortTransaction(producer, job.index)
+			return fmt.Errorf("failed to stream chunk to kafka: %w", err)
+		}
+
+		fmt.Printf("Streamed chunk %s to Kafka (transactional)\n", chunkOutput.ChunkMetadata.Topic)
+	}
+
+	commitCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if err := producer.CommitTransaction(commitCtx); err != nil {
+		abortTransaction(producer, job.index)
+		return fmt.Errorf("failed to commit transaction for section %d: %w", job.index, err)
+	}
+
+	return nil
+}
+
+// abortTransaction best-effort aborts producer's open transaction, logging
+// rather than propagating a failure: the caller is already returning the
+// original error that triggered the abort.
+func abortTransaction(producer *kafka.Producer, sectionIndex int) {
+	abortCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := producer.AbortTransaction(abortCtx); err != nil {
+		fmt.Printf("failed to abort transaction for section %d: %v\n", sectionIndex, err)
+	}
+}
+
+// processSectionWithRetry runs produce for job (processAndStreamSection, or
+// produceSectionTransactionally when the worker has a transactional
+// producer), making up to maxSectionRetries attempts in total with
+// sectionBackoff between them. This is
+// separate from callLLM's own retry loop, which only covers a single LLM
+// call rather than the whole chunk-and-stream pipeline for a section. Once
+// retries are exhausted, a transient last error is handed off to
+// ac.retryTopic for a separate consumer to replay later (see
+// cmd/retry-replay); a permanent one, or one that fails to enqueue onto the
+// retry topic, goes straight to ac.deadLetterTopic.
+func (ac *KafkaAgenticChunker) processSectionWithRetry(ctx context.Context, job sectionJob, produce sectionProducer) error {
+	backoff := newSectionBackoff()
+
+	var lastErr error
+	for attempt := 1; attempt <= ac.maxSectionRetries; attempt++ {
+		if attempt > 1 {
+			time.Sleep(backoff.delay(attempt - 1))
+		}
+
+		if err := produce(ctx, job); err != nil {
+			lastErr = err
+			fmt.Printf("section %d attempt %d/%d failed: %v\n", job.index, attempt, ac.maxSectionRetries, err)
 			continue
 		}
 
-		for _, chunk := range chunks {
-			chunkOutput := models.ChunkOutput{
-				Text:          chunk.Content,
-				Source:        sourceInfo,
-				ChunkMetadata: chunk.Metadata,
-			}
+		ac.processedCount.Add(1)
+		sectionsTotal.WithLabelValues("success").Inc()
+		return nil
+	}
 
-			if streamErr := ac.streamChunkToKafka(chunkOutput); streamErr != nil {
-				fmt.Printf("Failed to stream chunk to Kafka: %v\n", streamErr)
-				results <- sectionResult{chunks: nil, err: streamErr, index: job.index}
-				continue
-			} else {
-				fmt.Printf("Streamed chunk %s to Kafka\n", chunkOutput.ChunkMetadata.Topic)
-			}
+	if isTransientError(lastErr) {
+		if err := ac.publishRetry(job, lastErr, ac.maxSectionRetries, 30*time.Second); err == nil {
+			sectionsTotal.WithLabelValues("retried").Inc()
+			return lastErr
 		}
+		fmt.Printf("failed to enqueue section %d onto retry topic, dead-lettering instead\n", job.index)
+	}
 
-		results <- sectionResult{chunks: chunks, err: nil, index: job.index}
+	if err := ac.publishDeadLetter(job, lastErr, ac.maxSectionRetries); err != nil {
+		fmt.Printf("failed to publish section %d to dead letter topic: %v\n", job.index, err)
 	}
+
+	sectionsTotal.WithLabelValues("dead_lettered").Inc()
+	return lastErr
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+// ReplaySection reprocesses a section recorded in a RetryRecord: the same
+// chunk-and-stream pipeline processSectionWithRetry runs, but as a single
+// attempt with no further retry-topic hop. Exported for cmd/retry-replay,
+// which is responsible for dead-lettering a section that fails again here.
+func (ac *KafkaAgenticChunker) ReplaySection(ctx context.Context, record RetryRecord) error {
+	job := sectionJob{
+		index:      record.SectionIndex,
+		text:       record.SectionText,
+		sourceInfo: record.SourceInfo,
 	}
-	return b
-}
\ No newline at end of file
+
+	if err := ac.processAndStreamSection(ctx, job); err != nil {
+		return err
+	}
+
+	ac.processedCount.Add(1)
+	return nil
+}
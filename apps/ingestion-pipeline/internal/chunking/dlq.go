@@ -0,0 +1,168 @@
+package chunking
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// FailureMeta records why a section was retried or dead-lettered, so an
+// operator replaying the DLQ can tell a transient LLM-provider blip apart
+// from a section that will never succeed.
+type FailureMeta struct {
+	Error     string    `json:"error"`
+	Attempts  int       `json:"attempts"`
+	FirstSeen time.Time `json:"first_seen"`
+}
+
+// DLQRecord is the envelope published to deadLetterTopic once a section has
+// exhausted maxSectionRetries: the original section text and SourceInfo it
+// failed against, so it can be re-chunked by hand without re-running the
+// whole document through Tika.
+type DLQRecord struct {
+	SectionIndex int               `json:"section_index"`
+	SectionText  string            `json:"section_text"`
+	SourceInfo   models.SourceInfo `json:"source_info"`
+	Failure      FailureMeta       `json:"failure"`
+}
+
+// RetryRecord is the envelope published to retryTopic when a section fails
+// with a transient error: a separate, continuously-running consumer (see
+// cmd/retry-replay) waits until ReplayAfter and then calls ReplaySection.
+type RetryRecord struct {
+	SectionIndex int               `json:"section_index"`
+	SectionText  string            `json:"section_text"`
+	SourceInfo   models.SourceInfo `json:"source_info"`
+	Failure      FailureMeta       `json:"failure"`
+	ReplayAfter  time.Time         `json:"replay_after"`
+}
+
+// isTransientError reports whether err looks like a retryable hiccup
+// (HTTP 429/5xx from an LLMBackend, or a request/delivery timeout) rather
+// than a permanent failure (bad input, auth, parsing). LLMBackend errors
+// carry their status code only in the error string, so classification is
+// necessarily string-based here.
+func isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "status 429"),
+		strings.Contains(msg, "status 500"),
+		strings.Contains(msg, "status 502"),
+		strings.Contains(msg, "status 503"),
+		strings.Contains(msg, "status 504"),
+		strings.Contains(msg, "timeout"),
+		strings.Contains(msg, "deadline exceeded"),
+		strings.Contains(msg, "connection refused"),
+		strings.Contains(msg, "connection reset"):
+		return true
+	default:
+		return false
+	}
+}
+
+// publishDeadLetter publishes a section's final failure to ac.deadLetterTopic
+// and increments the dead_lettered counter.
+func (ac *KafkaAgenticChunker) publishDeadLetter(job sectionJob, failErr error, attempts int) error {
+	record := DLQRecord{
+		SectionIndex: job.index,
+		SectionText:  job.text,
+		SourceInfo:   job.sourceInfo,
+		Failure: FailureMeta{
+			Error:     failErr.Error(),
+			Attempts:  attempts,
+			FirstSeen: time.Now().UTC(),
+		},
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal DLQ record: %w", err)
+	}
+
+	topic := ac.deadLetterTopic
+	if err := ac.kafkaProducer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Value:          data,
+	}, nil); err != nil {
+		return fmt.Errorf("failed to produce to dead letter topic %s: %w", topic, err)
+	}
+
+	ac.deadLetteredCount.Add(1)
+	return nil
+}
+
+// publishRetry enqueues a transiently-failed section onto ac.retryTopic,
+// delayed by delay, and increments the retried counter. replayAfter is
+// carried both as a JSON field and a header so a replayer can filter
+// without unmarshalling the whole value first.
+func (ac *KafkaAgenticChunker) publishRetry(job sectionJob, failErr error, attempts int, delay time.Duration) error {
+	replayAfter := time.Now().UTC().Add(delay)
+
+	record := RetryRecord{
+		SectionIndex: job.index,
+		SectionText:  job.text,
+		SourceInfo:   job.sourceInfo,
+		Failure: FailureMeta{
+			Error:     failErr.Error(),
+			Attempts:  attempts,
+			FirstSeen: time.Now().UTC(),
+		},
+		ReplayAfter: replayAfter,
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal retry record: %w", err)
+	}
+
+	topic := ac.retryTopic
+	if err := ac.kafkaProducer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Value:          data,
+		Headers: []kafka.Header{
+			{Key: "replay_after", Value: []byte(replayAfter.Format(time.RFC3339))},
+		},
+	}, nil); err != nil {
+		return fmt.Errorf("failed to produce to retry topic %s: %w", topic, err)
+	}
+
+	ac.retriedCount.Add(1)
+	return nil
+}
+
+// DeadLetter publishes record to ac.deadLetterTopic with failErr as the
+// final failure, counting record's prior attempts plus this one. Exported
+// for cmd/retry-replay to call when a replayed section fails again.
+func (ac *KafkaAgenticChunker) DeadLetter(record RetryRecord, failErr error) error {
+	job := sectionJob{
+		index:      record.SectionIndex,
+		text:       record.SectionText,
+		sourceInfo: record.SourceInfo,
+	}
+	return ac.publishDeadLetter(job, failErr, record.Failure.Attempts+1)
+}
+
+// ChunkerStatus reports KafkaAgenticChunker's section-processing counters, for a
+// status endpoint to expose.
+type ChunkerStatus struct {
+	Processed    int64 `json:"processed"`
+	Retried      int64 `json:"retried"`
+	DeadLettered int64 `json:"dead_lettered"`
+}
+
+// Status returns a snapshot of KafkaAgenticChunker's section-processing counters.
+func (ac *KafkaAgenticChunker) Status() ChunkerStatus {
+	return ChunkerStatus{
+		Processed:    ac.processedCount.Load(),
+		Retried:      ac.retriedCount.Load(),
+		DeadLettered: ac.deadLetteredCount.Load(),
+	}
+}
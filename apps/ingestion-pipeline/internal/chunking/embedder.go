@@ -0,0 +1,280 @@
+package chunking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Embedder produces dense vector embeddings for chunk text. Implementations
+// are expected to be safe for concurrent use so callers can batch requests
+// under the same rate limiter/semaphore used for AI analysis.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+	Model() string
+	Dimensions() int
+}
+
+// NewEmbedder builds an Embedder from config. Provider selection mirrors the
+// one used for AI analysis: "gemini" (default), "openai", or "local" for a
+// self-hosted sentence-transformers HTTP endpoint.
+func NewEmbedder(cfg *Config) (Embedder, error) {
+	switch cfg.EmbeddingProvider {
+	case "", "gemini":
+		return NewGeminiEmbedder(cfg.GeminiAPIKey, cfg.EmbeddingModel, cfg.RequestTimeout), nil
+	case "openai":
+		return NewOpenAIEmbedder(cfg.EmbeddingAPIKey, cfg.EmbeddingModel, cfg.RequestTimeout), nil
+	case "local":
+		return NewLocalEmbedder(cfg.EmbeddingEndpoint, cfg.EmbeddingModel, cfg.RequestTimeout), nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider: %s", cfg.EmbeddingProvider)
+	}
+}
+
+const (
+	geminiEmbeddingDimensions = 768
+	openAIEmbeddingDimensions = 1536
+	localEmbeddingDimensions  = 768
+)
+
+// GeminiEmbedder calls Google's text-embedding-004 batch endpoint.
+type GeminiEmbedder struct {
+	apiKey     string
+	model      string
+	httpClient *http.Client
+	baseURL    string
+}
+
+func NewGeminiEmbedder(apiKey, model string, timeout time.Duration) *GeminiEmbedder {
+	if model == "" {
+		model = "text-embedding-004"
+	}
+	return &GeminiEmbedder{
+		apiKey:     apiKey,
+		model:      model,
+		httpClient: &http.Client{Timeout: timeout},
+		baseURL:    "https://generativelanguage.googleapis.com/v1beta/models",
+	}
+}
+
+func (ge *GeminiEmbedder) Model() string   { return ge.model }
+func (ge *GeminiEmbedder) Dimensions() int { return geminiEmbeddingDimensions }
+
+type geminiBatchEmbedRequest struct {
+	Requests []geminiEmbedContentRequest `json:"requests"`
+}
+
+type geminiEmbedContentRequest struct {
+	Model   string  `json:"model"`
+	Content Content `json:"content"`
+}
+
+type geminiBatchEmbedResponse struct {
+	Embeddings []geminiEmbedding `json:"embeddings"`
+}
+
+type geminiEmbedding struct {
+	Values []float64 `json:"values"`
+}
+
+func (ge *GeminiEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	modelPath := fmt.Sprintf("models/%s", ge.model)
+	reqBody := geminiBatchEmbedRequest{
+		Requests: make([]geminiEmbedContentRequest, len(texts)),
+	}
+	for i, text := range texts {
+		reqBody.Requests[i] = geminiEmbedContentRequest{
+			Model:   modelPath,
+			Content: Content{Parts: []Part{{Text: text}}},
+		}
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embed request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s:batchEmbedContents?key=%s", ge.baseURL, ge.model, ge.apiKey)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ge.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("embed API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var embedResp geminiBatchEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return nil, fmt.Errorf("failed to decode embed response: %w", err)
+	}
+
+	if len(embedResp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embedResp.Embeddings))
+	}
+
+	vectors := make([][]float64, len(embedResp.Embeddings))
+	for i, e := range embedResp.Embeddings {
+		vectors[i] = e.Values
+	}
+	return vectors, nil
+}
+
+// OpenAIEmbedder calls OpenAI's /v1/embeddings endpoint.
+type OpenAIEmbedder struct {
+	apiKey     string
+	model      string
+	httpClient *http.Client
+	baseURL    string
+}
+
+func NewOpenAIEmbedder(apiKey, model string, timeout time.Duration) *OpenAIEmbedder {
+	if model == "" {
+		model = "text-embedding-3-small"
+	}
+	return &OpenAIEmbedder{
+		apiKey:     apiKey,
+		model:      model,
+		httpClient: &http.Client{Timeout: timeout},
+		baseURL:    "https://api.openai.com/v1/embeddings",
+	}
+}
+
+func (oe *OpenAIEmbedder) Model() string   { return oe.model }
+func (oe *OpenAIEmbedder) Dimensions() int { return openAIEmbeddingDimensions }
+
+type openAIEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type openAIEmbedResponse struct {
+	Data []struct {
+		Embedding []float64 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+}
+
+func (oe *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	jsonData, err := json.Marshal(openAIEmbedRequest{Model: oe.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embed request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", oe.baseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", oe.apiKey))
+
+	resp, err := oe.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("embed API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var embedResp openAIEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return nil, fmt.Errorf("failed to decode embed response: %w", err)
+	}
+
+	vectors := make([][]float64, len(texts))
+	for _, d := range embedResp.Data {
+		if d.Index < 0 || d.Index >= len(vectors) {
+			continue
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, nil
+}
+
+// LocalEmbedder calls a self-hosted sentence-transformers HTTP sidecar that
+// accepts {"texts": [...]} and returns {"embeddings": [[...], ...]}.
+type LocalEmbedder struct {
+	endpoint   string
+	model      string
+	httpClient *http.Client
+}
+
+func NewLocalEmbedder(endpoint, model string, timeout time.Duration) *LocalEmbedder {
+	return &LocalEmbedder{
+		endpoint:   endpoint,
+		model:      model,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (le *LocalEmbedder) Model() string   { return le.model }
+func (le *LocalEmbedder) Dimensions() int { return localEmbeddingDimensions }
+
+type localEmbedRequest struct {
+	Texts []string `json:"texts"`
+}
+
+type localEmbedResponse struct {
+	Embeddings [][]float64 `json:"embeddings"`
+}
+
+func (le *LocalEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	jsonData, err := json.Marshal(localEmbedRequest{Texts: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embed request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", le.endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := le.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("embed API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var embedResp localEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return nil, fmt.Errorf("failed to decode embed response: %w", err)
+	}
+
+	if len(embedResp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embedResp.Embeddings))
+	}
+	return embedResp.Embeddings, nil
+}
@@ -31,10 +31,11 @@ type Part struct {
 }
 
 type GenerationConfig struct {
-	Temperature     float64 `json:"temperature"`
-	TopK           int     `json:"topK"`
-	TopP           float64 `json:"topP"`
-	MaxOutputTokens int     `json:"maxOutputTokens"`
+	Temperature      float64 `json:"temperature"`
+	TopK             int     `json:"topK"`
+	TopP             float64 `json:"topP"`
+	MaxOutputTokens  int     `json:"maxOutputTokens"`
+	ResponseMimeType string  `json:"responseMimeType,omitempty"`
 }
 
 type GeminiResponse struct {
@@ -56,6 +57,8 @@ func NewGeminiClient(apiKey, model string, timeout time.Duration) *GeminiClient
 	}
 }
 
+func (gc *GeminiClient) Model() string { return gc.model }
+
 func (gc *GeminiClient) AnalyzeChunk(ctx context.Context, systemPrompt, userPrompt string) (*ChunkAnalysis, error) {
 	url := fmt.Sprintf("%s/%s:generateContent?key=%s", gc.baseURL, gc.model, gc.apiKey)
 	
@@ -68,10 +71,11 @@ func (gc *GeminiClient) AnalyzeChunk(ctx context.Context, systemPrompt, userProm
 			},
 		},
 		GenerationConfig: GenerationConfig{
-			Temperature:     0.1,
-			TopK:           40,
-			TopP:           0.8,
-			MaxOutputTokens: 1024,
+			Temperature:      0.1,
+			TopK:             40,
+			TopP:             0.8,
+			MaxOutputTokens:  1024,
+			ResponseMimeType: "application/json",
 		},
 	}
 
@@ -0,0 +1,185 @@
+package chunking
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+var wordTokenPattern = regexp.MustCompile(`\S+`)
+
+// tokenSpan is a token's [start, end) character offset into the document
+// text it was tokenized from.
+type tokenSpan struct {
+	start int
+	end   int
+}
+
+func tokenizeWithOffsets(text string) ([]string, []tokenSpan) {
+	matches := wordTokenPattern.FindAllStringIndex(text, -1)
+	tokens := make([]string, len(matches))
+	spans := make([]tokenSpan, len(matches))
+	for i, m := range matches {
+		tokens[i] = text[m[0]:m[1]]
+		spans[i] = tokenSpan{start: m[0], end: m[1]}
+	}
+	return tokens, spans
+}
+
+// lateChunkEmbed implements the "late chunking" embedding mode: the whole
+// document is tokenized once on whitespace, the tokens are sent to the
+// embedder in overlapping sliding windows of LateChunkingWindowTokens
+// tokens, and each chunk's vector is the mean of its token span's vectors
+// (pulled from whichever window's center is closest), L2-normalized. Each
+// token in a window is passed to Embedder.Embed as a separate text, so a
+// token's vector comes from that token alone: it does not depend on the
+// neighbouring tokens or on which window it came from, and a chunk's vector
+// carries no context from outside the chunk's own words.
+//
+// Chunks whose text can't be located verbatim in the document (e.g. a
+// "semantic" mode chunk whose sentences were re-joined with single spaces)
+// get a nil vector so the caller can fall back to per-chunk embedding for
+// just those.
+func (ac *AgenticChunker) lateChunkEmbed(ctx context.Context, documentText string, chunks []ChunkPayload) ([][]float64, error) {
+	tokens, spans := tokenizeWithOffsets(documentText)
+	if len(tokens) == 0 {
+		return make([][]float64, len(chunks)), nil
+	}
+
+	windowSize := ac.config.LateChunkingWindowTokens
+	if windowSize <= 0 {
+		windowSize = 2000
+	}
+	overlap := ac.config.MaxChunkSize
+	if overlap <= 0 || overlap >= windowSize {
+		overlap = windowSize / 4
+	}
+	stride := windowSize - overlap
+	if stride <= 0 {
+		stride = windowSize
+	}
+
+	tokenVectors := make([][]float64, len(tokens))
+	tokenWindowCenter := make([]int, len(tokens))
+	for i := range tokenWindowCenter {
+		tokenWindowCenter[i] = -1
+	}
+
+	for start := 0; start < len(tokens); start += stride {
+		end := start + windowSize
+		if end > len(tokens) {
+			end = len(tokens)
+		}
+		center := (start + end) / 2
+
+		vectors, err := ac.embedder.Embed(ctx, tokens[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed token window [%d,%d): %w", start, end, err)
+		}
+
+		for i, v := range vectors {
+			globalIdx := start + i
+			if tokenWindowCenter[globalIdx] == -1 || abs(globalIdx-center) < abs(globalIdx-tokenWindowCenter[globalIdx]) {
+				tokenVectors[globalIdx] = v
+				tokenWindowCenter[globalIdx] = center
+			}
+		}
+
+		if end == len(tokens) {
+			break
+		}
+	}
+
+	result := make([][]float64, len(chunks))
+	for i, chunk := range chunks {
+		startChar := strings.Index(documentText, chunk.Text)
+		if startChar < 0 {
+			continue
+		}
+		endChar := startChar + len(chunk.Text)
+
+		startTok, endTok := tokensInSpan(spans, startChar, endChar)
+		if startTok >= endTok {
+			continue
+		}
+
+		pooled := meanPool(tokenVectors[startTok:endTok])
+		if pooled == nil {
+			continue
+		}
+		result[i] = l2Normalize(pooled)
+	}
+
+	return result, nil
+}
+
+func tokensInSpan(spans []tokenSpan, startChar, endChar int) (int, int) {
+	startTok, endTok := -1, -1
+	for i, span := range spans {
+		if span.end > startChar && startTok == -1 {
+			startTok = i
+		}
+		if span.start < endChar {
+			endTok = i + 1
+		}
+	}
+	if startTok == -1 {
+		return 0, 0
+	}
+	return startTok, endTok
+}
+
+func meanPool(vectors [][]float64) []float64 {
+	var dim int
+	count := 0
+	for _, v := range vectors {
+		if v == nil {
+			continue
+		}
+		dim = len(v)
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+
+	sum := make([]float64, dim)
+	for _, v := range vectors {
+		if v == nil {
+			continue
+		}
+		for i, x := range v {
+			sum[i] += x
+		}
+	}
+	for i := range sum {
+		sum[i] /= float64(count)
+	}
+	return sum
+}
+
+func l2Normalize(v []float64) []float64 {
+	var sumSquares float64
+	for _, x := range v {
+		sumSquares += x * x
+	}
+	norm := math.Sqrt(sumSquares)
+	if norm == 0 {
+		return v
+	}
+
+	normalized := make([]float64, len(v))
+	for i, x := range v {
+		normalized[i] = x / norm
+	}
+	return normalized
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
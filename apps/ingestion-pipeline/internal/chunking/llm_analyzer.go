@@ -0,0 +1,391 @@
+package chunking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LLMAnalyzer produces a ChunkAnalysis from a system/user prompt pair. Each
+// implementation is responsible for normalizing its provider's JSON-mode /
+// structured-output settings so the response body always deserializes into
+// ChunkAnalysis, regardless of which provider is configured.
+type LLMAnalyzer interface {
+	AnalyzeChunk(ctx context.Context, systemPrompt, userPrompt string) (*ChunkAnalysis, error)
+	Model() string
+}
+
+// ErrLLMMisconfigured is wrapped by NewLLMAnalyzer when Config names an
+// unknown LLMProvider or omits a setting that provider requires, so callers
+// can fail fast at construction instead of panicking on the first request.
+var ErrLLMMisconfigured = errors.New("llm analyzer misconfigured")
+
+// NewLLMAnalyzer builds the LLMAnalyzer named by cfg.LLMProvider. "gemini"
+// (the default, for backward compatibility with GeminiAPIKey/GeminiModel)
+// calls Google's Generative Language API; "openai" calls the chat
+// completions API; "anthropic" calls the Messages API with tool-use forced
+// to get structured output; "ollama" calls a local/self-hosted Ollama
+// server's /api/generate.
+func NewLLMAnalyzer(cfg *Config) (LLMAnalyzer, error) {
+	switch cfg.LLMProvider {
+	case "", "gemini":
+		apiKey := cfg.LLMAPIKey
+		if apiKey == "" {
+			apiKey = cfg.GeminiAPIKey
+		}
+		if apiKey == "" {
+			return nil, fmt.Errorf("%w: gemini requires an API key", ErrLLMMisconfigured)
+		}
+		model := cfg.LLMModel
+		if model == "" {
+			model = cfg.GeminiModel
+		}
+		return NewGeminiClient(apiKey, model, cfg.RequestTimeout), nil
+
+	case "openai":
+		if cfg.LLMAPIKey == "" {
+			return nil, fmt.Errorf("%w: openai requires an API key", ErrLLMMisconfigured)
+		}
+		return NewOpenAIAnalyzer(cfg.LLMAPIKey, cfg.LLMModel, cfg.LLMBaseURL, cfg.RequestTimeout), nil
+
+	case "anthropic":
+		if cfg.LLMAPIKey == "" {
+			return nil, fmt.Errorf("%w: anthropic requires an API key", ErrLLMMisconfigured)
+		}
+		return NewAnthropicAnalyzer(cfg.LLMAPIKey, cfg.LLMModel, cfg.LLMBaseURL, cfg.RequestTimeout), nil
+
+	case "ollama":
+		if cfg.LLMBaseURL == "" {
+			return nil, fmt.Errorf("%w: ollama requires a base URL", ErrLLMMisconfigured)
+		}
+		return NewOllamaAnalyzer(cfg.LLMBaseURL, cfg.LLMModel, cfg.RequestTimeout), nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown provider %q", ErrLLMMisconfigured, cfg.LLMProvider)
+	}
+}
+
+// extractJSONAnalysis unmarshals raw (the provider's raw text output) as a
+// ChunkAnalysis, stripping a ```json ... ``` fence first if the provider
+// ignored its JSON-mode instruction and wrapped the output anyway.
+func extractJSONAnalysis(raw string) (*ChunkAnalysis, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "```") {
+		raw = strings.TrimPrefix(raw, "```json")
+		raw = strings.TrimPrefix(raw, "```")
+		raw = strings.TrimSuffix(raw, "```")
+		raw = strings.TrimSpace(raw)
+	}
+
+	var analysis ChunkAnalysis
+	if err := json.Unmarshal([]byte(raw), &analysis); err != nil {
+		return nil, fmt.Errorf("failed to parse analysis JSON: %w", err)
+	}
+
+	analysis.ProcessingTimestamp = time.Now()
+	return &analysis, nil
+}
+
+// OpenAIAnalyzer calls OpenAI's /v1/chat/completions with
+// response_format={"type":"json_object"} so the model is constrained to
+// return valid JSON.
+type OpenAIAnalyzer struct {
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewOpenAIAnalyzer(apiKey, model, baseURL string, timeout time.Duration) *OpenAIAnalyzer {
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1/chat/completions"
+	}
+	return &OpenAIAnalyzer{
+		apiKey:     apiKey,
+		model:      model,
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (oa *OpenAIAnalyzer) Model() string { return oa.model }
+
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatRequest struct {
+	Model          string              `json:"model"`
+	Messages       []openAIChatMessage `json:"messages"`
+	Temperature    float64             `json:"temperature"`
+	ResponseFormat struct {
+		Type string `json:"type"`
+	} `json:"response_format"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIChatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (oa *OpenAIAnalyzer) AnalyzeChunk(ctx context.Context, systemPrompt, userPrompt string) (*ChunkAnalysis, error) {
+	reqBody := openAIChatRequest{
+		Model: oa.model,
+		Messages: []openAIChatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		},
+		Temperature: 0.1,
+	}
+	reqBody.ResponseFormat.Type = "json_object"
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", oa.baseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", oa.apiKey))
+
+	resp, err := oa.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("no response content received")
+	}
+
+	return extractJSONAnalysis(chatResp.Choices[0].Message.Content)
+}
+
+// AnthropicAnalyzer calls the Messages API with a single forced tool call:
+// rather than relying on prose-wrapped JSON, the model's only valid move is
+// to invoke record_chunk_analysis with arguments matching ChunkAnalysis.
+type AnthropicAnalyzer struct {
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewAnthropicAnalyzer(apiKey, model, baseURL string, timeout time.Duration) *AnthropicAnalyzer {
+	if model == "" {
+		model = "claude-3-5-sonnet-20241022"
+	}
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com/v1/messages"
+	}
+	return &AnthropicAnalyzer{
+		apiKey:     apiKey,
+		model:      model,
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (aa *AnthropicAnalyzer) Model() string { return aa.model }
+
+const analysisToolName = "record_chunk_analysis"
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type anthropicRequest struct {
+	Model      string              `json:"model"`
+	MaxTokens  int                 `json:"max_tokens"`
+	System     string              `json:"system"`
+	Messages   []anthropicMessage  `json:"messages"`
+	Tools      []anthropicTool     `json:"tools"`
+	ToolChoice anthropicToolChoice `json:"tool_choice"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type  string                 `json:"type"`
+		Input map[string]interface{} `json:"input"`
+	} `json:"content"`
+}
+
+func (aa *AnthropicAnalyzer) AnalyzeChunk(ctx context.Context, systemPrompt, userPrompt string) (*ChunkAnalysis, error) {
+	reqBody := anthropicRequest{
+		Model:     aa.model,
+		MaxTokens: 1024,
+		System:    systemPrompt,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: userPrompt},
+		},
+		Tools: []anthropicTool{
+			{
+				Name:        analysisToolName,
+				Description: "Record structured analysis of the given document chunk.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"chunk_role":               map[string]interface{}{"type": "string"},
+						"key_entities":             map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+						"topics":                   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+						"original_context_summary": map[string]interface{}{"type": "string"},
+						"level_of_detail":          map[string]interface{}{"type": "string"},
+						"agent_confidence":         map[string]interface{}{"type": "number"},
+					},
+					"required": []string{"chunk_role", "key_entities", "topics", "original_context_summary", "level_of_detail", "agent_confidence"},
+				},
+			},
+		},
+		ToolChoice: anthropicToolChoice{Type: "tool", Name: analysisToolName},
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", aa.baseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", aa.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := aa.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var msgResp anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&msgResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	for _, block := range msgResp.Content {
+		if block.Type != "tool_use" {
+			continue
+		}
+		encoded, err := json.Marshal(block.Input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encode tool input: %w", err)
+		}
+		return extractJSONAnalysis(string(encoded))
+	}
+
+	return nil, fmt.Errorf("no tool_use block in response")
+}
+
+// OllamaAnalyzer calls a local/self-hosted Ollama server's /api/generate
+// with format="json", Ollama's equivalent of JSON mode.
+type OllamaAnalyzer struct {
+	baseURL    string
+	model      string
+	httpClient *http.Client
+}
+
+func NewOllamaAnalyzer(baseURL, model string, timeout time.Duration) *OllamaAnalyzer {
+	if model == "" {
+		model = "llama3.1"
+	}
+	return &OllamaAnalyzer{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		model:      model,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (oa *OllamaAnalyzer) Model() string { return oa.model }
+
+type ollamaGenerateRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	System string `json:"system"`
+	Format string `json:"format"`
+	Stream bool   `json:"stream"`
+}
+
+type ollamaGenerateResponse struct {
+	Response string `json:"response"`
+}
+
+func (oa *OllamaAnalyzer) AnalyzeChunk(ctx context.Context, systemPrompt, userPrompt string) (*ChunkAnalysis, error) {
+	reqBody := ollamaGenerateRequest{
+		Model:  oa.model,
+		Prompt: userPrompt,
+		System: systemPrompt,
+		Format: "json",
+		Stream: false,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/generate", oa.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := oa.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var genResp ollamaGenerateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&genResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return extractJSONAnalysis(genResp.Response)
+}
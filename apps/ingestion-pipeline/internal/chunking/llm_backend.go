@@ -0,0 +1,442 @@
+package chunking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
+)
+
+// LLMBackend runs KafkaAgenticChunker's chunking prompt against a provider and
+// returns its raw text completion. Each implementation is responsible for
+// normalizing its provider's JSON-mode / structured-output settings to
+// schema, so parseChunkResponse can stay provider-agnostic.
+type LLMBackend interface {
+	Complete(ctx context.Context, prompt string, schema map[string]interface{}) (string, error)
+	Model() string
+}
+
+// ErrBackendMisconfigured is wrapped by NewLLMBackend when config names an
+// unknown Provider or omits a setting that provider requires, so callers
+// fail fast at construction instead of on the first request.
+var ErrBackendMisconfigured = errors.New("llm backend misconfigured")
+
+// chunkResponseSchema describes the {"chunks": [...]} shape
+// parseChunkResponse expects, passed to each backend so it can enforce
+// structured output (OpenAI response_format=json_schema, Gemini
+// responseSchema, Anthropic tool input_schema).
+var chunkResponseSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"chunks": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"content":    map[string]interface{}{"type": "string"},
+					"topic":      map[string]interface{}{"type": "string"},
+					"keywords":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+					"entities":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+					"summary":    map[string]interface{}{"type": "string"},
+					"category":   map[string]interface{}{"type": "string"},
+					"sentiment":  map[string]interface{}{"type": "string"},
+					"complexity": map[string]interface{}{"type": "string"},
+					"language":   map[string]interface{}{"type": "string"},
+				},
+				"required": []string{"content", "topic", "keywords", "entities", "summary", "category", "sentiment", "complexity", "language"},
+			},
+		},
+	},
+	"required": []string{"chunks"},
+}
+
+// NewLLMBackend builds the LLMBackend named by config.Provider. "gemini"
+// (the default, for backward compatibility with GeminiAPIKey/GeminiModel)
+// calls Google's Generative Language API; "openai" calls the chat
+// completions API with response_format=json_schema; "anthropic" calls the
+// Messages API with a tool call forced to the given schema; "ollama" calls
+// a local/self-hosted Ollama server's native /api/generate.
+func NewLLMBackend(config models.ChunkingConfig) (LLMBackend, error) {
+	switch config.Provider {
+	case "", "gemini":
+		apiKey := config.APIKey
+		if apiKey == "" {
+			apiKey = config.GeminiAPIKey
+		}
+		if apiKey == "" {
+			return nil, fmt.Errorf("%w: gemini requires an API key", ErrBackendMisconfigured)
+		}
+		model := config.Model
+		if model == "" {
+			model = config.GeminiModel
+		}
+		return NewGeminiBackend(apiKey, model, config.RequestTimeout), nil
+
+	case "openai":
+		if config.APIKey == "" {
+			return nil, fmt.Errorf("%w: openai requires an API key", ErrBackendMisconfigured)
+		}
+		return NewOpenAIBackend(config.APIKey, config.Model, config.Endpoint, config.ExtraHeaders, config.RequestTimeout), nil
+
+	case "anthropic":
+		if config.APIKey == "" {
+			return nil, fmt.Errorf("%w: anthropic requires an API key", ErrBackendMisconfigured)
+		}
+		return NewAnthropicBackend(config.APIKey, config.Model, config.Endpoint, config.ExtraHeaders, config.RequestTimeout), nil
+
+	case "ollama":
+		if config.Endpoint == "" {
+			return nil, fmt.Errorf("%w: ollama requires an endpoint", ErrBackendMisconfigured)
+		}
+		return NewOllamaBackend(config.Endpoint, config.Model, config.ExtraHeaders, config.RequestTimeout), nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown provider %q", ErrBackendMisconfigured, config.Provider)
+	}
+}
+
+// GeminiBackend calls Google's Generative Language API with
+// responseMimeType: application/json and a responseSchema, so the model is
+// constrained to return JSON matching schema.
+type GeminiBackend struct {
+	apiKey  string
+	model   string
+	baseURL string
+	client  *http.Client
+}
+
+func NewGeminiBackend(apiKey, model string, timeout time.Duration) *GeminiBackend {
+	if model == "" {
+		model = "gemini-2.0-flash-lite"
+	}
+	return &GeminiBackend{
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent", model),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (g *GeminiBackend) Model() string { return g.model }
+
+type geminiGenerationConfig struct {
+	ResponseMimeType string                 `json:"responseMimeType,omitempty"`
+	ResponseSchema   map[string]interface{} `json:"responseSchema,omitempty"`
+}
+
+type geminiBackendRequest struct {
+	Contents         []models.Content       `json:"contents"`
+	GenerationConfig geminiGenerationConfig `json:"generationConfig"`
+}
+
+func (g *GeminiBackend) Complete(ctx context.Context, prompt string, schema map[string]interface{}) (string, error) {
+	reqBody := geminiBackendRequest{
+		Contents: []models.Content{{Parts: []models.Part{{Text: prompt}}}},
+		GenerationConfig: geminiGenerationConfig{
+			ResponseMimeType: "application/json",
+			ResponseSchema:   schema,
+		},
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s?key=%s", g.baseURL, g.apiKey)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("api request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var geminiResp models.GeminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&geminiResp); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("no content in gemini response")
+	}
+
+	return geminiResp.Candidates[0].Content.Parts[0].Text, nil
+}
+
+// OpenAIBackend calls /v1/chat/completions with
+// response_format={"type":"json_schema", "json_schema": {...}} so the model
+// is constrained to return JSON matching schema.
+type OpenAIBackend struct {
+	apiKey       string
+	model        string
+	baseURL      string
+	extraHeaders map[string]string
+	client       *http.Client
+}
+
+func NewOpenAIBackend(apiKey, model, baseURL string, extraHeaders map[string]string, timeout time.Duration) *OpenAIBackend {
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1/chat/completions"
+	}
+	return &OpenAIBackend{
+		apiKey:       apiKey,
+		model:        model,
+		baseURL:      baseURL,
+		extraHeaders: extraHeaders,
+		client:       &http.Client{Timeout: timeout},
+	}
+}
+
+func (ob *OpenAIBackend) Model() string { return ob.model }
+
+type openAIBackendJSONSchema struct {
+	Name   string                 `json:"name"`
+	Schema map[string]interface{} `json:"schema"`
+	Strict bool                   `json:"strict"`
+}
+
+type openAIBackendResponseFormat struct {
+	Type       string                  `json:"type"`
+	JSONSchema openAIBackendJSONSchema `json:"json_schema"`
+}
+
+type openAIBackendRequest struct {
+	Model          string                      `json:"model"`
+	Messages       []openAIChatMessage         `json:"messages"`
+	Temperature    float64                     `json:"temperature"`
+	ResponseFormat openAIBackendResponseFormat `json:"response_format"`
+}
+
+func (ob *OpenAIBackend) Complete(ctx context.Context, prompt string, schema map[string]interface{}) (string, error) {
+	reqBody := openAIBackendRequest{
+		Model: ob.model,
+		Messages: []openAIChatMessage{
+			{Role: "user", Content: prompt},
+		},
+		Temperature: 0.1,
+		ResponseFormat: openAIBackendResponseFormat{
+			Type: "json_schema",
+			JSONSchema: openAIBackendJSONSchema{
+				Name:   "chunk_response",
+				Schema: schema,
+				Strict: true,
+			},
+		},
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", ob.baseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", ob.apiKey))
+	for k, v := range ob.extraHeaders {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := ob.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("api request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(chatResp.Choices) == 0 {
+		return "", fmt.Errorf("no response content received")
+	}
+
+	return chatResp.Choices[0].Message.Content, nil
+}
+
+// AnthropicBackend calls the Messages API with a single forced tool call:
+// rather than relying on prose-wrapped JSON, the model's only valid move is
+// to invoke recordChunksToolName with arguments matching schema.
+type AnthropicBackend struct {
+	apiKey       string
+	model        string
+	baseURL      string
+	extraHeaders map[string]string
+	client       *http.Client
+}
+
+func NewAnthropicBackend(apiKey, model, baseURL string, extraHeaders map[string]string, timeout time.Duration) *AnthropicBackend {
+	if model == "" {
+		model = "claude-3-5-sonnet-20241022"
+	}
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com/v1/messages"
+	}
+	return &AnthropicBackend{
+		apiKey:       apiKey,
+		model:        model,
+		baseURL:      baseURL,
+		extraHeaders: extraHeaders,
+		client:       &http.Client{Timeout: timeout},
+	}
+}
+
+func (ab *AnthropicBackend) Model() string { return ab.model }
+
+const recordChunksToolName = "record_chunks"
+
+func (ab *AnthropicBackend) Complete(ctx context.Context, prompt string, schema map[string]interface{}) (string, error) {
+	reqBody := anthropicRequest{
+		Model:     ab.model,
+		MaxTokens: 4096,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: prompt},
+		},
+		Tools: []anthropicTool{
+			{
+				Name:        recordChunksToolName,
+				Description: "Record the chunked analysis of the given text.",
+				InputSchema: schema,
+			},
+		},
+		ToolChoice: anthropicToolChoice{Type: "tool", Name: recordChunksToolName},
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", ab.baseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", ab.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	for k, v := range ab.extraHeaders {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := ab.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("api request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var msgResp anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&msgResp); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	for _, block := range msgResp.Content {
+		if block.Type != "tool_use" {
+			continue
+		}
+		encoded, err := json.Marshal(block.Input)
+		if err != nil {
+			return "", fmt.Errorf("failed to re-encode tool input: %w", err)
+		}
+		return string(encoded), nil
+	}
+
+	return "", fmt.Errorf("no tool_use block in response")
+}
+
+// OllamaBackend calls a local/self-hosted Ollama server's /api/generate
+// with format="json", Ollama's equivalent of JSON mode. Ollama does not
+// support a provided schema directly, so schema enforcement here relies on
+// the prompt's own instructions plus parseChunkResponse's validation.
+type OllamaBackend struct {
+	baseURL      string
+	model        string
+	extraHeaders map[string]string
+	client       *http.Client
+}
+
+func NewOllamaBackend(baseURL, model string, extraHeaders map[string]string, timeout time.Duration) *OllamaBackend {
+	if model == "" {
+		model = "llama3.1"
+	}
+	return &OllamaBackend{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		model:        model,
+		extraHeaders: extraHeaders,
+		client:       &http.Client{Timeout: timeout},
+	}
+}
+
+func (ob *OllamaBackend) Model() string { return ob.model }
+
+func (ob *OllamaBackend) Complete(ctx context.Context, prompt string, schema map[string]interface{}) (string, error) {
+	reqBody := ollamaGenerateRequest{
+		Model:  ob.model,
+		Prompt: prompt,
+		Format: "json",
+		Stream: false,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/generate", ob.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range ob.extraHeaders {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := ob.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("api request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var genResp ollamaGenerateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&genResp); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return genResp.Response, nil
+}
@@ -0,0 +1,432 @@
+package chunking
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// Chunker splits a document's full text into StructuralChunk pieces,
+// populating SourceLocation.StartChar/EndChar/ChunkIndex so every chunk can
+// be traced back to its span in the original text. It's a deterministic
+// alternative to performSemanticChunking's StructuralSplitter path, selected
+// via Config.LocalChunkingMode; NewAgenticChunker wires the result in ahead
+// of the Gemini-driven analysis in analyzeChunksWithAI.
+type Chunker interface {
+	Chunk(ctx context.Context, text string) ([]StructuralChunk, error)
+}
+
+// NewChunker returns the Chunker config.LocalChunkingMode selects, or nil
+// (with no error) when LocalChunkingMode is empty, in which case the caller
+// should keep using the existing StructuralSplitter path.
+func NewChunker(config *Config, embedder Embedder) (Chunker, error) {
+	switch config.LocalChunkingMode {
+	case "":
+		return nil, nil
+	case "fixed_token":
+		return newFixedTokenChunker(config)
+	case "recursive_character":
+		return newRecursiveCharacterChunker(config), nil
+	case "semantic":
+		return newSemanticChunker(config, embedder)
+	default:
+		return nil, fmt.Errorf("unknown local chunking mode %q", config.LocalChunkingMode)
+	}
+}
+
+func estimateTokenCount(text string) int {
+	return utf8.RuneCountInString(text) / 4
+}
+
+// fixedTokenChunker packs tiktoken tokens into fixed-size windows, each
+// overlapping the previous by overlapTokens, per config.TokenOverlap.
+type fixedTokenChunker struct {
+	encoding      *tiktoken.Tiktoken
+	maxTokens     int
+	overlapTokens int
+}
+
+func newFixedTokenChunker(config *Config) (*fixedTokenChunker, error) {
+	encodingName := config.TokenizerEncoding
+	if encodingName == "" {
+		encodingName = "cl100k_base"
+	}
+
+	encoding, err := tiktoken.GetEncoding(encodingName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tokenizer encoding %q: %w", encodingName, err)
+	}
+
+	maxTokens := config.MaxChunkSize
+	if maxTokens <= 0 {
+		return nil, fmt.Errorf("fixed token chunker requires a positive MaxChunkSize")
+	}
+
+	return &fixedTokenChunker{
+		encoding:      encoding,
+		maxTokens:     maxTokens,
+		overlapTokens: config.TokenOverlap,
+	}, nil
+}
+
+func (c *fixedTokenChunker) Chunk(_ context.Context, text string) ([]StructuralChunk, error) {
+	tokens := c.encoding.Encode(text, nil, nil)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	step := c.maxTokens - c.overlapTokens
+	if step <= 0 {
+		step = c.maxTokens
+	}
+
+	var chunks []StructuralChunk
+	index := 0
+
+	for start := 0; start < len(tokens); start += step {
+		end := start + c.maxTokens
+		if end > len(tokens) {
+			end = len(tokens)
+		}
+
+		startChar := utf8.RuneCountInString(c.encoding.Decode(tokens[:start]))
+		endChar := utf8.RuneCountInString(c.encoding.Decode(tokens[:end]))
+		idx := index
+
+		chunks = append(chunks, StructuralChunk{
+			Text: c.encoding.Decode(tokens[start:end]),
+			Role: "paragraph",
+			Breadcrumb: SourceLocation{
+				StartChar:  &startChar,
+				EndChar:    &endChar,
+				ChunkIndex: &idx,
+			},
+		})
+		index++
+
+		if end == len(tokens) {
+			break
+		}
+	}
+
+	return chunks, nil
+}
+
+// recursiveSeparators is the hierarchy recursiveCharacterChunker walks,
+// trying each in order until one actually shortens an oversized piece.
+var recursiveSeparators = []string{"\n\n", "\n", ". ", " "}
+
+// recursiveCharacterChunker walks recursiveSeparators to break oversized
+// text, then greedily packs the resulting pieces back up to MaxChunkSize
+// while respecting MinChunkSize, the same packing shape splitProseText uses
+// for sentences.
+type recursiveCharacterChunker struct {
+	maxChunkSize int
+	minChunkSize int
+}
+
+func newRecursiveCharacterChunker(config *Config) *recursiveCharacterChunker {
+	return &recursiveCharacterChunker{
+		maxChunkSize: config.MaxChunkSize,
+		minChunkSize: config.MinChunkSize,
+	}
+}
+
+func (c *recursiveCharacterChunker) Chunk(_ context.Context, text string) ([]StructuralChunk, error) {
+	pieces := c.split(text, recursiveSeparators)
+	return c.pack(pieces), nil
+}
+
+// split recursively breaks text on the first separator in the hierarchy
+// that actually produces more than one piece, recursing into the next
+// separator for any piece still over MaxChunkSize. A piece that exceeds
+// MaxChunkSize even after " " splitting (a single long token, e.g. a URL)
+// is returned as-is rather than torn apart further.
+func (c *recursiveCharacterChunker) split(text string, hierarchy []string) []string {
+	if estimateTokenCount(text) <= c.maxChunkSize || len(hierarchy) == 0 {
+		return []string{text}
+	}
+
+	sep := hierarchy[0]
+	parts := strings.Split(text, sep)
+	if len(parts) == 1 {
+		return c.split(text, hierarchy[1:])
+	}
+
+	var pieces []string
+	for i, part := range parts {
+		if i < len(parts)-1 {
+			part += sep
+		}
+		if part == "" {
+			continue
+		}
+		if estimateTokenCount(part) > c.maxChunkSize {
+			pieces = append(pieces, c.split(part, hierarchy[1:])...)
+		} else {
+			pieces = append(pieces, part)
+		}
+	}
+	return pieces
+}
+
+// pack greedily combines consecutive pieces (which concatenate back into
+// the original text exactly, since split only ever breaks text apart and
+// never trims it) up to MaxChunkSize, tracking each packed chunk's rune
+// offset via a running cursor rather than re-searching the source text.
+func (c *recursiveCharacterChunker) pack(pieces []string) []StructuralChunk {
+	var chunks []StructuralChunk
+	var current strings.Builder
+	currentTokens := 0
+	cursor := 0
+	chunkStart := 0
+	index := 0
+
+	flush := func() {
+		if current.Len() == 0 {
+			return
+		}
+		chunkText := current.String()
+		startChar := chunkStart
+		endChar := chunkStart + utf8.RuneCountInString(chunkText)
+		idx := index
+
+		chunks = append(chunks, StructuralChunk{
+			Text: strings.TrimSpace(chunkText),
+			Role: "paragraph",
+			Breadcrumb: SourceLocation{
+				StartChar:  &startChar,
+				EndChar:    &endChar,
+				ChunkIndex: &idx,
+			},
+		})
+		index++
+		current.Reset()
+		currentTokens = 0
+	}
+
+	for _, piece := range pieces {
+		pieceTokens := estimateTokenCount(piece)
+
+		if currentTokens > 0 && currentTokens+pieceTokens > c.maxChunkSize {
+			if currentTokens >= c.minChunkSize {
+				flush()
+			}
+		}
+		if current.Len() == 0 {
+			chunkStart = cursor
+		}
+
+		current.WriteString(piece)
+		currentTokens += pieceTokens
+		cursor += utf8.RuneCountInString(piece)
+	}
+
+	if current.Len() > 0 && (currentTokens >= c.minChunkSize || len(chunks) == 0) {
+		flush()
+	}
+
+	return chunks
+}
+
+// sentenceSplitPattern finds sentence-ending punctuation runs; Go's RE2
+// engine doesn't support the lookbehind chunker.go's splitBySentences uses,
+// so sentences here are built from the punctuation-inclusive match itself
+// rather than a zero-width split point.
+var sentenceSplitPattern = regexp.MustCompile(`[^.!?]*[.!?]+`)
+
+type charSpan struct {
+	start, end int
+}
+
+// splitSentencesWithSpans breaks text into trimmed sentences plus each
+// one's rune offset span in text, including a final unterminated sentence
+// (no trailing ./!/?) if any text remains after the last match.
+func splitSentencesWithSpans(text string) ([]string, []charSpan) {
+	locs := sentenceSplitPattern.FindAllStringIndex(text, -1)
+
+	var sentences []string
+	var spans []charSpan
+	lastEnd := 0
+
+	appendSpan := func(byteStart, byteEnd int) {
+		raw := text[byteStart:byteEnd]
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" {
+			return
+		}
+		leading := len(raw) - len(strings.TrimLeft(raw, " \t\r\n"))
+		startChar := utf8.RuneCountInString(text[:byteStart]) + utf8.RuneCountInString(raw[:leading])
+		endChar := startChar + utf8.RuneCountInString(trimmed)
+		sentences = append(sentences, trimmed)
+		spans = append(spans, charSpan{start: startChar, end: endChar})
+	}
+
+	for _, loc := range locs {
+		appendSpan(loc[0], loc[1])
+		lastEnd = loc[1]
+	}
+	if lastEnd < len(text) {
+		appendSpan(lastEnd, len(text))
+	}
+
+	return sentences, spans
+}
+
+func cosineDistance(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	similarity := dot / (math.Sqrt(normA) * math.Sqrt(normB))
+	return 1 - similarity
+}
+
+// rollingPercentile returns the pct-th percentile (0-100) of the last
+// window elements of data, or 0 if data is empty (meaning no boundary has
+// enough history yet to judge an outlier).
+func rollingPercentile(data []float64, window int, pct float64) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+
+	start := 0
+	if len(data) > window {
+		start = len(data) - window
+	}
+	sample := append([]float64{}, data[start:]...)
+	sort.Float64s(sample)
+
+	rank := pct / 100 * float64(len(sample)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sample[lower]
+	}
+	frac := rank - float64(lower)
+	return sample[lower]*(1-frac) + sample[upper]*frac
+}
+
+// semanticChunker starts a new chunk wherever the cosine distance between
+// consecutive sentence embeddings exceeds the 95th-percentile distance seen
+// over the preceding SemanticWindowSize sentence boundaries. Any resulting
+// sentence group that still exceeds MaxChunkSize is handed to fallback
+// (recursive_character) instead of being kept as one oversized chunk.
+type semanticChunker struct {
+	embedder     Embedder
+	windowSize   int
+	maxChunkSize int
+	fallback     *recursiveCharacterChunker
+}
+
+func newSemanticChunker(config *Config, embedder Embedder) (*semanticChunker, error) {
+	if embedder == nil {
+		return nil, fmt.Errorf("semantic chunking mode requires an embedder")
+	}
+
+	windowSize := config.SemanticWindowSize
+	if windowSize <= 0 {
+		windowSize = 20
+	}
+
+	return &semanticChunker{
+		embedder:     embedder,
+		windowSize:   windowSize,
+		maxChunkSize: config.MaxChunkSize,
+		fallback:     newRecursiveCharacterChunker(config),
+	}, nil
+}
+
+func (c *semanticChunker) Chunk(ctx context.Context, text string) ([]StructuralChunk, error) {
+	sentences, spans := splitSentencesWithSpans(text)
+	if len(sentences) == 0 {
+		return nil, nil
+	}
+	if len(sentences) == 1 {
+		return c.fallback.Chunk(ctx, text)
+	}
+
+	vectors, err := c.embedder.Embed(ctx, sentences)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed sentences for semantic chunking: %w", err)
+	}
+
+	distances := make([]float64, len(sentences)-1)
+	for i := 1; i < len(vectors); i++ {
+		distances[i-1] = cosineDistance(vectors[i-1], vectors[i])
+	}
+
+	var chunks []StructuralChunk
+	index := 0
+	groupStart := 0
+
+	flushGroup := func(end int) error {
+		groupText := strings.Join(sentences[groupStart:end], " ")
+		startChar := spans[groupStart].start
+		endChar := spans[end-1].end
+
+		if estimateTokenCount(groupText) <= c.maxChunkSize {
+			idx := index
+			chunks = append(chunks, StructuralChunk{
+				Text: groupText,
+				Role: "paragraph",
+				Breadcrumb: SourceLocation{
+					StartChar:  &startChar,
+					EndChar:    &endChar,
+					ChunkIndex: &idx,
+				},
+			})
+			index++
+			return nil
+		}
+
+		sub, err := c.fallback.Chunk(ctx, groupText)
+		if err != nil {
+			return fmt.Errorf("recursive_character fallback failed for oversized sentence group: %w", err)
+		}
+		for _, sc := range sub {
+			idx := index
+			subStart := startChar + derefOrZero(sc.Breadcrumb.StartChar)
+			subEnd := startChar + derefOrZero(sc.Breadcrumb.EndChar)
+			sc.Breadcrumb.StartChar = &subStart
+			sc.Breadcrumb.EndChar = &subEnd
+			sc.Breadcrumb.ChunkIndex = &idx
+			chunks = append(chunks, sc)
+			index++
+		}
+		return nil
+	}
+
+	for i, dist := range distances {
+		threshold := rollingPercentile(distances[:i], c.windowSize, 95)
+		if threshold > 0 && dist > threshold {
+			if err := flushGroup(i + 1); err != nil {
+				return nil, err
+			}
+			groupStart = i + 1
+		}
+	}
+	if err := flushGroup(len(sentences)); err != nil {
+		return nil, err
+	}
+
+	return chunks, nil
+}
+
+func derefOrZero(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
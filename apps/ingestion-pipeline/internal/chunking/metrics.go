@@ -0,0 +1,103 @@
+package chunking
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+)
+
+// tracer emits the spans processSectionConcurrently, callLLM, and
+// streamChunkToKafka record around a section/chunk's journey through the
+// pipeline. With no TracerProvider registered (the common case outside a
+// deployment that wires one up) these are free no-ops.
+var tracer = otel.Tracer("github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/chunking")
+
+var (
+	sectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orbit_chunker_sections_total",
+		Help: "Sections KafkaAgenticChunker finished processing, by terminal status (success, retried, dead_lettered).",
+	}, []string{"status"})
+
+	chunksProducedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "orbit_chunker_chunks_produced_total",
+		Help: "Chunks streamed to Kafka by KafkaAgenticChunker.",
+	})
+
+	geminiRequestDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "orbit_chunker_gemini_request_duration_seconds",
+		Help:    "Latency of a single LLM backend call made by callLLM.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	geminiRetriesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "orbit_chunker_gemini_retries_total",
+		Help: "Retries of a single LLM backend call within callLLM's own retry loop.",
+	})
+
+	kafkaProduceDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "orbit_chunker_kafka_produce_duration_seconds",
+		Help:    "Latency of streamChunkToKafka, from Produce to delivery confirmation.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	kafkaDeliveryFailuresTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "orbit_chunker_kafka_delivery_failures_total",
+		Help: "Chunk deliveries that streamChunkToKafka failed to produce or confirm.",
+	})
+
+	activeWorkers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "orbit_chunker_active_workers",
+		Help: "ChunkTextStreaming worker goroutines currently processing a section.",
+	})
+
+	queueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "orbit_chunker_queue_depth",
+		Help: "Sections queued in the current ChunkTextStreaming call, waiting for a worker.",
+	})
+)
+
+// StartAdminServer starts an HTTP server on addr exposing Prometheus metrics
+// at /metrics and Go runtime profiles at /debug/pprof/*, separate from the
+// document-upload API server in cmd/main. Close shuts it down alongside the
+// Kafka producer.
+func (ac *KafkaAgenticChunker) StartAdminServer(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	ac.adminServer = server
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("chunker admin server stopped: %v\n", err)
+		}
+	}()
+
+	return nil
+}
+
+// closeAdminServer shuts down the admin server started by StartAdminServer,
+// if any. Called from Close.
+func (ac *KafkaAgenticChunker) closeAdminServer() {
+	if ac.adminServer == nil {
+		return
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := ac.adminServer.Shutdown(shutdownCtx); err != nil {
+		fmt.Printf("failed to shut down chunker admin server: %v\n", err)
+	}
+}
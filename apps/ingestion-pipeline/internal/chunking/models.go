@@ -3,7 +3,7 @@ package chunking
 import (
 	"time"
 
-	"github.com/NEMYSESx/orbit/apps/ingestion-pipeline/internal/models"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
 )
 
 type SourceLocation struct {
@@ -11,49 +11,59 @@ type SourceLocation struct {
 	StartChar  *int `json:"start_char,omitempty"`
 	EndChar    *int `json:"end_char,omitempty"`
 	ChunkIndex *int `json:"chunk_index,omitempty"`
+
+	// Structural breadcrumbs populated by StructuralSplitter so ChunkRole
+	// doesn't need to be guessed by the LLM for obvious cases.
+	HeadingPath  []string `json:"heading_path,omitempty"`
+	FunctionName string   `json:"function_name,omitempty"`
+	TableCaption string   `json:"table_caption,omitempty"`
 }
 
 type ChunkAnalysis struct {
 	ChunkRole              string    `json:"chunk_role"`
-	KeyEntities           []string  `json:"key_entities"`
-	Topics                []string  `json:"topics"`
-	OriginalContextSummary string   `json:"original_context_summary"`
-	LevelOfDetail         string    `json:"level_of_detail"`
-	AgentConfidence       float64   `json:"agent_confidence"`
-	ProcessingTimestamp   time.Time `json:"processing_timestamp"`
+	KeyEntities            []string  `json:"key_entities"`
+	Topics                 []string  `json:"topics"`
+	OriginalContextSummary string    `json:"original_context_summary"`
+	LevelOfDetail          string    `json:"level_of_detail"`
+	AgentConfidence        float64   `json:"agent_confidence"`
+	ProcessingTimestamp    time.Time `json:"processing_timestamp"`
 }
 
 type ChunkPayload struct {
-	Text                   string         `json:"text"`
-	SourceID              string         `json:"source_id"`
-	SourceType            string         `json:"source_type"`
-	Title                 string         `json:"title"`
-	Filepath              string         `json:"filepath"`
-	LastModifiedDate      time.Time      `json:"last_modified_date"`
-	SourceLocation        SourceLocation `json:"source_location"`
-	Analysis              ChunkAnalysis  `json:"analysis"`
-	RelatedChunks         []string       `json:"related_chunks"`
-	ProcessingMetadata    ProcessingMetadata `json:"processing_metadata"`
+	Text               string             `json:"text"`
+	SourceID           string             `json:"source_id"`
+	SourceType         string             `json:"source_type"`
+	Title              string             `json:"title"`
+	Filepath           string             `json:"filepath"`
+	LastModifiedDate   time.Time          `json:"last_modified_date"`
+	SourceLocation     SourceLocation     `json:"source_location"`
+	Analysis           ChunkAnalysis      `json:"analysis"`
+	RelatedChunks      []string           `json:"related_chunks"`
+	ProcessingMetadata ProcessingMetadata `json:"processing_metadata"`
+	SparseVector       SparseVector       `json:"sparse_vector,omitempty"`
 }
 
 type ProcessingMetadata struct {
-	ChunkID           string    `json:"chunk_id"`
-	ProcessedAt       time.Time `json:"processed_at"`
-	ProcessingVersion string    `json:"processing_version"`
-	TokenCount        int       `json:"token_count"`
-	ProcessingTimeMs  int64     `json:"processing_time_ms"`
+	ChunkID            string    `json:"chunk_id"`
+	ProcessedAt        time.Time `json:"processed_at"`
+	ProcessingVersion  string    `json:"processing_version"`
+	TokenCount         int       `json:"token_count"`
+	ProcessingTimeMs   int64     `json:"processing_time_ms"`
+	EmbeddingModel     string    `json:"embedding_model"`
+	EmbeddingDimension int       `json:"embedding_dimension"`
 }
 
 type QdrantPoint struct {
-	ID      string       `json:"id"`
-	Vector  []float64    `json:"vector"`
-	Payload ChunkPayload `json:"payload"`
+	ID           string       `json:"id"`
+	Vector       []float64    `json:"vector"`
+	SparseVector SparseVector `json:"sparse_vector,omitempty"`
+	Payload      ChunkPayload `json:"payload"`
 }
 
 type ChunkedDocument struct {
-	OriginalDocument models.ExtractedContent `json:"original_document"`
-	Chunks          []QdrantPoint           `json:"chunks"`
-	ProcessingSummary ProcessingSummary      `json:"processing_summary"`
+	OriginalDocument  models.ExtractedContent `json:"original_document"`
+	Chunks            []QdrantPoint           `json:"chunks"`
+	ProcessingSummary ProcessingSummary       `json:"processing_summary"`
 }
 
 type ProcessingSummary struct {
@@ -62,4 +72,6 @@ type ProcessingSummary struct {
 	AverageConfidence  float64       `json:"average_confidence"`
 	FailedChunks       int           `json:"failed_chunks"`
 	ProcessedAt        time.Time     `json:"processed_at"`
-}
\ No newline at end of file
+	CacheHits          int           `json:"cache_hits"`
+	CacheMisses        int           `json:"cache_misses"`
+}
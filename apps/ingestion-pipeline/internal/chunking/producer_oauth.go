@@ -0,0 +1,106 @@
+package chunking
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// producerOAuthRefresher keeps a *kafka.Producer's SASL/OAUTHBEARER token
+// fresh. A Kafka consumer can react to a librdkafka OAuthBearerTokenRefresh
+// event from inside its poll loop, but KafkaAgenticChunker's producer has no such
+// loop draining its Events() channel, so refresh instead runs on a
+// background goroutine timed to the token's own expiry.
+type producerOAuthRefresher struct {
+	cfg      *clientcredentials.Config
+	producer *kafka.Producer
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// startProducerOAuthRefresher fetches an initial token, pushes it into
+// producer, and starts the background refresh loop.
+func startProducerOAuthRefresher(producer *kafka.Producer, oauth models.OAuthConfig) (*producerOAuthRefresher, error) {
+	r := &producerOAuthRefresher{
+		cfg: &clientcredentials.Config{
+			ClientID:     oauth.ClientID,
+			ClientSecret: oauth.ClientSecret,
+			TokenURL:     oauth.TokenURL,
+			Scopes:       oauth.Scopes,
+		},
+		producer: producer,
+		stop:     make(chan struct{}),
+	}
+
+	expiry, err := r.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	r.wg.Add(1)
+	go r.loop(expiry)
+
+	return r, nil
+}
+
+// refresh fetches a fresh token and pushes it into the producer, returning
+// the token's expiry so loop knows when to run again.
+func (r *producerOAuthRefresher) refresh() (time.Time, error) {
+	token, err := r.cfg.Token(context.Background())
+	if err != nil {
+		r.producer.SetOAuthBearerTokenFailure(err.Error())
+		return time.Time{}, fmt.Errorf("failed to fetch OAuth2 token: %w", err)
+	}
+
+	expiry := token.Expiry
+	if expiry.IsZero() {
+		expiry = time.Now().Add(time.Hour)
+	}
+
+	if err := r.producer.SetOAuthBearerToken(kafka.OAuthBearerToken{
+		TokenValue: token.AccessToken,
+		Expiration: expiry,
+		Principal:  r.cfg.ClientID,
+	}); err != nil {
+		return time.Time{}, err
+	}
+
+	return expiry, nil
+}
+
+// loop refreshes the token shortly before it expires, until Close is
+// called.
+func (r *producerOAuthRefresher) loop(nextExpiry time.Time) {
+	defer r.wg.Done()
+
+	for {
+		wait := time.Until(nextExpiry) - 30*time.Second
+		if wait < 0 {
+			wait = 0
+		}
+
+		select {
+		case <-time.After(wait):
+			expiry, err := r.refresh()
+			if err != nil {
+				fmt.Printf("producer OAuth2 token refresh failed, retrying shortly: %v\n", err)
+				nextExpiry = time.Now().Add(30 * time.Second)
+				continue
+			}
+			nextExpiry = expiry
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+func (r *producerOAuthRefresher) Close() {
+	close(r.stop)
+	r.wg.Wait()
+}
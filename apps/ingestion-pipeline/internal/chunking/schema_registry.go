@@ -0,0 +1,157 @@
+package chunking
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// SubjectNamingStrategy selects how a Confluent Schema Registry subject
+// name is derived from a topic and a record's schema name, mirroring the
+// three built-in strategies Confluent's serializers support.
+type SubjectNamingStrategy string
+
+const (
+	// TopicNameStrategy names the subject "<topic>-value" (the default).
+	// All records on a topic must share one schema.
+	TopicNameStrategy SubjectNamingStrategy = "TopicName"
+
+	// RecordNameStrategy names the subject after the record's fully
+	// qualified schema name, so one topic can carry multiple record types.
+	RecordNameStrategy SubjectNamingStrategy = "RecordName"
+
+	// TopicRecordNameStrategy combines both: "<topic>-<recordName>".
+	TopicRecordNameStrategy SubjectNamingStrategy = "TopicRecordName"
+)
+
+// subjectFor derives the Schema Registry subject name for strategy. An
+// empty or unrecognized strategy falls back to TopicNameStrategy.
+func subjectFor(strategy SubjectNamingStrategy, topic, recordName string) string {
+	switch strategy {
+	case RecordNameStrategy:
+		return recordName
+	case TopicRecordNameStrategy:
+		return fmt.Sprintf("%s-%s", topic, recordName)
+	default:
+		return topic + "-value"
+	}
+}
+
+// SchemaRegistryClient is a minimal Confluent Schema Registry REST client:
+// just enough to register a schema, fetch one back by ID, and set a
+// subject's compatibility mode.
+type SchemaRegistryClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewSchemaRegistryClient(baseURL string) *SchemaRegistryClient {
+	return &SchemaRegistryClient{
+		baseURL: baseURL,
+		client:  &http.Client{},
+	}
+}
+
+type registerSchemaRequest struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+type registerSchemaResponse struct {
+	ID int `json:"id"`
+}
+
+// Register registers schema (schemaType is "AVRO", "JSON", "PROTOBUF", or
+// empty for Avro, the registry's default) under subject and returns its
+// schema ID. Registering an already-registered schema is idempotent and
+// returns the existing ID.
+func (c *SchemaRegistryClient) Register(subject, schema, schemaType string) (int, error) {
+	body, err := json.Marshal(registerSchemaRequest{Schema: schema, SchemaType: schemaType})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal schema registration request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/subjects/%s/versions", c.baseURL, subject)
+	resp, err := c.client.Post(url, "application/vnd.schemaregistry.v1+json", bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to reach schema registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("schema registration failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var parsed registerSchemaResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return 0, fmt.Errorf("failed to parse schema registration response: %w", err)
+	}
+
+	return parsed.ID, nil
+}
+
+type schemaByIDResponse struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType"`
+}
+
+// GetByID fetches the raw schema text and schema type registered under id.
+func (c *SchemaRegistryClient) GetByID(id int) (schema, schemaType string, err error) {
+	url := fmt.Sprintf("%s/schemas/ids/%d", c.baseURL, id)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to reach schema registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("schema lookup failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var parsed schemaByIDResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return "", "", fmt.Errorf("failed to parse schema lookup response: %w", err)
+	}
+	if parsed.SchemaType == "" {
+		parsed.SchemaType = "AVRO"
+	}
+
+	return parsed.Schema, parsed.SchemaType, nil
+}
+
+type compatibilityRequest struct {
+	Compatibility string `json:"compatibility"`
+}
+
+// SetCompatibility sets subject's compatibility mode (e.g. "BACKWARD",
+// "FORWARD", "FULL", "NONE"), enforcing it on every later registration.
+func (c *SchemaRegistryClient) SetCompatibility(subject, mode string) error {
+	body, err := json.Marshal(compatibilityRequest{Compatibility: mode})
+	if err != nil {
+		return fmt.Errorf("failed to marshal compatibility request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/config/%s", c.baseURL, subject)
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach schema registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("setting compatibility failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
@@ -0,0 +1,263 @@
+package chunking
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/linkedin/goavro/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// srMagicByte prefixes every message serialized with a schema, per
+// Confluent's wire format: one magic byte, a 4-byte big-endian schema ID,
+// then the encoded payload.
+const srMagicByte byte = 0x0
+
+// encodeSRWireFormat wraps payload in the Schema Registry wire format so a
+// consumer can recover schemaID without a side channel.
+func encodeSRWireFormat(schemaID int, payload []byte) []byte {
+	framed := make([]byte, 1+4+len(payload))
+	framed[0] = srMagicByte
+	binary.BigEndian.PutUint32(framed[1:5], uint32(schemaID))
+	copy(framed[5:], payload)
+	return framed
+}
+
+// Serializer turns a chunk message into the bytes streamChunkToKafka
+// produces to Kafka. Every implementation registers its schema once, at
+// construction, and prefixes each serialized message with that schema's ID
+// in the Schema Registry wire format.
+type Serializer interface {
+	Serialize(v any) ([]byte, error)
+}
+
+// chunkOutputJSONSchema is the JSON Schema registered for ChunkOutput when
+// Format is "json". It documents the wire shape for schema evolution
+// checks even though JSONSerializer itself doesn't validate against it.
+const chunkOutputJSONSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "ChunkOutput",
+  "type": "object",
+  "properties": {
+    "text": {"type": "string"},
+    "source": {"type": "object"},
+    "chunk_metadata": {"type": "object"},
+    "payload_ref": {"type": ["object", "null"]}
+  },
+  "required": ["source", "chunk_metadata"]
+}`
+
+// chunkOutputAvroSchema mirrors models.ChunkOutput's JSON shape. Nested
+// objects that can independently be absent (source, chunk_metadata) are
+// modeled as Avro records rather than maps so evolution is field-checked.
+const chunkOutputAvroSchema = `{
+  "type": "record",
+  "name": "ChunkOutput",
+  "fields": [
+    {"name": "text", "type": ["null", "string"], "default": null},
+    {"name": "source", "type": {
+      "type": "record", "name": "SourceInfo",
+      "fields": [
+        {"name": "document_title", "type": "string"},
+        {"name": "document_type", "type": "string"},
+        {"name": "section", "type": ["null", "string"], "default": null},
+        {"name": "page_number", "type": ["null", "int"], "default": null},
+        {"name": "last_modified", "type": ["null", "string"], "default": null}
+      ]
+    }},
+    {"name": "chunk_metadata", "type": {
+      "type": "record", "name": "ChunkMetadata",
+      "fields": [
+        {"name": "topic", "type": "string"},
+        {"name": "keywords", "type": {"type": "array", "items": "string"}},
+        {"name": "entities", "type": {"type": "array", "items": "string"}},
+        {"name": "summary", "type": "string"},
+        {"name": "category", "type": "string"},
+        {"name": "sentiment", "type": "string"},
+        {"name": "complexity", "type": "string"},
+        {"name": "language", "type": "string"},
+        {"name": "word_count", "type": "int"},
+        {"name": "chunk_index", "type": "int"},
+        {"name": "timestamp", "type": "string"}
+      ]
+    }},
+    {"name": "payload_ref", "type": ["null", {
+      "type": "record", "name": "PayloadRef",
+      "fields": [
+        {"name": "payload_uri", "type": "string"},
+        {"name": "size", "type": "long"},
+        {"name": "etag", "type": "string"}
+      ]
+    }], "default": null}
+  ]
+}`
+
+// chunkOutputProtoSchema is the .proto source registered for ChunkOutput
+// when Format is "protobuf", kept in the registry as the source of truth
+// for downstream codegen. ProtobufSerializer itself can't compile this
+// (protoc isn't available in this pipeline), so it encodes messages as a
+// google.protobuf.Struct instead — genuine protobuf wire format, just
+// untyped, until generated ChunkOutput message code replaces it.
+const chunkOutputProtoSchema = `syntax = "proto3";
+
+package orbit.chunking;
+
+message SourceInfo {
+  string document_title = 1;
+  string document_type = 2;
+  string section = 3;
+  string last_modified = 4;
+}
+
+message ChunkMetadata {
+  string topic = 1;
+  repeated string keywords = 2;
+  repeated string entities = 3;
+  string summary = 4;
+  string category = 5;
+  string sentiment = 6;
+  string complexity = 7;
+  string language = 8;
+  int32 word_count = 9;
+  int32 chunk_index = 10;
+  string timestamp = 11;
+}
+
+message PayloadRef {
+  string payload_uri = 1;
+  int64 size = 2;
+  string etag = 3;
+}
+
+message ChunkOutput {
+  string text = 1;
+  SourceInfo source = 2;
+  ChunkMetadata chunk_metadata = 3;
+  PayloadRef payload_ref = 4;
+}
+`
+
+// JSONSerializer wraps json.Marshal with the Schema Registry wire format,
+// registering chunkOutputJSONSchema once at construction.
+type JSONSerializer struct {
+	schemaID int
+}
+
+func NewJSONSerializer(registry *SchemaRegistryClient, subject string) (*JSONSerializer, error) {
+	id, err := registry.Register(subject, chunkOutputJSONSchema, "JSON")
+	if err != nil {
+		return nil, fmt.Errorf("failed to register JSON schema for %s: %w", subject, err)
+	}
+	return &JSONSerializer{schemaID: id}, nil
+}
+
+func (s *JSONSerializer) Serialize(v any) ([]byte, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal json payload: %w", err)
+	}
+	return encodeSRWireFormat(s.schemaID, payload), nil
+}
+
+// AvroSerializer encodes messages as Avro binary, registering
+// chunkOutputAvroSchema once at construction.
+type AvroSerializer struct {
+	schemaID int
+	codec    *goavro.Codec
+}
+
+func NewAvroSerializer(registry *SchemaRegistryClient, subject string) (*AvroSerializer, error) {
+	id, err := registry.Register(subject, chunkOutputAvroSchema, "AVRO")
+	if err != nil {
+		return nil, fmt.Errorf("failed to register Avro schema for %s: %w", subject, err)
+	}
+
+	codec, err := goavro.NewCodec(chunkOutputAvroSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile Avro codec: %w", err)
+	}
+
+	return &AvroSerializer{schemaID: id, codec: codec}, nil
+}
+
+// Serialize round-trips v through JSON to get a goavro-native
+// map[string]interface{}, then encodes that natively to Avro binary. v is
+// expected to already match chunkOutputAvroSchema's field names (Go structs
+// tagged for JSON happen to produce them, since both were written from the
+// same field list).
+func (s *AvroSerializer) Serialize(v any) ([]byte, error) {
+	jsonPayload, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload to json: %w", err)
+	}
+
+	native, _, err := s.codec.NativeFromTextual(jsonPayload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert payload to avro native form: %w", err)
+	}
+
+	binaryPayload, err := s.codec.BinaryFromNative(nil, native)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode avro binary: %w", err)
+	}
+
+	return encodeSRWireFormat(s.schemaID, binaryPayload), nil
+}
+
+// ProtobufSerializer encodes messages as a google.protobuf.Struct, the
+// closest dependency-free stand-in for a generated ChunkOutput protobuf
+// message available without running protoc. It registers
+// chunkOutputProtoSchema (the intended real .proto source) under subject so
+// the registry carries the true schema for whenever generated types land.
+type ProtobufSerializer struct {
+	schemaID int
+}
+
+func NewProtobufSerializer(registry *SchemaRegistryClient, subject string) (*ProtobufSerializer, error) {
+	id, err := registry.Register(subject, chunkOutputProtoSchema, "PROTOBUF")
+	if err != nil {
+		return nil, fmt.Errorf("failed to register protobuf schema for %s: %w", subject, err)
+	}
+	return &ProtobufSerializer{schemaID: id}, nil
+}
+
+func (s *ProtobufSerializer) Serialize(v any) ([]byte, error) {
+	jsonPayload, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload to json: %w", err)
+	}
+
+	var asMap map[string]interface{}
+	if err := json.Unmarshal(jsonPayload, &asMap); err != nil {
+		return nil, fmt.Errorf("failed to convert payload to a struct map: %w", err)
+	}
+
+	st, err := structpb.NewStruct(asMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build protobuf struct: %w", err)
+	}
+
+	binaryPayload, err := proto.Marshal(st)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal protobuf payload: %w", err)
+	}
+
+	return encodeSRWireFormat(s.schemaID, binaryPayload), nil
+}
+
+// NewSerializer builds the Serializer for format ("json", "avro", or
+// "protobuf"), registering its schema under subject.
+func NewSerializer(format string, registry *SchemaRegistryClient, subject string) (Serializer, error) {
+	switch format {
+	case "", "json":
+		return NewJSONSerializer(registry, subject)
+	case "avro":
+		return NewAvroSerializer(registry, subject)
+	case "protobuf":
+		return NewProtobufSerializer(registry, subject)
+	default:
+		return nil, fmt.Errorf("unknown schema registry format %q", format)
+	}
+}
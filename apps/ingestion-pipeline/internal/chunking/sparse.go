@@ -0,0 +1,186 @@
+package chunking
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// SparseVector is a term-index/weight pair list suitable for Qdrant's named
+// sparse vector shape.
+type SparseVector struct {
+	Indices []uint32  `json:"indices"`
+	Values  []float32 `json:"values"`
+}
+
+// SparseEncoder produces a sparse term-weighted vector for a chunk of text.
+// sourceType scopes the corpus statistics used for IDF so, e.g., "pdf" and
+// "code" documents don't skew each other's term weights.
+type SparseEncoder interface {
+	Encode(sourceType, text string) (SparseVector, error)
+	Model() string
+}
+
+// NewSparseEncoder builds a SparseEncoder from config. Only "bm25" is
+// implemented today; "none" disables sparse vector generation entirely.
+func NewSparseEncoder(cfg *Config) (SparseEncoder, error) {
+	switch cfg.SparseEncoderType {
+	case "", "bm25":
+		return NewBM25Encoder(cfg.SparseStatsPath)
+	case "none":
+		return nil, nil
+	default:
+		return NewBM25Encoder(cfg.SparseStatsPath)
+	}
+}
+
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "has": true, "he": true,
+	"in": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"that": true, "the": true, "to": true, "was": true, "were": true,
+	"will": true, "with": true, "this": true, "but": true, "or": true,
+	"not": true, "you": true, "your": true, "i": true, "we": true,
+}
+
+var tokenPattern = regexp.MustCompile(`[a-z0-9]+`)
+
+func tokenize(text string) []string {
+	lowered := strings.ToLower(text)
+	raw := tokenPattern.FindAllString(lowered, -1)
+
+	tokens := make([]string, 0, len(raw))
+	for _, tok := range raw {
+		if stopwords[tok] {
+			continue
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens
+}
+
+type bm25SourceStats struct {
+	DocFrequency   map[string]int `json:"doc_frequency"`
+	TotalDocs      int            `json:"total_docs"`
+	TotalTermCount int64          `json:"total_term_count"`
+}
+
+type bm25Stats struct {
+	Vocabulary    map[string]uint32           `json:"vocabulary"`
+	NextIndex     uint32                      `json:"next_index"`
+	PerSourceType map[string]*bm25SourceStats `json:"per_source_type"`
+}
+
+// BM25Encoder is a default in-process SparseEncoder implementation. It
+// maintains document-frequency/length statistics per source_type, persisted
+// to disk so IDF weights stay stable (and keep improving) across runs.
+type BM25Encoder struct {
+	mu        sync.Mutex
+	statsPath string
+	stats     *bm25Stats
+	k1        float64
+	b         float64
+}
+
+func NewBM25Encoder(statsPath string) (*BM25Encoder, error) {
+	enc := &BM25Encoder{
+		statsPath: statsPath,
+		k1:        1.5,
+		b:         0.75,
+		stats: &bm25Stats{
+			Vocabulary:    make(map[string]uint32),
+			PerSourceType: make(map[string]*bm25SourceStats),
+		},
+	}
+
+	if statsPath == "" {
+		return enc, nil
+	}
+
+	data, err := os.ReadFile(statsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return enc, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, enc.stats); err != nil {
+		return nil, err
+	}
+	return enc, nil
+}
+
+func (e *BM25Encoder) Model() string { return "bm25" }
+
+func (e *BM25Encoder) Encode(sourceType, text string) (SparseVector, error) {
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return SparseVector{}, nil
+	}
+
+	termFreq := make(map[string]int, len(tokens))
+	for _, tok := range tokens {
+		termFreq[tok]++
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	source, ok := e.stats.PerSourceType[sourceType]
+	if !ok {
+		source = &bm25SourceStats{DocFrequency: make(map[string]int)}
+		e.stats.PerSourceType[sourceType] = source
+	}
+
+	source.TotalDocs++
+	source.TotalTermCount += int64(len(tokens))
+	for term := range termFreq {
+		source.DocFrequency[term]++
+	}
+
+	avgDocLen := float64(source.TotalTermCount) / float64(source.TotalDocs)
+	docLen := float64(len(tokens))
+
+	indices := make([]uint32, 0, len(termFreq))
+	values := make([]float32, 0, len(termFreq))
+
+	for term, tf := range termFreq {
+		idx, ok := e.stats.Vocabulary[term]
+		if !ok {
+			idx = e.stats.NextIndex
+			e.stats.Vocabulary[term] = idx
+			e.stats.NextIndex++
+		}
+
+		df := float64(source.DocFrequency[term])
+		n := float64(source.TotalDocs)
+		idf := math.Log((n-df+0.5)/(df+0.5) + 1)
+
+		tfComponent := (float64(tf) * (e.k1 + 1)) / (float64(tf) + e.k1*(1-e.b+e.b*docLen/avgDocLen))
+
+		indices = append(indices, idx)
+		values = append(values, float32(idf*tfComponent))
+	}
+
+	if err := e.save(); err != nil {
+		return SparseVector{}, err
+	}
+
+	return SparseVector{Indices: indices, Values: values}, nil
+}
+
+func (e *BM25Encoder) save() error {
+	if e.statsPath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(e.stats)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(e.statsPath, data, 0644)
+}
@@ -1,26 +1,83 @@
 package chunking
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 
-	"github.com/NEMYSESx/orbit/apps/ingestion-pipeline/internal/models"
-	"github.com/NEMYSESx/orbit/apps/ingestion-pipeline/internal/storage"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/qdrant"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/storage"
 )
 
 type ChunkingStorageManager struct {
-	storageManager *storage.Manager
-	config         *Config
+	storageManager  *storage.Manager
+	config          *Config
+	webhookNotifier *WebhookNotifier
+	chunkIndex      *ChunkIndex
+	qdrantClient    *qdrant.Client
+	adminServer     *http.Server
 }
 
-func NewChunkingStorageManager(storageManager *storage.Manager, config *Config) *ChunkingStorageManager {
+func NewChunkingStorageManager(storageManager *storage.Manager, config *Config) (*ChunkingStorageManager, error) {
+	chunkIndex, err := NewChunkIndex(config.ChunkIndexPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open chunk index: %w", err)
+	}
+
+	qdrantConfig := qdrant.DefaultConfig()
+	qdrantConfig.BaseURL = config.QdrantURL
+	qdrantConfig.Collection = config.QdrantCollection
+
 	return &ChunkingStorageManager{
-		storageManager: storageManager,
-		config:         config,
+		storageManager:  storageManager,
+		config:          config,
+		webhookNotifier: NewWebhookNotifier(config.WebhookEndpoints),
+		chunkIndex:      chunkIndex,
+		qdrantClient:    qdrant.NewClient(qdrantConfig),
+	}, nil
+}
+
+// touchChunkIndex looks up chunk's content-addressable key in the chunk
+// index and sets chunk.ID to the point ID on file for that key: chunk's
+// own ID (or the key, if chunk has no ID) the first time the content is
+// seen, or the ID recorded at that first time on a repeat. Re-ingesting the same
+// content always resolves to the same point ID, so a downstream Qdrant
+// upsert is naturally idempotent rather than minting a fresh point every
+// time. hit reports whether this chunk's content was already indexed.
+func (csm *ChunkingStorageManager) touchChunkIndex(chunk *QdrantPoint) (hit bool, err error) {
+	key := ChunkKey(chunk.Payload.Text, csm.config.EmbeddingModel)
+
+	entry := ChunkIndexEntry{
+		PointID:    chunk.ID,
+		SourceType: chunk.Payload.SourceType,
+		Bytes:      len(chunk.Payload.Text),
+	}
+	if entry.PointID == "" {
+		entry.PointID = key
 	}
+
+	pointID, hit, err := csm.chunkIndex.Touch(key, entry)
+	if err != nil {
+		return false, fmt.Errorf("chunk index lookup failed: %w", err)
+	}
+
+	chunk.ID = pointID
+	return hit, nil
 }
 
 func (csm *ChunkingStorageManager) SaveChunkedDocument(chunkedDoc *ChunkedDocument) (string, error) {
+	for i := range chunkedDoc.Chunks {
+		if _, err := csm.touchChunkIndex(&chunkedDoc.Chunks[i]); err != nil {
+			return "", err
+		}
+	}
+	if err := csm.chunkIndex.IncrementDocuments(); err != nil {
+		fmt.Printf("chunk index document count update failed: %v\n", err)
+	}
+
 	// Create a new ExtractedContent for the chunked document
 	chunkedContent := &models.ExtractedContent{
 		Metadata: models.DocumentMetadata{
@@ -42,13 +99,36 @@ func (csm *ChunkingStorageManager) SaveChunkedDocument(chunkedDoc *ChunkedDocume
 		CleanText: csm.generateChunkedSummary(chunkedDoc),
 	}
 
-	return csm.storageManager.Save(chunkedContent)
+	savedPath, err := csm.storageManager.Save(chunkedContent)
+	if err != nil {
+		return "", err
+	}
+
+	sourceType := chunkedDoc.OriginalDocument.Metadata.SourceType
+	if err := csm.webhookNotifier.Notify(sourceType, chunkedDoc); err != nil {
+		fmt.Printf("webhook notification failed for document %s: %v\n", chunkedDoc.OriginalDocument.Metadata.ID, err)
+	}
+
+	return savedPath, nil
 }
 
 func (csm *ChunkingStorageManager) SaveChunksAsIndividualFiles(chunkedDoc *ChunkedDocument) ([]string, error) {
 	var savedPaths []string
 
-	for i, chunk := range chunkedDoc.Chunks {
+	for i := range chunkedDoc.Chunks {
+		chunk := &chunkedDoc.Chunks[i]
+
+		hit, err := csm.touchChunkIndex(chunk)
+		if err != nil {
+			return savedPaths, err
+		}
+		if hit {
+			// Identical content already written under this chunk's point
+			// ID; the reference-count bump above is all this re-ingestion
+			// needs.
+			continue
+		}
+
 		chunkContent := &models.ExtractedContent{
 			Metadata: models.DocumentMetadata{
 				ID:               fmt.Sprintf("%s_chunk_%d", chunkedDoc.OriginalDocument.Metadata.ID, i),
@@ -88,9 +168,91 @@ func (csm *ChunkingStorageManager) generateChunkedSummary(chunkedDoc *ChunkedDoc
 		summary += fmt.Sprintf("Role: %s\n", chunk.Payload.Analysis.ChunkRole)
 		summary += fmt.Sprintf("Topics: %v\n", chunk.Payload.Analysis.Topics)
 		summary += fmt.Sprintf("Confidence: %.2f\n", chunk.Payload.Analysis.AgentConfidence)
-		summary += fmt.Sprintf("Text Preview: %s...\n\n", 
+		summary += fmt.Sprintf("Text Preview: %s...\n\n",
 			chunk.Payload.Text[:min(100, len(chunk.Payload.Text))])
 	}
 
 	return summary
 }
+
+// Compact drops every chunk index entry whose reference count has reached
+// zero and deletes the corresponding points from Qdrant, so vector storage
+// doesn't keep serving embeddings for chunks the file store no longer has.
+func (csm *ChunkingStorageManager) Compact(ctx context.Context) (int, error) {
+	deletedPointIDs, err := csm.chunkIndex.Compact()
+	if err != nil {
+		return 0, fmt.Errorf("chunk index compaction failed: %w", err)
+	}
+	if len(deletedPointIDs) == 0 {
+		return 0, nil
+	}
+
+	if err := csm.qdrantClient.Delete(ctx, deletedPointIDs...); err != nil {
+		return 0, fmt.Errorf("failed to delete compacted points from qdrant: %w", err)
+	}
+
+	return len(deletedPointIDs), nil
+}
+
+// StartAdminServer starts an HTTP server on addr exposing the chunk
+// index's usage report at /usage and a manual compaction trigger at
+// /compact (POST), mirroring KafkaAgenticChunker.StartAdminServer's separation
+// of admin concerns from the document-upload API server in cmd/main.
+func (csm *ChunkingStorageManager) StartAdminServer(addr string) error {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/usage", func(w http.ResponseWriter, r *http.Request) {
+		report, err := csm.chunkIndex.UsageReport()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			fmt.Printf("failed to write usage report response: %v\n", err)
+		}
+	})
+
+	mux.HandleFunc("/compact", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		compacted, err := csm.Compact(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]int{"compacted": compacted}); err != nil {
+			fmt.Printf("failed to write compact response: %v\n", err)
+		}
+	})
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	csm.adminServer = server
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("chunk storage admin server stopped: %v\n", err)
+		}
+	}()
+
+	return nil
+}
+
+// Close shuts down the admin server, if started, and the chunk index.
+func (csm *ChunkingStorageManager) Close() error {
+	if csm.adminServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := csm.adminServer.Shutdown(shutdownCtx); err != nil {
+			fmt.Printf("failed to shut down chunk storage admin server: %v\n", err)
+		}
+	}
+
+	return csm.chunkIndex.Close()
+}
@@ -0,0 +1,275 @@
+package chunking
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// StructuralChunk is one unit produced by StructuralSplitter. Role mirrors
+// the chunk_role values ChunkAnalysis normally has the LLM infer, and
+// Breadcrumb carries the structural location (heading path, function name,
+// table caption) so callers can skip guessing those for obvious cases.
+type StructuralChunk struct {
+	Text       string
+	Role       string
+	Breadcrumb SourceLocation
+}
+
+// StructuralSplitter dispatches to a format-aware splitter based on
+// ExtractedContent.Metadata.ContentType instead of treating every document
+// as prose. Markdown gets heading-aware splitting that keeps code fences
+// and tables intact; recognized source code gets split on function/class
+// boundaries; everything else falls back to the existing prose splitter.
+type StructuralSplitter struct {
+	maxChunkSize int
+	minChunkSize int
+}
+
+func NewStructuralSplitter(config *Config) *StructuralSplitter {
+	return &StructuralSplitter{
+		maxChunkSize: config.MaxChunkSize,
+		minChunkSize: config.MinChunkSize,
+	}
+}
+
+var (
+	headingPattern    = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	codeFencePattern  = regexp.MustCompile("^```")
+	tableRowPattern   = regexp.MustCompile(`^\s*\|`)
+	codeBoundaryRegex = regexp.MustCompile(`^\s*(func|def|class)\s+([A-Za-z0-9_]+)`)
+)
+
+func (s *StructuralSplitter) estimateTokenCount(text string) int {
+	return utf8.RuneCountInString(text) / 4
+}
+
+// Split picks a format-aware splitter based on contentType.
+func (s *StructuralSplitter) Split(contentType, text string) []StructuralChunk {
+	switch {
+	case isMarkdownContentType(contentType):
+		return s.splitMarkdown(text)
+	case isCodeContentType(contentType):
+		return s.splitCode(text)
+	default:
+		return s.splitProse(text)
+	}
+}
+
+func isMarkdownContentType(contentType string) bool {
+	ct := strings.ToLower(contentType)
+	return strings.Contains(ct, "markdown") || strings.HasSuffix(ct, "/md")
+}
+
+func isCodeContentType(contentType string) bool {
+	ct := strings.ToLower(contentType)
+	for _, marker := range []string{"x-go", "x-python", "x-java", "x-c", "x-script", "javascript", "typescript", "code"} {
+		if strings.Contains(ct, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitMarkdown walks the document line by line, tracking a heading-level
+// stack so every emitted chunk carries its full heading path, and treats
+// fenced code blocks and pipe tables as atomic chunks rather than letting
+// prose-oriented paragraph/sentence splitting tear them apart.
+func (s *StructuralSplitter) splitMarkdown(text string) []StructuralChunk {
+	lines := strings.Split(text, "\n")
+	var chunks []StructuralChunk
+	var headingPath []string
+	var paragraph []string
+	var lastNonBlank string
+
+	flushParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		joined := strings.TrimSpace(strings.Join(paragraph, "\n"))
+		paragraph = nil
+		if joined == "" {
+			return
+		}
+		for _, sub := range s.splitProseText(joined) {
+			chunks = append(chunks, StructuralChunk{
+				Text:       sub,
+				Role:       "paragraph",
+				Breadcrumb: SourceLocation{HeadingPath: append([]string{}, headingPath...)},
+			})
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			level := len(m[1])
+			title := strings.TrimSpace(m[2])
+			if level-1 < len(headingPath) {
+				headingPath = headingPath[:level-1]
+			}
+			for len(headingPath) < level-1 {
+				headingPath = append(headingPath, "")
+			}
+			headingPath = append(headingPath, title)
+
+			chunks = append(chunks, StructuralChunk{
+				Text:       title,
+				Role:       "heading",
+				Breadcrumb: SourceLocation{HeadingPath: append([]string{}, headingPath...)},
+			})
+			continue
+		}
+
+		if codeFencePattern.MatchString(strings.TrimSpace(line)) {
+			flushParagraph()
+			var fenceLines []string
+			fenceLines = append(fenceLines, line)
+			for i++; i < len(lines); i++ {
+				fenceLines = append(fenceLines, lines[i])
+				if codeFencePattern.MatchString(strings.TrimSpace(lines[i])) {
+					break
+				}
+			}
+			chunks = append(chunks, StructuralChunk{
+				Text:       strings.Join(fenceLines, "\n"),
+				Role:       "code_snippet",
+				Breadcrumb: SourceLocation{HeadingPath: append([]string{}, headingPath...)},
+			})
+			continue
+		}
+
+		if tableRowPattern.MatchString(line) {
+			flushParagraph()
+			var tableLines []string
+			for ; i < len(lines) && tableRowPattern.MatchString(lines[i]); i++ {
+				tableLines = append(tableLines, lines[i])
+			}
+			i--
+
+			chunks = append(chunks, StructuralChunk{
+				Text: strings.Join(tableLines, "\n"),
+				Role: "table_data",
+				Breadcrumb: SourceLocation{
+					HeadingPath:  append([]string{}, headingPath...),
+					TableCaption: lastNonBlank,
+				},
+			})
+			continue
+		}
+
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			lastNonBlank = trimmed
+		}
+		paragraph = append(paragraph, line)
+	}
+
+	flushParagraph()
+	return chunks
+}
+
+// splitCode breaks on function/class boundaries recognized by a small set
+// of common keywords (func, def, class). This is a lightweight heuristic
+// rather than a real tree-sitter grammar walk, but it keeps each chunk
+// aligned to a single declaration without any cgo dependency.
+func (s *StructuralSplitter) splitCode(text string) []StructuralChunk {
+	lines := strings.Split(text, "\n")
+	var chunks []StructuralChunk
+	var current []string
+	currentFunc := ""
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		joined := strings.TrimRight(strings.Join(current, "\n"), "\n")
+		if strings.TrimSpace(joined) == "" {
+			current = nil
+			return
+		}
+		chunks = append(chunks, StructuralChunk{
+			Text:       joined,
+			Role:       "code_snippet",
+			Breadcrumb: SourceLocation{FunctionName: currentFunc},
+		})
+		current = nil
+	}
+
+	for _, line := range lines {
+		if m := codeBoundaryRegex.FindStringSubmatch(line); m != nil {
+			flush()
+			currentFunc = m[2]
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	if len(chunks) == 0 {
+		return s.splitProse(text)
+	}
+	return chunks
+}
+
+// splitProse is the original paragraph/sentence-based splitter, used as the
+// fallback for content types that aren't markdown or recognized source.
+func (s *StructuralSplitter) splitProse(text string) []StructuralChunk {
+	var chunks []StructuralChunk
+	for _, sub := range s.splitProseText(text) {
+		chunks = append(chunks, StructuralChunk{Text: sub, Role: "paragraph"})
+	}
+	return chunks
+}
+
+func (s *StructuralSplitter) splitProseText(text string) []string {
+	paragraphs := regexp.MustCompile(`\n\s*\n`).Split(text, -1)
+
+	var chunks []string
+	var currentChunk strings.Builder
+	currentTokens := 0
+
+	for _, paragraph := range paragraphs {
+		trimmed := strings.TrimSpace(paragraph)
+		if trimmed == "" {
+			continue
+		}
+
+		// Split after sentence-ending punctuation followed by whitespace; RE2
+		// has no lookbehind, so the punctuation is kept by slicing.
+		var sentences []string
+		last := 0
+		for _, loc := range regexp.MustCompile(`[.!?]\s+`).FindAllStringIndex(trimmed, -1) {
+			sentences = append(sentences, trimmed[last:loc[0]+1])
+			last = loc[1]
+		}
+		sentences = append(sentences, trimmed[last:])
+		for _, sentence := range sentences {
+			sentence = strings.TrimSpace(sentence)
+			if sentence == "" {
+				continue
+			}
+
+			sentenceTokens := s.estimateTokenCount(sentence)
+			if currentTokens+sentenceTokens > s.maxChunkSize && currentChunk.Len() > 0 {
+				if currentTokens >= s.minChunkSize {
+					chunks = append(chunks, strings.TrimSpace(currentChunk.String()))
+				}
+				currentChunk.Reset()
+				currentTokens = 0
+			}
+
+			if currentChunk.Len() > 0 {
+				currentChunk.WriteString(" ")
+			}
+			currentChunk.WriteString(sentence)
+			currentTokens += sentenceTokens
+		}
+	}
+
+	if currentChunk.Len() > 0 && currentTokens >= s.minChunkSize {
+		chunks = append(chunks, strings.TrimSpace(currentChunk.String()))
+	}
+
+	return chunks
+}
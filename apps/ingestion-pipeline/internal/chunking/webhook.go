@@ -0,0 +1,286 @@
+package chunking
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/google/uuid"
+)
+
+// WebhookEndpointConfig configures one outbound webhook ChunkingStorageManager
+// fires after a document finishes chunking.
+type WebhookEndpointConfig struct {
+	URL string `json:"url"`
+	// Method is the HTTP method to send ("POST" or "PUT"); empty defaults
+	// to POST.
+	Method string `json:"method"`
+
+	// AuthMode selects how the request authenticates: "bearer" sends
+	// "Authorization: Bearer <AuthSecret>"; "header" sends
+	// AuthHeaderName: AuthSecret verbatim, which covers Splunk HEC's
+	// "Authorization: Splunk <token>" convention as well as arbitrary
+	// custom headers; "basic" sends HTTP Basic auth with AuthSecret in
+	// "user:password" form. Anything else sends no auth header.
+	AuthMode       string `json:"auth_mode"`
+	AuthHeaderName string `json:"auth_header_name"`
+	AuthSecret     string `json:"auth_secret"`
+
+	// PayloadTemplate selects what gets sent: "full_chunked_document" (the
+	// whole ChunkedDocument, the default), "chunk_summaries_only" (just the
+	// processing summary plus each chunk's role/topics/confidence, no chunk
+	// text), or "per_chunk" (one request per chunk, each carrying a single
+	// QdrantPoint).
+	PayloadTemplate string `json:"payload_template"`
+
+	// AtLeastOnce wraps delivery in exponential-backoff retries (see
+	// withWebhookRetry) instead of firing the request once and discarding
+	// any error.
+	AtLeastOnce bool `json:"at_least_once"`
+
+	// SigningSecret, if set, HMAC-SHA256-signs the request (over the raw
+	// body plus a timestamp and nonce) and sends the digest as
+	// X-Orbit-Signature, alongside X-Orbit-Timestamp/X-Orbit-Nonce so
+	// receivers can verify authenticity and reject replays.
+	SigningSecret string `json:"signing_secret"`
+}
+
+// WebhookNotifier fans a finished ChunkedDocument out to the endpoints
+// configured for its SourceType.
+type WebhookNotifier struct {
+	client    *http.Client
+	endpoints map[string][]WebhookEndpointConfig
+}
+
+func NewWebhookNotifier(endpoints map[string][]WebhookEndpointConfig) *WebhookNotifier {
+	return &WebhookNotifier{
+		client:    &http.Client{Timeout: 10 * time.Second},
+		endpoints: endpoints,
+	}
+}
+
+// Notify delivers chunkedDoc to every endpoint configured for sourceType.
+// Fire-and-forget endpoint failures are logged and otherwise ignored;
+// at-least-once endpoint failures (after exhausting retries) are returned
+// via firstErr so the caller can decide whether to dead-letter the
+// document, though delivery is still attempted to every other endpoint
+// first.
+func (wn *WebhookNotifier) Notify(sourceType string, chunkedDoc *ChunkedDocument) error {
+	endpoints := wn.endpoints[sourceType]
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	var firstErr error
+	for _, endpoint := range endpoints {
+		if err := wn.deliver(endpoint, chunkedDoc); err != nil {
+			fmt.Printf("webhook delivery to %s failed: %v\n", endpoint.URL, err)
+			if endpoint.AtLeastOnce && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+func (wn *WebhookNotifier) deliver(endpoint WebhookEndpointConfig, chunkedDoc *ChunkedDocument) error {
+	payloads, err := buildWebhookPayloads(endpoint.PayloadTemplate, chunkedDoc)
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, payload := range payloads {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to marshal webhook payload: %w", err)
+			}
+			continue
+		}
+
+		sendOnce := func() error { return wn.send(endpoint, body) }
+
+		var sendErr error
+		if endpoint.AtLeastOnce {
+			sendErr = withWebhookRetry(sendOnce)
+		} else {
+			sendErr = sendOnce()
+		}
+
+		if sendErr != nil && firstErr == nil {
+			firstErr = sendErr
+		}
+	}
+
+	return firstErr
+}
+
+func (wn *WebhookNotifier) send(endpoint WebhookEndpointConfig, body []byte) error {
+	method := endpoint.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequest(method, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	switch endpoint.AuthMode {
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+endpoint.AuthSecret)
+	case "header":
+		headerName := endpoint.AuthHeaderName
+		if headerName == "" {
+			headerName = "Authorization"
+		}
+		req.Header.Set(headerName, endpoint.AuthSecret)
+	case "basic":
+		user, pass, _ := strings.Cut(endpoint.AuthSecret, ":")
+		req.SetBasicAuth(user, pass)
+	}
+
+	if endpoint.SigningSecret != "" {
+		nonce := uuid.NewString()
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Orbit-Timestamp", timestamp)
+		req.Header.Set("X-Orbit-Nonce", nonce)
+		req.Header.Set("X-Orbit-Signature", signWebhookBody(endpoint.SigningSecret, timestamp, nonce, body))
+	}
+
+	resp, err := wn.client.Do(req)
+	if err != nil {
+		return &webhookDeliveryError{network: true, body: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return &webhookDeliveryError{statusCode: resp.StatusCode, body: string(respBody)}
+	}
+
+	return nil
+}
+
+// signWebhookBody computes the HMAC-SHA256 digest sent as
+// X-Orbit-Signature. Covering the timestamp and nonce alongside the body
+// means a captured request can't be replayed with a different nonce and
+// still pass verification.
+func signWebhookBody(secret, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte(nonce))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookDeliveryError classifies a failed delivery attempt as retryable
+// (a transient 5xx/429 response, or a network error reaching the
+// endpoint) or permanent (anything else, e.g. a 400/401 the endpoint will
+// never accept).
+type webhookDeliveryError struct {
+	statusCode int
+	body       string
+	network    bool
+}
+
+func (e *webhookDeliveryError) Error() string {
+	if e.network {
+		return fmt.Sprintf("network error delivering webhook: %s", e.body)
+	}
+	return fmt.Sprintf("webhook endpoint returned status %d: %s", e.statusCode, e.body)
+}
+
+func (e *webhookDeliveryError) isRetryable() bool {
+	return e.network || e.statusCode == http.StatusTooManyRequests || e.statusCode >= 500
+}
+
+// withWebhookRetry runs fn with exponential backoff and jitter (100ms
+// initial interval, 30s cap, at most 5 retries), stopping early on a
+// non-retryable webhookDeliveryError. Unlike the Qdrant client's fixed
+// doubling schedule (1s, 2s, 4s, ...), its intervals are randomized.
+func withWebhookRetry(fn func() error) error {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = 100 * time.Millisecond
+	b.MaxInterval = 30 * time.Second
+	policy := backoff.WithMaxRetries(b, 5)
+
+	op := func() error {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if delErr, ok := err.(*webhookDeliveryError); ok && !delErr.isRetryable() {
+			return backoff.Permanent(err)
+		}
+		return err
+	}
+
+	return backoff.Retry(op, policy)
+}
+
+// chunkSummary is one chunk's entry in the "chunk_summaries_only" payload
+// template: its analysis, without the chunk text itself.
+type chunkSummary struct {
+	ChunkID         string   `json:"chunk_id"`
+	ChunkRole       string   `json:"chunk_role"`
+	Topics          []string `json:"topics"`
+	AgentConfidence float64  `json:"agent_confidence"`
+}
+
+// chunkSummariesPayload is the "chunk_summaries_only" template's body.
+type chunkSummariesPayload struct {
+	DocumentID        string            `json:"document_id"`
+	DocumentTitle     string            `json:"document_title"`
+	ProcessingSummary ProcessingSummary `json:"processing_summary"`
+	Chunks            []chunkSummary    `json:"chunks"`
+}
+
+// buildWebhookPayloads renders chunkedDoc per template, returning one
+// payload per outbound request: a single element for
+// "full_chunked_document"/"chunk_summaries_only", or one per chunk for
+// "per_chunk".
+func buildWebhookPayloads(template string, chunkedDoc *ChunkedDocument) ([]interface{}, error) {
+	switch template {
+	case "", "full_chunked_document":
+		return []interface{}{chunkedDoc}, nil
+
+	case "chunk_summaries_only":
+		summaries := make([]chunkSummary, len(chunkedDoc.Chunks))
+		for i, chunk := range chunkedDoc.Chunks {
+			summaries[i] = chunkSummary{
+				ChunkID:         chunk.Payload.ProcessingMetadata.ChunkID,
+				ChunkRole:       chunk.Payload.Analysis.ChunkRole,
+				Topics:          chunk.Payload.Analysis.Topics,
+				AgentConfidence: chunk.Payload.Analysis.AgentConfidence,
+			}
+		}
+		return []interface{}{chunkSummariesPayload{
+			DocumentID:        chunkedDoc.OriginalDocument.Metadata.ID,
+			DocumentTitle:     chunkedDoc.OriginalDocument.Metadata.Title,
+			ProcessingSummary: chunkedDoc.ProcessingSummary,
+			Chunks:            summaries,
+		}}, nil
+
+	case "per_chunk":
+		payloads := make([]interface{}, len(chunkedDoc.Chunks))
+		for i, chunk := range chunkedDoc.Chunks {
+			payloads[i] = chunk
+		}
+		return payloads, nil
+
+	default:
+		return nil, fmt.Errorf("unknown webhook payload template %q", template)
+	}
+}
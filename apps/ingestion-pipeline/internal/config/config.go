@@ -42,6 +42,39 @@ type Config struct {
 	Storage    StorageConfig    `json:"storage"`
 	Processing ProcessingConfig `json:"processing"`
 	Chunking   ChunkingConfig   `json:"chunking"`
+	Text       TextConfig       `json:"text"`
+}
+
+// TextConfig configures internal/text's Cleaner. Pipeline names the
+// text.CleanStep sequence to run, in order, e.g. ["html_strip", "nfkc",
+// "dehyphenate", "collapse_ws"]; an empty Pipeline falls back to
+// text.NewCleaner's built-in default sequence.
+type TextConfig struct {
+	Pipeline  []string        `json:"pipeline"`
+	Redaction RedactionConfig `json:"redaction"`
+}
+
+// RedactionConfig drives text.NewRedactor for the "redact_pii" step.
+// Enabled gates whether processor.New builds and registers that step at
+// all; a config can then opt a pipeline into it by naming "redact_pii"
+// in TextConfig.Pipeline alongside the other steps it wants.
+type RedactionConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// Tokens overrides the replacement token for one of the built-in span
+	// types ("email", "phone", "credit_card", "iban", "ipv4", "ipv6") or a
+	// Patterns entry below.
+	Tokens map[string]string `json:"tokens"`
+
+	// Patterns adds a user-defined detector per entry: key is the span
+	// type name (used in Tokens and the sidecar map), value is the regex
+	// to match.
+	Patterns map[string]string `json:"patterns"`
+
+	// EmitSidecar, if true, makes each redacted occurrence a uniquely
+	// numbered placeholder (e.g. "[EMAIL_1]") instead of a flat token, so
+	// an authorized downstream reader can rehydrate the original value.
+	EmitSidecar bool `json:"emit_sidecar"`
 }
 
 type TikaConfig struct {
@@ -49,6 +82,26 @@ type TikaConfig struct {
 	Timeout       Duration `json:"timeout"`
 	RetryAttempts int      `json:"retry_attempts"`
 	RetryDelay    Duration `json:"retry_delay"`
+
+	// Recursive switches extraction to Tika's /rmeta/text endpoint, which
+	// unpacks embedded resources (email attachments, images inside PDFs,
+	// archive entries) as their own metadata blocks. MaxEmbeddedDepth bounds
+	// how many levels of nesting are kept in the returned tree; it is not
+	// sent to Tika, which still unpacks every level of the input.
+	Recursive        bool `json:"recursive"`
+	MaxEmbeddedDepth int  `json:"max_embedded_depth"`
+
+	// JSONPointers overrides the auto-detected split points extractJSONContent
+	// uses to carve a JSON document into per-JSONPath chunks (e.g.
+	// "$.items[*]", "$.messages[*].content"), for known document shapes
+	// (OpenAPI specs, JSONL logs, chat transcripts) where auto-detection
+	// picks the wrong arrays. Leave empty to auto-detect leaf arrays.
+	JSONPointers []string `json:"json_pointers"`
+
+	// JSONSchemaMaxEnumCandidates bounds how many distinct string values a
+	// field can have before extractJSONContent's schema inference gives up
+	// on representing it as an enum and falls back to a bare "type": "string".
+	JSONSchemaMaxEnumCandidates int `json:"json_schema_max_enum_candidates"`
 }
 
 type StorageConfig struct {
@@ -56,6 +109,37 @@ type StorageConfig struct {
 	TempDir        string `json:"temp_dir"`
 	KeepOriginals  bool   `json:"keep_originals"`
 	CompressOutput bool   `json:"compress_output"`
+
+	// Backend selects the storage.Store implementation storage.Manager
+	// routes Save/SaveBatch/Load through. "" or "json_gzip" keeps the
+	// original per-document JSON(.gz) writer; "ndjson_zstd" appends to a
+	// Zstandard-compressed newline-delimited JSON log with a sidecar index;
+	// "parquet" writes columnar Parquet files for analytics tools (DuckDB,
+	// Spark) to query directly.
+	Backend string `json:"backend"`
+
+	NDJSONZstd NDJSONZstdConfig `json:"ndjson_zstd"`
+	Parquet    ParquetConfig    `json:"parquet"`
+}
+
+type NDJSONZstdConfig struct {
+	// Level is the zstd compression level (1-22). Zero uses the zstd
+	// package's default speed/ratio tradeoff.
+	Level int `json:"level"`
+
+	// RotationSize rotates to a new segment once the current one reaches
+	// this many uncompressed bytes. Zero disables size-based rotation.
+	RotationSize int64 `json:"rotation_size_bytes"`
+
+	// RotationInterval rotates to a new segment after this long, in
+	// addition to RotationSize. Zero disables time-based rotation.
+	RotationInterval Duration `json:"rotation_interval"`
+}
+
+type ParquetConfig struct {
+	// RowGroupSize bounds how many bytes accumulate in memory before the
+	// Parquet writer flushes a row group.
+	RowGroupSize int `json:"row_group_size"`
 }
 
 type ChunkingConfig struct {
@@ -65,6 +149,53 @@ type ChunkingConfig struct {
 	MaxTokens    int     `json:"max_tokens"`
 	Temperature  float64 `json:"temperature"`
 	SectionSize  int     `json:"sectionSize"`
+
+	// Security configures how the chunking Kafka producer authenticates to
+	// the cluster. Its zero value is plaintext, unauthenticated Kafka.
+	Security KafkaSecurityConfig `json:"security"`
+
+	// SchemaRegistry configures Confluent Schema Registry integration for
+	// outgoing chunk messages. Its zero value (URL unset) leaves chunk
+	// messages as plain, schema-less JSON.
+	SchemaRegistry SchemaRegistryConfig `json:"schema_registry"`
+
+	// Transactional enables exactly-once section streaming: each chunking
+	// worker gets its own transactional Kafka producer and commits (or
+	// aborts) one transaction per section.
+	Transactional bool `json:"transactional"`
+}
+
+type SchemaRegistryConfig struct {
+	URL             string `json:"url"`
+	Format          string `json:"format"`
+	SubjectStrategy string `json:"subject_strategy"`
+	Compatibility   string `json:"compatibility"`
+}
+
+type KafkaSecurityConfig struct {
+	Protocol      string `json:"protocol"`
+	SASLMechanism string `json:"sasl_mechanism"`
+	SASLUsername  string `json:"sasl_username"`
+	SASLPassword  string `json:"sasl_password"`
+
+	TLS   TLSConfig   `json:"tls"`
+	OAuth OAuthConfig `json:"oauth"`
+}
+
+type TLSConfig struct {
+	CAFile             string `json:"ca_file"`
+	CertFile           string `json:"cert_file"`
+	KeyFile            string `json:"key_file"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+}
+
+// OAuthConfig drives client-credentials token acquisition for a
+// SASL/OAUTHBEARER connection.
+type OAuthConfig struct {
+	TokenURL     string   `json:"token_url"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Scopes       []string `json:"scopes"`
 }
 
 type ProcessingConfig struct {
@@ -73,6 +204,25 @@ type ProcessingConfig struct {
 	BatchSize        int      `json:"batch_size"`
 	MaxConcurrency   int      `json:"max_concurrency"`
 	EnableTextClean  bool     `json:"enable_text_cleaning"`
+
+	// IdempotencyEnabled turns on the Idempotency-Key header check and
+	// SHA-256 content-hash dedup on /receive and /receive/uploads, backed by
+	// a bbolt KV at IdempotencyDBPath. Cached responses expire after
+	// IdempotencyTTL (zero keeps them indefinitely).
+	IdempotencyEnabled bool     `json:"idempotency_enabled"`
+	IdempotencyDBPath  string   `json:"idempotency_db_path"`
+	IdempotencyTTL     Duration `json:"idempotency_ttl"`
+
+	// MaxConcurrentUploads caps how many resumable upload sessions
+	// (uploads.Manager) can be open at once, so a burst of
+	// POST /receive/uploads can't open unbounded temp files. Zero means no
+	// cap.
+	MaxConcurrentUploads int `json:"max_concurrent_uploads"`
+
+	// UploadSessionTTL bounds how long a resumable upload session can sit
+	// without a PATCH before uploads.Reaper closes its temp file and evicts
+	// it. Zero disables reaping.
+	UploadSessionTTL Duration `json:"upload_session_ttl"`
 }
 
 func Load(configPath string) (*Config, error) {
@@ -86,6 +236,10 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to load config from file: %w", err)
 	}
 
+	if cfg.Tika.JSONSchemaMaxEnumCandidates == 0 {
+		cfg.Tika.JSONSchemaMaxEnumCandidates = 20
+	}
+
 	if err := cfg.validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -116,7 +270,10 @@ func (c *Config) validate() error {
 	if c.Processing.MaxConcurrency <= 0 {
 		return fmt.Errorf("max concurrency must be positive")
 	}
-	
+	if c.Tika.Recursive && c.Tika.MaxEmbeddedDepth <= 0 {
+		return fmt.Errorf("tika max embedded depth must be positive when recursive extraction is enabled")
+	}
+
 	if c.Chunking.Enabled {
 		if c.Chunking.GeminiAPIKey == "" {
 			return fmt.Errorf("gemini API key is required when chunking is enabled")
@@ -142,4 +299,4 @@ func (c *Config) Save(path string) error {
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(c)
-}
\ No newline at end of file
+}
@@ -0,0 +1,95 @@
+// Package idempotency provides a small bbolt-backed cache of previously
+// computed processing responses, keyed by either a client-supplied
+// Idempotency-Key header or a "sha256:<hex>" content hash, so repeated
+// submissions of the same request or the same document short-circuit to the
+// prior result instead of reprocessing.
+package idempotency
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+const bucketName = "idempotent_responses"
+
+// Store is a bbolt-backed KV of cached responses. Entries older than the
+// configured TTL are treated as misses and overwritten on the next Put; a
+// zero TTL keeps entries indefinitely.
+type Store struct {
+	db  *bbolt.DB
+	ttl time.Duration
+}
+
+type cachedEntry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Response json.RawMessage `json:"response"`
+}
+
+// NewStore opens (creating if necessary) a bbolt database at path and
+// ensures its response bucket exists.
+func NewStore(path string, ttl time.Duration) (*Store, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open idempotency store %q: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(bucketName))
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize idempotency bucket: %w", err)
+	}
+
+	return &Store{db: db, ttl: ttl}, nil
+}
+
+// Get returns the cached response for key, if present and not expired.
+func (s *Store) Get(key string) (json.RawMessage, bool, error) {
+	var entry *cachedEntry
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(bucketName)).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+
+		var e cachedEntry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return fmt.Errorf("failed to decode cached entry for %q: %w", key, err)
+		}
+		entry = &e
+		return nil
+	})
+	if err != nil || entry == nil {
+		return nil, false, err
+	}
+
+	if s.ttl > 0 && time.Since(entry.StoredAt) > s.ttl {
+		return nil, false, nil
+	}
+
+	return entry.Response, true, nil
+}
+
+// Put stores response under key, stamped with the current time for TTL
+// expiry.
+func (s *Store) Put(key string, response json.RawMessage) error {
+	data, err := json.Marshal(cachedEntry{StoredAt: time.Now(), Response: response})
+	if err != nil {
+		return fmt.Errorf("failed to encode cached entry for %q: %w", key, err)
+	}
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(bucketName)).Put([]byte(key), data)
+	})
+}
+
+// Close releases the underlying bbolt database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -10,7 +10,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/NEMYSESx/orbit/apps/ingestion-pipeline/internal/models"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
 )
 
 type Builder struct{}
@@ -6,12 +6,21 @@ import (
 )
 
 type DocumentMetadata struct {
+	// ID uniquely identifies this document within the pipeline. It is
+	// derived from the source file's path and content checksum (see
+	// metadata.Builder.generateID) so the same file reprocessed without
+	// changes yields the same ID.
+	ID string `json:"id"`
+	// SourceID identifies the originating document a derived piece of
+	// content (a chunk, a chunked summary) was produced from. For a
+	// top-level document it is equal to ID.
+	SourceID         string                 `json:"source_id"`
 	Title            string                 `json:"title"`
 	Filepath         string                 `json:"filepath"`
 	FileSize         int64                  `json:"file_size"`
 	Author           string                 `json:"author,omitempty"`
 	CreationDate     *time.Time             `json:"creation_date,omitempty"`
-	LastModifiedDate string                 `json:"last_modified_date,omitempty"`
+	LastModifiedDate time.Time              `json:"last_modified_date,omitempty"`
 	Language         string                 `json:"language,omitempty"`
 	ContentType      string                 `json:"content_type"`
 	SourceType       string                 `json:"source_type"`
@@ -21,10 +30,21 @@ type DocumentMetadata struct {
 }
 
 type ExtractedContent struct {
-	Metadata  DocumentMetadata `json:"metadata"`
-	CleanText string           `json:"clean_text"`
-	WordCount int              `json:"word_count"`
-	PageCount int              `json:"page_count,omitempty"`
+	Metadata DocumentMetadata `json:"metadata"`
+	// RawText is a second copy of a document's text alongside CleanText,
+	// set only by chunking.ChunkingStorageManager: SaveChunksAsIndividualFiles
+	// stores each chunk's text here, and SaveChunkedDocument copies the
+	// original document's RawText. tika.Client leaves it empty.
+	RawText   string `json:"raw_text,omitempty"`
+	CleanText string `json:"clean_text"`
+	WordCount int    `json:"word_count"`
+	PageCount int    `json:"page_count,omitempty"`
+
+	// Children holds one ExtractedContent per embedded resource (an email
+	// attachment, an image inside a PDF, an archive entry) found during
+	// recursive Tika extraction, or one per JSONPath chunk for a JSON
+	// source document (each tagged with "json_path" in ExtraMetadata).
+	Children []*ExtractedContent `json:"children,omitempty"`
 }
 
 type TikaResponse struct {
@@ -71,9 +91,22 @@ type Chunk struct {
 }
 
 type ChunkOutput struct {
-	Text          string        `json:"text"`
+	Text          string        `json:"text,omitempty"`
 	Source        SourceInfo    `json:"source"`
 	ChunkMetadata ChunkMetadata `json:"chunk_metadata"`
+
+	// PayloadRef is set instead of Text when KafkaAgenticChunker spills the
+	// chunk body to object storage because it was too large to inline in
+	// the Kafka message. The consumer must resolve it before embedding.
+	PayloadRef *PayloadRef `json:"payload_ref,omitempty"`
+}
+
+// PayloadRef points at a chunk body stored in an S3-compatible bucket,
+// keyed by sha256(text)+chunk_id.
+type PayloadRef struct {
+	PayloadURI string `json:"payload_uri"`
+	Size       int64  `json:"size"`
+	ETag       string `json:"etag"`
 }
 
 type SourceInfo struct {
@@ -91,6 +124,117 @@ type ChunkingConfig struct {
 	MaxConcurrency int
 	RateLimitRPS   int
 	RequestTimeout time.Duration
+
+	// ObjectStore configures where KafkaAgenticChunker spills oversized chunk
+	// bodies instead of inlining them in the Kafka message.
+	ObjectStore ObjectStoreConfig
+
+	// Security configures how KafkaAgenticChunker's Kafka producer authenticates
+	// to the cluster. Its zero value is plaintext, unauthenticated Kafka.
+	Security KafkaSecurityConfig
+
+	// Provider selects the LLMBackend KafkaAgenticChunker's chunking prompt runs
+	// against: "gemini" (the default, falls back to GeminiAPIKey/GeminiModel
+	// above when unset), "openai", "anthropic", or "ollama". Endpoint
+	// overrides the provider's default API URL, needed for Ollama (which has
+	// no public default) and useful for OpenAI/Anthropic-compatible proxies.
+	// ExtraHeaders are set on every backend request, for proxies that require
+	// their own auth headers alongside (or instead of) APIKey.
+	Provider     string
+	Model        string
+	Endpoint     string
+	APIKey       string
+	ExtraHeaders map[string]string
+
+	// DeadLetterTopic/RetryTopic override KafkaAgenticChunker's default
+	// "<kafka topic>.dlq"/".retry" names for sections that exhaust their
+	// per-section retries. Leave unset to use the default.
+	DeadLetterTopic string
+	RetryTopic      string
+
+	// SchemaRegistry configures Confluent Schema Registry integration for
+	// outgoing chunk messages. Its zero value (URL unset) leaves
+	// streamChunkToKafka on plain, schema-less json.Marshal.
+	SchemaRegistry SchemaRegistryConfig
+
+	// Transactional enables exactly-once section streaming: each worker gets
+	// its own transactional Kafka producer, with a transactional.id derived
+	// from the host's hostname and the worker's index, and begins/commits (or
+	// aborts) one transaction per section so a section's chunks land in Kafka
+	// atomically. Idempotent production (enable.idempotence,
+	// max.in.flight.requests.per.connection<=5) is always on regardless of
+	// this flag.
+	Transactional bool
+}
+
+// SchemaRegistryConfig drives KafkaAgenticChunker's Serializer: which wire
+// format to register and produce, how to name the subject, and what
+// compatibility mode to enforce on it.
+type SchemaRegistryConfig struct {
+	URL string
+
+	// Format selects the Serializer: "json" (the default), "avro", or
+	// "protobuf".
+	Format string
+
+	// SubjectStrategy selects the Schema Registry subject naming strategy:
+	// "TopicName" (the default), "RecordName", or "TopicRecordName".
+	SubjectStrategy string
+
+	// Compatibility sets the subject's compatibility mode (e.g. "BACKWARD",
+	// "FORWARD", "FULL", "NONE") on startup. Left unset, the registry's
+	// existing subject-level (or global) config is untouched.
+	Compatibility string
+}
+
+// KafkaSecurityConfig configures KafkaAgenticChunker's Kafka producer for a
+// managed cluster (MSK, Confluent Cloud) instead of local, unauthenticated
+// Kafka: Protocol selects "SASL_SSL"/"SASL_PLAINTEXT", SASLMechanism selects
+// "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512", or "OAUTHBEARER".
+type KafkaSecurityConfig struct {
+	Protocol      string
+	SASLMechanism string
+
+	// SASLUsername/SASLPassword authenticate PLAIN and SCRAM-SHA-256/512.
+	SASLUsername string
+	SASLPassword string
+
+	TLS   TLSConfig
+	OAuth OAuthConfig
+}
+
+type TLSConfig struct {
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	InsecureSkipVerify bool
+}
+
+// OAuthConfig drives client-credentials token acquisition for
+// SASL/OAUTHBEARER.
+type OAuthConfig struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// ObjectStoreConfig configures the MinIO/S3-compatible bucket KafkaAgenticChunker
+// spills oversized chunk bodies to, keeping Kafka messages under broker
+// message.max.bytes limits.
+type ObjectStoreConfig struct {
+	Endpoint        string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UseSSL          bool
+
+	// Strategy selects when a chunk's Text is externalized: "inline_only"
+	// (never, the default when Endpoint is unset), "always_external"
+	// (always), or "" / "spillover" (only when Text exceeds
+	// ThresholdBytes, the default once an object store is configured).
+	Strategy       string
+	ThresholdBytes int
 }
 
 type ChunkerResult struct {
@@ -148,7 +292,7 @@ type AgenticChunker interface {
 
 func (cr *ChunkerResult) ToQdrantPayload() []map[string]interface{} {
 	payload := make([]map[string]interface{}, len(cr.Chunks))
-	
+
 	for i, chunk := range cr.Chunks {
 		payload[i] = map[string]interface{}{
 			"id": chunk.ID,
@@ -167,12 +311,12 @@ func (cr *ChunkerResult) ToQdrantPayload() []map[string]interface{} {
 				"timestamp":   chunk.Metadata.Timestamp,
 			},
 		}
-		
+
 		if len(chunk.Vector) > 0 {
 			payload[i]["vector"] = chunk.Vector
 		}
 	}
-	
+
 	return payload
 }
 
@@ -188,7 +332,7 @@ func (cr *ChunkerResult) FilterChunksByCategory(category string) []Chunk {
 
 func (cr *ChunkerResult) GetChunksByKeyword(keyword string) []Chunk {
 	var matches []Chunk
-	
+
 	for _, chunk := range cr.Chunks {
 		for _, kw := range chunk.Metadata.Keywords {
 			if kw == keyword {
@@ -197,7 +341,7 @@ func (cr *ChunkerResult) GetChunksByKeyword(keyword string) []Chunk {
 			}
 		}
 	}
-	
+
 	return matches
 }
 
@@ -207,7 +351,7 @@ func (cr *ChunkerResult) GetStatistics() map[string]interface{} {
 	sentiments := make(map[string]int)
 	complexities := make(map[string]int)
 	languages := make(map[string]int)
-	
+
 	for _, chunk := range cr.Chunks {
 		totalWords += chunk.Metadata.WordCount
 		categories[chunk.Metadata.Category]++
@@ -215,20 +359,20 @@ func (cr *ChunkerResult) GetStatistics() map[string]interface{} {
 		complexities[chunk.Metadata.Complexity]++
 		languages[chunk.Metadata.Language]++
 	}
-	
+
 	avgWords := 0.0
 	if cr.TotalCount > 0 {
 		avgWords = float64(totalWords) / float64(cr.TotalCount)
 	}
-	
+
 	return map[string]interface{}{
-		"total_chunks":              cr.TotalCount,
-		"total_words":               totalWords,
-		"average_words_per_chunk":   avgWords,
-		"categories":                categories,
-		"sentiments":                sentiments,
-		"complexities":              complexities,
-		"languages":                 languages,
-		"processed_at":              cr.ProcessedAt,
+		"total_chunks":            cr.TotalCount,
+		"total_words":             totalWords,
+		"average_words_per_chunk": avgWords,
+		"categories":              categories,
+		"sentiments":              sentiments,
+		"complexities":            complexities,
+		"languages":               languages,
+		"processed_at":            cr.ProcessedAt,
 	}
-}
\ No newline at end of file
+}
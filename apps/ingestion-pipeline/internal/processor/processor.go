@@ -9,6 +9,7 @@ import (
 	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/chunking"
 	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/config"
 	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/storage"
 	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/text"
 	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/tika"
 	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/validator"
@@ -20,8 +21,9 @@ type DocumentProcessor struct {
 	tikaClient  *tika.Client
 	textCleaner *text.Cleaner
 	validator   *validator.FileValidator
-	chunker     *chunking.AgenticChunker
+	chunker     *chunking.KafkaAgenticChunker
 	producer    *kafka.Producer
+	storage     *storage.Manager
 }
 
 func New(cfg *config.Config) (*DocumentProcessor, error) {
@@ -32,11 +34,36 @@ func New(cfg *config.Config) (*DocumentProcessor, error) {
 		MaxConcurrency: 5,
 		RateLimitRPS:   10,
 		RequestTimeout: time.Second * 30,
-	}
-
-	chunker := chunking.NewAgenticChunker(chunkingConfig)
-	
-	if err := chunker.InitializeKafkaStreaming("kafka:29092", "documents"); err != nil {
+		Security: models.KafkaSecurityConfig{
+			Protocol:      cfg.Chunking.Security.Protocol,
+			SASLMechanism: cfg.Chunking.Security.SASLMechanism,
+			SASLUsername:  cfg.Chunking.Security.SASLUsername,
+			SASLPassword:  cfg.Chunking.Security.SASLPassword,
+			TLS: models.TLSConfig{
+				CAFile:             cfg.Chunking.Security.TLS.CAFile,
+				CertFile:           cfg.Chunking.Security.TLS.CertFile,
+				KeyFile:            cfg.Chunking.Security.TLS.KeyFile,
+				InsecureSkipVerify: cfg.Chunking.Security.TLS.InsecureSkipVerify,
+			},
+			OAuth: models.OAuthConfig{
+				TokenURL:     cfg.Chunking.Security.OAuth.TokenURL,
+				ClientID:     cfg.Chunking.Security.OAuth.ClientID,
+				ClientSecret: cfg.Chunking.Security.OAuth.ClientSecret,
+				Scopes:       cfg.Chunking.Security.OAuth.Scopes,
+			},
+		},
+		SchemaRegistry: models.SchemaRegistryConfig{
+			URL:             cfg.Chunking.SchemaRegistry.URL,
+			Format:          cfg.Chunking.SchemaRegistry.Format,
+			SubjectStrategy: cfg.Chunking.SchemaRegistry.SubjectStrategy,
+			Compatibility:   cfg.Chunking.SchemaRegistry.Compatibility,
+		},
+		Transactional: cfg.Chunking.Transactional,
+	}
+
+	chunker := chunking.NewKafkaAgenticChunker(chunkingConfig)
+
+	if err := chunker.InitializeKafkaStreaming("kafka:29092", "documents", chunkingConfig.Security); err != nil {
 		return nil, fmt.Errorf("failed to initialize Kafka streaming: %w", err)
 	}
 
@@ -51,18 +78,48 @@ func New(cfg *config.Config) (*DocumentProcessor, error) {
 		return nil, fmt.Errorf("failed to create main Kafka producer: %w", err)
 	}
 
+	storageManager, err := storage.NewManager(&cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage manager: %w", err)
+	}
+
+	if cfg.Text.Redaction.Enabled {
+		redactor, err := text.NewRedactor(text.RedactorConfig{
+			Tokens:      cfg.Text.Redaction.Tokens,
+			Patterns:    cfg.Text.Redaction.Patterns,
+			EmitSidecar: cfg.Text.Redaction.EmitSidecar,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build PII redactor: %w", err)
+		}
+		text.RegisterRedactStep(redactor)
+	}
+
+	textCleaner, err := text.NewCleaner(cfg.Processing.EnableTextClean, cfg.Text.Pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build text cleaning pipeline: %w", err)
+	}
+
 	dp := &DocumentProcessor{
 		config:      cfg,
 		tikaClient:  tika.NewClient(&cfg.Tika),
-		textCleaner: text.NewCleaner(cfg.Processing.EnableTextClean),
+		textCleaner: textCleaner,
 		validator:   validator.NewFileValidator(&cfg.Processing),
 		chunker:     chunker,
 		producer:    producer,
+		storage:     storageManager,
 	}
 
 	return dp, nil
 }
 
+// StorageManager exposes the processor's storage.Manager so HTTP handlers
+// (resumable uploads, in particular) can stream upload chunks into its temp
+// directory (StorageConfig.TempDir) through CreateTempFile.
+func (dp *DocumentProcessor) StorageManager() *storage.Manager {
+	return dp.storage
+}
+
 func (dp *DocumentProcessor) ProcessDocument(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*models.ProcessResult, error) {
 	fmt.Printf("Starting processing for file: %s\n", header.Filename)
 
@@ -99,7 +156,7 @@ func (dp *DocumentProcessor) ProcessDocument(ctx context.Context, file multipart
 		sourceInfo := models.SourceInfo{
 			DocumentTitle: extracted.Metadata.Title,
 			DocumentType:  extracted.Metadata.ContentType,
-			LastModified:  extracted.Metadata.LastModifiedDate,
+			LastModified:  extracted.Metadata.LastModifiedDate.Format(time.RFC3339),
 		}
 
 		err := dp.chunker.ChunkTextStreaming(ctx, cleanText, sourceInfo)
@@ -167,6 +224,18 @@ func (dp *DocumentProcessor) GetChunkingStatistics(result *models.ProcessResult)
 	}
 }
 
+// ChunkerStatus reports the chunker's section-processing counters
+// (processed, retried, dead-lettered), for a status endpoint to expose.
+func (dp *DocumentProcessor) ChunkerStatus() chunking.ChunkerStatus {
+	return dp.chunker.Status()
+}
+
+// StartChunkerAdminServer starts the chunker's Prometheus/pprof admin server
+// on addr. It runs until the chunker is closed.
+func (dp *DocumentProcessor) StartChunkerAdminServer(addr string) error {
+	return dp.chunker.StartAdminServer(addr)
+}
+
 func (dp *DocumentProcessor) Close() {
 	if dp.chunker != nil {
 		dp.chunker.Close()
@@ -174,4 +243,7 @@ func (dp *DocumentProcessor) Close() {
 	if dp.producer != nil {
 		dp.producer.Close()
 	}
+	if dp.storage != nil {
+		dp.storage.Close()
+	}
 }
\ No newline at end of file
@@ -0,0 +1,297 @@
+package qdrant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// SparseVector is a term-index/weight pair list, serialized into Qdrant's
+// named sparse vector shape (`{"indices": [...], "values": [...]}`).
+type SparseVector struct {
+	Indices []uint32  `json:"indices"`
+	Values  []float32 `json:"values"`
+}
+
+// Point is a single upsertable record. Vectors holds dense named vectors
+// ("dense", ...) and Sparse holds named sparse vectors ("sparse", ...) so a
+// point can carry both in the same upsert against a hybrid collection.
+type Point struct {
+	ID      string                  `json:"id"`
+	Vectors map[string][]float64    `json:"vectors"`
+	Sparse  map[string]SparseVector `json:"sparse"`
+	Payload map[string]interface{}  `json:"payload"`
+}
+
+// Config controls collection bootstrap and upsert batching behaviour.
+// SparseVectorName enables a named sparse vector on the collection (e.g.
+// "sparse" for BM25/SPLADE weights) in addition to the dense one; leave it
+// empty for dense-only collections.
+type Config struct {
+	BaseURL          string
+	Collection       string
+	VectorSize       int
+	Distance         string
+	SparseVectorName string
+	BatchSize        int
+	MaxRetries       int
+	RequestTimeout   time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		BaseURL:        "http://localhost:6333",
+		Collection:     "document_chunks",
+		VectorSize:     768,
+		Distance:       "Cosine",
+		BatchSize:      64,
+		MaxRetries:     3,
+		RequestTimeout: 30 * time.Second,
+	}
+}
+
+// Client is a minimal Qdrant REST client covering collection bootstrap and
+// batched, retried upserts. It buffers points and flushes them in
+// config.BatchSize batches so callers can stream points as they're produced.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	pending    []Point
+}
+
+func NewClient(config Config) *Client {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 64
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 3
+	}
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.RequestTimeout},
+	}
+}
+
+// EnsureCollection creates the target collection if it doesn't already
+// exist, using the dense "dense" named vector with the configured size and
+// distance metric. It is a no-op if the collection is already present.
+func (c *Client) EnsureCollection(ctx context.Context) error {
+	exists, err := c.collectionExists(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check collection: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	body := map[string]interface{}{
+		"vectors": map[string]interface{}{
+			"dense": map[string]interface{}{
+				"size":     c.config.VectorSize,
+				"distance": c.config.Distance,
+			},
+		},
+	}
+
+	if c.config.SparseVectorName != "" {
+		body["sparse_vectors"] = map[string]interface{}{
+			c.config.SparseVectorName: map[string]interface{}{},
+		}
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal collection config: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s", c.config.BaseURL, c.config.Collection)
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("create collection failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+func (c *Client) collectionExists(ctx context.Context) (bool, error) {
+	url := fmt.Sprintf("%s/collections/%s", c.config.BaseURL, c.config.Collection)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("get collection failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return true, nil
+}
+
+// Upsert queues points for upsert and flushes immediately whenever the
+// buffer reaches config.BatchSize. Each point is keyed by its ID so
+// re-upserting the same ProcessingMetadata.ChunkID is idempotent.
+func (c *Client) Upsert(ctx context.Context, points ...Point) error {
+	c.pending = append(c.pending, points...)
+
+	for len(c.pending) >= c.config.BatchSize {
+		batch := c.pending[:c.config.BatchSize]
+		if err := c.upsertBatch(ctx, batch); err != nil {
+			return err
+		}
+		c.pending = c.pending[c.config.BatchSize:]
+	}
+	return nil
+}
+
+// Flush upserts any remaining buffered points.
+func (c *Client) Flush(ctx context.Context) error {
+	if len(c.pending) == 0 {
+		return nil
+	}
+	if err := c.upsertBatch(ctx, c.pending); err != nil {
+		return err
+	}
+	c.pending = nil
+	return nil
+}
+
+func (c *Client) upsertBatch(ctx context.Context, points []Point) error {
+	body := map[string]interface{}{
+		"points": toUpsertPoints(points),
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal upsert batch: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/points?wait=true", c.config.BaseURL, c.config.Collection)
+
+	var lastErr error
+	for attempt := 0; attempt < c.config.MaxRetries; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonData))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to make request: %w", err)
+			time.Sleep(time.Duration(1<<attempt) * time.Second)
+			continue
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			resp.Body.Close()
+			return nil
+		}
+
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		lastErr = fmt.Errorf("upsert failed with status %d: %s", resp.StatusCode, string(respBody))
+		log.Printf("qdrant upsert attempt %d/%d failed: %v", attempt+1, c.config.MaxRetries, lastErr)
+		time.Sleep(time.Duration(1<<attempt) * time.Second)
+	}
+
+	return fmt.Errorf("upsert batch failed after %d attempts: %w", c.config.MaxRetries, lastErr)
+}
+
+// Delete removes the points with the given IDs from the collection. Unlike
+// Upsert it is not buffered: an ID handed to Delete is sent immediately, so
+// a caller that just Compacted its own index doesn't have to separately
+// Flush to be sure the deletion took effect.
+func (c *Client) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	body := map[string]interface{}{
+		"points": ids,
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delete request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/points/delete?wait=true", c.config.BaseURL, c.config.Collection)
+
+	var lastErr error
+	for attempt := 0; attempt < c.config.MaxRetries; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to make request: %w", err)
+			time.Sleep(time.Duration(1<<attempt) * time.Second)
+			continue
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			resp.Body.Close()
+			return nil
+		}
+
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		lastErr = fmt.Errorf("delete failed with status %d: %s", resp.StatusCode, string(respBody))
+		log.Printf("qdrant delete attempt %d/%d failed: %v", attempt+1, c.config.MaxRetries, lastErr)
+		time.Sleep(time.Duration(1<<attempt) * time.Second)
+	}
+
+	return fmt.Errorf("delete failed after %d attempts: %w", c.config.MaxRetries, lastErr)
+}
+
+func toUpsertPoints(points []Point) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(points))
+	for i, p := range points {
+		vector := make(map[string]interface{}, len(p.Vectors)+len(p.Sparse))
+		for name, v := range p.Vectors {
+			vector[name] = v
+		}
+		for name, sv := range p.Sparse {
+			vector[name] = map[string]interface{}{
+				"indices": sv.Indices,
+				"values":  sv.Values,
+			}
+		}
+
+		out[i] = map[string]interface{}{
+			"id":      p.ID,
+			"vector":  vector,
+			"payload": p.Payload,
+		}
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/config"
+)
+
+// Compactor periodically merges small, sealed ndjson_zstd segments into a
+// larger Parquet file, so a long-running append log doesn't accumulate into
+// many tiny files that analytics tools would have to open one by one.
+type Compactor struct {
+	config   *config.StorageConfig
+	manager  *Manager
+	interval time.Duration
+	stop     chan struct{}
+}
+
+// NewCompactor builds a Compactor that runs every interval against
+// manager's ndjson segment directory, writing merged output through a new
+// parquet Store each pass. It's independent of manager's active backend,
+// so it keeps working even if Backend has since been switched to "parquet".
+func NewCompactor(manager *Manager, interval time.Duration) *Compactor {
+	return &Compactor{
+		config:   manager.config,
+		manager:  manager,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Run blocks, compacting on every tick until Stop is called.
+func (c *Compactor) Run() {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.compactOnce(); err != nil {
+				log.Printf("storage compaction failed: %v", err)
+			}
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// Stop ends the compaction loop after its current pass, if any, finishes.
+func (c *Compactor) Stop() {
+	close(c.stop)
+}
+
+func (c *Compactor) compactOnce() error {
+	segmentDir := filepath.Join(c.config.OutputDir, "ndjson")
+
+	entries, err := os.ReadDir(segmentDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list ndjson segments for compaction: %w", err)
+	}
+
+	var segments []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".ndjson.zst") {
+			segments = append(segments, strings.TrimSuffix(entry.Name(), ".ndjson.zst"))
+		}
+	}
+	sort.Strings(segments)
+
+	// Segment IDs are UnixNano-ordered, so the last one sorts newest.
+	// Leave it alone: it may still be the one an ndjsonZstdStore is
+	// actively appending to.
+	if len(segments) <= 1 {
+		return nil
+	}
+	sealed := segments[:len(segments)-1]
+
+	parquetOut, err := newParquetStore(c.config)
+	if err != nil {
+		return fmt.Errorf("failed to open parquet writer for compaction: %w", err)
+	}
+	defer parquetOut.Close()
+
+	reader := &ndjsonZstdStore{segmentDir: segmentDir}
+
+	merged := 0
+	for _, segmentID := range sealed {
+		records, err := reader.LoadAll(segmentID)
+		if err != nil {
+			log.Printf("storage compaction: skipping segment %s: %v", segmentID, err)
+			continue
+		}
+
+		for _, content := range records {
+			if _, err := parquetOut.Save(content); err != nil {
+				return fmt.Errorf("failed to write segment %s into parquet: %w", segmentID, err)
+			}
+		}
+		merged += len(records)
+
+		os.Remove(filepath.Join(segmentDir, segmentID+".ndjson.zst"))
+		os.Remove(filepath.Join(segmentDir, segmentID+".idx.json"))
+	}
+
+	log.Printf("storage compaction: merged %d records from %d ndjson segments into %s", merged, len(sealed), parquetOut.filePath)
+	return nil
+}
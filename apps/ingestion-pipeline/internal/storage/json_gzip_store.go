@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/config"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
+)
+
+// jsonGzipStore is the original per-document writer: one JSON file per
+// ExtractedContent, optionally gzip-compressed. It's the default backend
+// (config.StorageConfig.Backend == "" or "json_gzip").
+type jsonGzipStore struct {
+	config *config.StorageConfig
+}
+
+func newJSONGzipStore(cfg *config.StorageConfig) *jsonGzipStore {
+	return &jsonGzipStore{config: cfg}
+}
+
+func (s *jsonGzipStore) Save(content *models.ExtractedContent) (string, error) {
+	outputFilename := generateOutputFilename(content.Metadata)
+	outputPath := filepath.Join(s.config.OutputDir, outputFilename)
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if s.config.CompressOutput {
+		return s.saveCompressed(content, outputPath)
+	}
+
+	return s.saveUncompressed(content, outputPath)
+}
+
+func (s *jsonGzipStore) saveUncompressed(content *models.ExtractedContent, outputPath string) (string, error) {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(content); err != nil {
+		return "", fmt.Errorf("failed to encode content to JSON: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+func (s *jsonGzipStore) saveCompressed(content *models.ExtractedContent, outputPath string) (string, error) {
+	compressedPath := outputPath + ".gz"
+
+	file, err := os.Create(compressedPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create compressed output file: %w", err)
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	defer gzipWriter.Close()
+
+	encoder := json.NewEncoder(gzipWriter)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(content); err != nil {
+		return "", fmt.Errorf("failed to encode content to compressed JSON: %w", err)
+	}
+
+	return compressedPath, nil
+}
+
+func (s *jsonGzipStore) SaveBatch(contents []*models.ExtractedContent) ([]string, error) {
+	var outputPaths []string
+	var errors []error
+
+	for _, content := range contents {
+		outputPath, err := s.Save(content)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("failed to save document %s: %w",
+				content.Metadata.ID, err))
+			continue
+		}
+		outputPaths = append(outputPaths, outputPath)
+	}
+
+	if len(errors) > 0 {
+		var errorMsgs []string
+		for _, err := range errors {
+			errorMsgs = append(errorMsgs, err.Error())
+		}
+		return outputPaths, fmt.Errorf("batch save completed with %d errors: %s",
+			len(errors), strings.Join(errorMsgs, "; "))
+	}
+
+	return outputPaths, nil
+}
+
+func (s *jsonGzipStore) Load(filePath string) (*models.ExtractedContent, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	var content models.ExtractedContent
+
+	if strings.HasSuffix(filePath, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer gzipReader.Close()
+
+		decoder := json.NewDecoder(gzipReader)
+		if err := decoder.Decode(&content); err != nil {
+			return nil, fmt.Errorf("failed to decode compressed JSON: %w", err)
+		}
+	} else {
+		decoder := json.NewDecoder(file)
+		if err := decoder.Decode(&content); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+	}
+
+	return &content, nil
+}
+
+func (s *jsonGzipStore) Stats() (*StorageStats, error) {
+	stats := &StorageStats{
+		Backend:   "json_gzip",
+		OutputDir: s.config.OutputDir,
+		TempDir:   s.config.TempDir,
+	}
+
+	err := filepath.Walk(s.config.OutputDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			stats.TotalFiles++
+			stats.TotalSize += info.Size()
+
+			if strings.HasSuffix(path, ".json") {
+				stats.JsonFiles++
+			} else if strings.HasSuffix(path, ".json.gz") {
+				stats.CompressedFiles++
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate storage stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (s *jsonGzipStore) Close() error {
+	return nil
+}
+
+func generateOutputFilename(metadata models.DocumentMetadata) string {
+	timestamp := metadata.ProcessedAt.Format("20060102_150405")
+
+	safeID := sanitizeFilename(metadata.ID)
+
+	return fmt.Sprintf("%s_%s.json", safeID, timestamp)
+}
+
+func sanitizeFilename(filename string) string {
+	unsafe := []string{"/", "\\", ":", "*", "?", "\"", "<", ">", "|", " "}
+	safe := filename
+
+	for _, char := range unsafe {
+		safe = strings.ReplaceAll(safe, char, "_")
+	}
+
+	for strings.Contains(safe, "__") {
+		safe = strings.ReplaceAll(safe, "__", "_")
+	}
+
+	safe = strings.Trim(safe, "_")
+
+	if safe == "" {
+		safe = "document"
+	}
+
+	if len(safe) > 100 {
+		safe = safe[:100]
+	}
+
+	return safe
+}
@@ -1,170 +1,87 @@
 package storage
 
 import (
-	"compress/gzip"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/NEMYSESx/orbit/apps/ingestion-pipeline/internal/config"
-	"github.com/NEMYSESx/orbit/apps/ingestion-pipeline/internal/models"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/config"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
 )
 
+// Manager is the storage entry point callers use; it routes Save,
+// SaveBatch, Load, and GetStorageStats through whichever Store backend
+// config.StorageConfig.Backend selects, and keeps the filesystem-level
+// helpers (temp files, document listing) that apply regardless of backend.
 type Manager struct {
-	config *config.StorageConfig
+	config  *config.StorageConfig
+	backend Store
 }
 
-func NewManager(cfg *config.StorageConfig) *Manager {
-	return &Manager{
-		config: cfg,
-	}
-}
-
-func (sm *Manager) Save(content *models.ExtractedContent) (string, error) {
-	outputFilename := sm.generateOutputFilename(content.Metadata)
-	outputPath := filepath.Join(sm.config.OutputDir, outputFilename)
-
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
-		return "", fmt.Errorf("failed to create output directory: %w", err)
-	}
-
-	if sm.config.CompressOutput {
-		return sm.saveCompressed(content, outputPath)
-	}
-	
-	return sm.saveUncompressed(content, outputPath)
-}
-
-func (sm *Manager) saveUncompressed(content *models.ExtractedContent, outputPath string) (string, error) {
-	file, err := os.Create(outputPath)
+// NewManager builds a Manager with the Store backend named by cfg.Backend:
+// "" or "json_gzip" (default) for the original per-document JSON(.gz)
+// writer, "ndjson_zstd" for a compressed append log, or "parquet" for a
+// columnar writer.
+func NewManager(cfg *config.StorageConfig) (*Manager, error) {
+	backend, err := newBackend(cfg)
 	if err != nil {
-		return "", fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer file.Close()
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	
-	if err := encoder.Encode(content); err != nil {
-		return "", fmt.Errorf("failed to encode content to JSON: %w", err)
+		return nil, fmt.Errorf("failed to initialize storage backend %q: %w", cfg.Backend, err)
 	}
 
-	return outputPath, nil
+	return &Manager{
+		config:  cfg,
+		backend: backend,
+	}, nil
 }
 
-func (sm *Manager) saveCompressed(content *models.ExtractedContent, outputPath string) (string, error) {
-	compressedPath := outputPath + ".gz"
-	
-	file, err := os.Create(compressedPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to create compressed output file: %w", err)
+func newBackend(cfg *config.StorageConfig) (Store, error) {
+	switch cfg.Backend {
+	case "", "json_gzip":
+		return newJSONGzipStore(cfg), nil
+	case "ndjson_zstd":
+		return newNDJSONZstdStore(cfg)
+	case "parquet":
+		return newParquetStore(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
 	}
-	defer file.Close()
-
-	gzipWriter := gzip.NewWriter(file)
-	defer gzipWriter.Close()
-
-	encoder := json.NewEncoder(gzipWriter)
-	encoder.SetIndent("", "  ")
-	
-	if err := encoder.Encode(content); err != nil {
-		return "", fmt.Errorf("failed to encode content to compressed JSON: %w", err)
-	}
-
-	return compressedPath, nil
 }
 
-func (sm *Manager) generateOutputFilename(metadata models.DocumentMetadata) string {
-	timestamp := metadata.ProcessedAt.Format("20060102_150405")
-	
-	safeID := sm.sanitizeFilename(metadata.ID)
-	
-	filename := fmt.Sprintf("%s_%s.json", safeID, timestamp)
-	return filename
+func (sm *Manager) Save(content *models.ExtractedContent) (string, error) {
+	return sm.backend.Save(content)
 }
 
-func (sm *Manager) sanitizeFilename(filename string) string {
-	unsafe := []string{"/", "\\", ":", "*", "?", "\"", "<", ">", "|", " "}
-	safe := filename
-	
-	for _, char := range unsafe {
-		safe = strings.ReplaceAll(safe, char, "_")
-	}
-	
-	for strings.Contains(safe, "__") {
-		safe = strings.ReplaceAll(safe, "__", "_")
-	}
-	
-	safe = strings.Trim(safe, "_")
-	
-	if safe == "" {
-		safe = "document"
-	}
-	
-	if len(safe) > 100 {
-		safe = safe[:100]
-	}
-	
-	return safe
+func (sm *Manager) SaveBatch(contents []*models.ExtractedContent) ([]string, error) {
+	return sm.backend.SaveBatch(contents)
 }
 
-func (sm *Manager) Load(filePath string) (*models.ExtractedContent, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
-
-	var content models.ExtractedContent
-
-	if strings.HasSuffix(filePath, ".gz") {
-		gzipReader, err := gzip.NewReader(file)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
-		}
-		defer gzipReader.Close()
-
-		decoder := json.NewDecoder(gzipReader)
-		if err := decoder.Decode(&content); err != nil {
-			return nil, fmt.Errorf("failed to decode compressed JSON: %w", err)
-		}
-	} else {
-		decoder := json.NewDecoder(file)
-		if err := decoder.Decode(&content); err != nil {
-			return nil, fmt.Errorf("failed to decode JSON: %w", err)
-		}
-	}
-
-	return &content, nil
+func (sm *Manager) Load(identifier string) (*models.ExtractedContent, error) {
+	return sm.backend.Load(identifier)
 }
 
-func (sm *Manager) SaveBatch(contents []*models.ExtractedContent) ([]string, error) {
-	var outputPaths []string
-	var errors []error
-
-	for _, content := range contents {
-		outputPath, err := sm.Save(content)
-		if err != nil {
-			errors = append(errors, fmt.Errorf("failed to save document %s: %w", 
-				content.Metadata.ID, err))
-			continue
-		}
-		outputPaths = append(outputPaths, outputPath)
-	}
+// GetStorageStats reports the active backend's own stats (file/segment
+// counts and sizes), tagged with which backend produced them.
+func (sm *Manager) GetStorageStats() (*StorageStats, error) {
+	return sm.backend.Stats()
+}
 
-	if len(errors) > 0 {
-		var errorMsgs []string
-		for _, err := range errors {
-			errorMsgs = append(errorMsgs, err.Error())
-		}
-		return outputPaths, fmt.Errorf("batch save completed with %d errors: %s", 
-			len(errors), strings.Join(errorMsgs, "; "))
-	}
+// Close releases any resources (open segment/index files, parquet writers)
+// held by the active backend.
+func (sm *Manager) Close() error {
+	return sm.backend.Close()
+}
 
-	return outputPaths, nil
+// StartCompaction starts a Compactor that periodically merges small
+// ndjson_zstd segments into larger Parquet files, returning the handle so
+// the caller can Stop it. The compactor runs independently of which
+// backend is currently active, so it's safe to enable even if Backend was
+// switched to "parquet" after some segments already accumulated.
+func (sm *Manager) StartCompaction(interval time.Duration) *Compactor {
+	compactor := NewCompactor(sm, interval)
+	go compactor.Run()
+	return compactor
 }
 
 func (sm *Manager) CreateTempFile(prefix string) (*os.File, error) {
@@ -182,54 +99,22 @@ func (sm *Manager) CreateTempFile(prefix string) (*os.File, error) {
 
 func (sm *Manager) CleanupTempFiles() error {
 	cutoff := time.Now().Add(-24 * time.Hour)
-	
+
 	err := filepath.Walk(sm.config.TempDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		
+
 		if !info.IsDir() && info.ModTime().Before(cutoff) {
 			if removeErr := os.Remove(path); removeErr != nil {
 				fmt.Printf("Warning: failed to remove temp file %s: %v\n", path, removeErr)
 			}
 		}
-		
-		return nil
-	})
-
-	return err
-}
-
-func (sm *Manager) GetStorageStats() (*StorageStats, error) {
-	stats := &StorageStats{
-		OutputDir: sm.config.OutputDir,
-		TempDir:   sm.config.TempDir,
-	}
 
-	err := filepath.Walk(sm.config.OutputDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		
-		if !info.IsDir() {
-			stats.TotalFiles++
-			stats.TotalSize += info.Size()
-			
-			if strings.HasSuffix(path, ".json") {
-				stats.JsonFiles++
-			} else if strings.HasSuffix(path, ".json.gz") {
-				stats.CompressedFiles++
-			}
-		}
-		
 		return nil
 	})
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to calculate storage stats: %w", err)
-	}
-
-	return stats, nil
+	return err
 }
 
 func (sm *Manager) ListProcessedDocuments() ([]string, error) {
@@ -239,11 +124,11 @@ func (sm *Manager) ListProcessedDocuments() ([]string, error) {
 		if err != nil {
 			return err
 		}
-		
-		if !info.IsDir() && (strings.HasSuffix(path, ".json") || strings.HasSuffix(path, ".json.gz")) {
+
+		if !info.IsDir() && isDocumentFile(path) {
 			documents = append(documents, path)
 		}
-		
+
 		return nil
 	})
 
@@ -254,6 +139,15 @@ func (sm *Manager) ListProcessedDocuments() ([]string, error) {
 	return documents, nil
 }
 
+func isDocumentFile(path string) bool {
+	for _, suffix := range []string{".json", ".json.gz", ".ndjson.zst", ".parquet"} {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
 func (sm *Manager) DeleteDocument(documentPath string) error {
 	absOutputDir, err := filepath.Abs(sm.config.OutputDir)
 	if err != nil {
@@ -275,12 +169,3 @@ func (sm *Manager) DeleteDocument(documentPath string) error {
 
 	return nil
 }
-
-type StorageStats struct {
-	OutputDir       string `json:"output_dir"`
-	TempDir         string `json:"temp_dir"`
-	TotalFiles      int    `json:"total_files"`
-	JsonFiles       int    `json:"json_files"`
-	CompressedFiles int    `json:"compressed_files"`
-	TotalSize       int64  `json:"total_size_bytes"`
-}
\ No newline at end of file
@@ -0,0 +1,300 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/config"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
+	"github.com/klauspost/compress/zstd"
+)
+
+// ndjsonIndexEntry is one sidecar index record: where its line starts in
+// the segment's uncompressed byte stream, how big it is, and enough to
+// identify it without decompressing.
+type ndjsonIndexEntry struct {
+	Offset    int64  `json:"offset"`
+	Size      int    `json:"size"`
+	Timestamp string `json:"timestamp"`
+	DocID     string `json:"doc_id"`
+}
+
+// ndjsonZstdStore appends each ExtractedContent as one line of a
+// Zstandard-compressed newline-delimited JSON log, with a sidecar index
+// (offset, size, timestamp, doc id) per line. Segments rotate by size
+// and/or age per config.NDJSONZstdConfig, so a single append log doesn't
+// grow unbounded.
+type ndjsonZstdStore struct {
+	config     *config.StorageConfig
+	segmentDir string
+
+	mu           sync.Mutex
+	segmentID    string
+	segmentStart time.Time
+	offset       int64
+	count        int
+
+	file       *os.File
+	zstdWriter *zstd.Encoder
+	indexFile  *os.File
+	indexEnc   *json.Encoder
+}
+
+func newNDJSONZstdStore(cfg *config.StorageConfig) (*ndjsonZstdStore, error) {
+	s := &ndjsonZstdStore{
+		config:     cfg,
+		segmentDir: filepath.Join(cfg.OutputDir, "ndjson"),
+	}
+
+	if err := os.MkdirAll(s.segmentDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create ndjson segment directory: %w", err)
+	}
+
+	if err := s.rotate(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// rotate closes the current segment (if any) and opens a fresh one. Callers
+// must hold s.mu.
+func (s *ndjsonZstdStore) rotate() error {
+	if s.zstdWriter != nil {
+		s.zstdWriter.Close()
+	}
+	if s.file != nil {
+		s.file.Close()
+	}
+	if s.indexFile != nil {
+		s.indexFile.Close()
+	}
+
+	s.segmentID = fmt.Sprintf("segment-%d", time.Now().UnixNano())
+	segmentPath := filepath.Join(s.segmentDir, s.segmentID+".ndjson.zst")
+	indexPath := filepath.Join(s.segmentDir, s.segmentID+".idx.json")
+
+	file, err := os.Create(segmentPath)
+	if err != nil {
+		return fmt.Errorf("failed to create ndjson segment %s: %w", segmentPath, err)
+	}
+
+	var opts []zstd.EOption
+	if s.config.NDJSONZstd.Level > 0 {
+		opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(s.config.NDJSONZstd.Level)))
+	}
+
+	zw, err := zstd.NewWriter(file, opts...)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open zstd writer for %s: %w", segmentPath, err)
+	}
+
+	indexFile, err := os.Create(indexPath)
+	if err != nil {
+		zw.Close()
+		file.Close()
+		return fmt.Errorf("failed to create ndjson index %s: %w", indexPath, err)
+	}
+
+	s.file = file
+	s.zstdWriter = zw
+	s.indexFile = indexFile
+	s.indexEnc = json.NewEncoder(indexFile)
+	s.segmentStart = time.Now()
+	s.offset = 0
+	s.count = 0
+
+	return nil
+}
+
+// needsRotation must be called with s.mu held.
+func (s *ndjsonZstdStore) needsRotation() bool {
+	cfg := s.config.NDJSONZstd
+	if cfg.RotationSize > 0 && s.offset >= cfg.RotationSize {
+		return true
+	}
+	if cfg.RotationInterval.Duration > 0 && time.Since(s.segmentStart) >= cfg.RotationInterval.Duration {
+		return true
+	}
+	return false
+}
+
+// Save appends content as one line and returns "segmentID#lineIndex", which
+// Load uses to find it again without re-scanning every segment.
+func (s *ndjsonZstdStore) Save(content *models.ExtractedContent) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.count > 0 && s.needsRotation() {
+		if err := s.rotate(); err != nil {
+			return "", err
+		}
+	}
+
+	line, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal content for ndjson segment: %w", err)
+	}
+	line = append(line, '\n')
+
+	if _, err := s.zstdWriter.Write(line); err != nil {
+		return "", fmt.Errorf("failed to append to ndjson segment: %w", err)
+	}
+	if err := s.zstdWriter.Flush(); err != nil {
+		return "", fmt.Errorf("failed to flush ndjson segment: %w", err)
+	}
+
+	entry := ndjsonIndexEntry{
+		Offset:    s.offset,
+		Size:      len(line),
+		Timestamp: content.Metadata.ProcessedAt.Format(time.RFC3339),
+		DocID:     content.Metadata.ID,
+	}
+	if err := s.indexEnc.Encode(entry); err != nil {
+		return "", fmt.Errorf("failed to append to ndjson index: %w", err)
+	}
+
+	identifier := fmt.Sprintf("%s#%d", s.segmentID, s.count)
+	s.offset += int64(len(line))
+	s.count++
+
+	return identifier, nil
+}
+
+func (s *ndjsonZstdStore) SaveBatch(contents []*models.ExtractedContent) ([]string, error) {
+	identifiers := make([]string, 0, len(contents))
+	for _, content := range contents {
+		identifier, err := s.Save(content)
+		if err != nil {
+			return identifiers, fmt.Errorf("failed to save document %s: %w", content.Metadata.ID, err)
+		}
+		identifiers = append(identifiers, identifier)
+	}
+	return identifiers, nil
+}
+
+// Load decodes identifier ("segmentID#lineIndex", as returned by Save) back
+// into the original content.
+func (s *ndjsonZstdStore) Load(identifier string) (*models.ExtractedContent, error) {
+	segmentID, lineIndexStr, ok := strings.Cut(identifier, "#")
+	if !ok {
+		return nil, fmt.Errorf("ndjson_zstd Load expects \"segmentID#lineIndex\", got %q", identifier)
+	}
+
+	lineIndex, err := strconv.Atoi(lineIndexStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ndjson line index in %q: %w", identifier, err)
+	}
+
+	records, err := s.LoadAll(segmentID)
+	if err != nil {
+		return nil, err
+	}
+	if lineIndex < 0 || lineIndex >= len(records) {
+		return nil, fmt.Errorf("ndjson segment %s has no record at line %d", segmentID, lineIndex)
+	}
+
+	return records[lineIndex], nil
+}
+
+// LoadAll decompresses segmentID in full and returns every record it holds,
+// in append order. Compactor uses this directly, since it needs every
+// record rather than one at a time.
+func (s *ndjsonZstdStore) LoadAll(segmentID string) ([]*models.ExtractedContent, error) {
+	segmentPath := filepath.Join(s.segmentDir, segmentID+".ndjson.zst")
+
+	file, err := os.Open(segmentPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ndjson segment %s: %w", segmentPath, err)
+	}
+	defer file.Close()
+
+	zr, err := zstd.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zstd reader for %s: %w", segmentPath, err)
+	}
+	defer zr.Close()
+
+	var records []*models.ExtractedContent
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		var content models.ExtractedContent
+		if err := json.Unmarshal(scanner.Bytes(), &content); err != nil {
+			return nil, fmt.Errorf("failed to decode ndjson record in segment %s: %w", segmentID, err)
+		}
+		records = append(records, &content)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan ndjson segment %s: %w", segmentID, err)
+	}
+
+	return records, nil
+}
+
+func (s *ndjsonZstdStore) Stats() (*StorageStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := &StorageStats{
+		Backend:   "ndjson_zstd",
+		OutputDir: s.segmentDir,
+		TempDir:   s.config.TempDir,
+	}
+
+	entries, err := os.ReadDir(s.segmentDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ndjson segments: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		stats.TotalFiles++
+		stats.TotalSize += info.Size()
+		if strings.HasSuffix(entry.Name(), ".ndjson.zst") {
+			stats.CompressedFiles++
+		}
+	}
+
+	return stats, nil
+}
+
+func (s *ndjsonZstdStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var firstErr error
+	if s.zstdWriter != nil {
+		if err := s.zstdWriter.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if s.file != nil {
+		if err := s.file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if s.indexFile != nil {
+		if err := s.indexFile.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/config"
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parquetDocumentRow is the columnar record parquetStore writes per
+// ExtractedContent, flattened so DuckDB/Spark can query it without
+// re-parsing JSON. Children (embedded resources, JSONPath chunks) are not
+// written: a row holds only the top-level document's own fields, since the
+// writer here works off a flat struct schema.
+type parquetDocumentRow struct {
+	DocID       string `parquet:"name=doc_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Title       string `parquet:"name=title, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ContentType string `parquet:"name=content_type, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CleanText   string `parquet:"name=clean_text, type=BYTE_ARRAY, convertedtype=UTF8"`
+	WordCount   int32  `parquet:"name=word_count, type=INT32"`
+	ProcessedAt int64  `parquet:"name=processed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+}
+
+// parquetStore writes ExtractedContent as Parquet rows. Unlike
+// jsonGzipStore/ndjsonZstdStore, it doesn't support reading individual
+// documents back out — it's meant to be queried directly by analytics
+// tools, not round-tripped through Load.
+type parquetStore struct {
+	config *config.StorageConfig
+
+	mu       sync.Mutex
+	filePath string
+	fw       source.ParquetFile
+	pw       *writer.ParquetWriter
+}
+
+func newParquetStore(cfg *config.StorageConfig) (*parquetStore, error) {
+	dir := filepath.Join(cfg.OutputDir, "parquet")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create parquet output directory: %w", err)
+	}
+
+	filePath := filepath.Join(dir, fmt.Sprintf("documents-%d.parquet", time.Now().UnixNano()))
+
+	fw, err := local.NewLocalFileWriter(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet file %s: %w", filePath, err)
+	}
+
+	pw, err := writer.NewParquetWriter(fw, new(parquetDocumentRow), 4)
+	if err != nil {
+		fw.Close()
+		return nil, fmt.Errorf("failed to open parquet writer for %s: %w", filePath, err)
+	}
+
+	rowGroupSize := cfg.Parquet.RowGroupSize
+	if rowGroupSize <= 0 {
+		rowGroupSize = 128 * 1024 * 1024
+	}
+	pw.RowGroupSize = int64(rowGroupSize)
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+
+	return &parquetStore{
+		config:   cfg,
+		filePath: filePath,
+		fw:       fw,
+		pw:       pw,
+	}, nil
+}
+
+func (s *parquetStore) Save(content *models.ExtractedContent) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := parquetDocumentRow{
+		DocID:       content.Metadata.ID,
+		Title:       content.Metadata.Title,
+		ContentType: content.Metadata.ContentType,
+		CleanText:   content.CleanText,
+		WordCount:   int32(content.WordCount),
+		ProcessedAt: content.Metadata.ProcessedAt.UnixMilli(),
+	}
+
+	if err := s.pw.Write(row); err != nil {
+		return "", fmt.Errorf("failed to write parquet row for %s: %w", content.Metadata.ID, err)
+	}
+
+	return s.filePath, nil
+}
+
+func (s *parquetStore) SaveBatch(contents []*models.ExtractedContent) ([]string, error) {
+	paths := make([]string, 0, len(contents))
+	for _, content := range contents {
+		path, err := s.Save(content)
+		if err != nil {
+			return paths, fmt.Errorf("failed to save document %s: %w", content.Metadata.ID, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func (s *parquetStore) Load(identifier string) (*models.ExtractedContent, error) {
+	return nil, fmt.Errorf("parquet backend does not support reading individual documents back out; query %s directly instead", identifier)
+}
+
+func (s *parquetStore) Stats() (*StorageStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat parquet file %s: %w", s.filePath, err)
+	}
+
+	return &StorageStats{
+		Backend:    "parquet",
+		OutputDir:  filepath.Dir(s.filePath),
+		TempDir:    s.config.TempDir,
+		TotalFiles: 1,
+		TotalSize:  info.Size(),
+	}, nil
+}
+
+func (s *parquetStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.pw.WriteStop(); err != nil {
+		return fmt.Errorf("failed to finalize parquet file %s: %w", s.filePath, err)
+	}
+
+	return s.fw.Close()
+}
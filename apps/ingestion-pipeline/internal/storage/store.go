@@ -0,0 +1,33 @@
+package storage
+
+import "github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/models"
+
+// Store is a pluggable storage backend for ExtractedContent. Manager routes
+// Save, SaveBatch, Load, and GetStorageStats through whichever Store
+// newBackend selects for config.StorageConfig.Backend.
+type Store interface {
+	Save(content *models.ExtractedContent) (string, error)
+	SaveBatch(contents []*models.ExtractedContent) ([]string, error)
+
+	// Load resolves whatever identifier the backend's own Save returned
+	// (a file path for json_gzip, a "segmentID#lineIndex" pair for
+	// ndjson_zstd) back into the original content. Backends that don't
+	// support reading individual documents back out (parquet) return an
+	// error explaining why.
+	Load(identifier string) (*models.ExtractedContent, error)
+
+	Stats() (*StorageStats, error)
+	Close() error
+}
+
+// StorageStats reports file/segment counts and sizes for whichever backend
+// produced them.
+type StorageStats struct {
+	Backend         string `json:"backend"`
+	OutputDir       string `json:"output_dir"`
+	TempDir         string `json:"temp_dir"`
+	TotalFiles      int    `json:"total_files"`
+	JsonFiles       int    `json:"json_files"`
+	CompressedFiles int    `json:"compressed_files"`
+	TotalSize       int64  `json:"total_size_bytes"`
+}
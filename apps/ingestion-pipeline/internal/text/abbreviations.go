@@ -0,0 +1,52 @@
+package text
+
+import "strings"
+
+// defaultAbbreviations holds each supported language's common
+// period-abbreviated titles and phrases, normalized to lowercase with
+// internal periods already stripped (so "U.S.A." and "usa" compare
+// equal). It's intentionally small: the goal is to catch the
+// abbreviations that actually show up in ingested documents often enough
+// to break chunk boundaries, not to be exhaustive.
+var defaultAbbreviations = map[string][]string{
+	"en": {
+		"mr", "mrs", "ms", "dr", "prof", "sr", "jr", "st", "vs", "etc",
+		"eg", "ie", "approx", "no", "inc", "ltd", "co", "corp", "dept",
+		"fig", "vol", "pg", "pp", "usa", "uk", "mt", "gen", "col", "capt",
+	},
+	"es": {
+		"sr", "sra", "srta", "dr", "dra", "prof", "ud", "uds", "etc",
+		"pág", "vol", "núm", "depto", "cía",
+	},
+	"fr": {
+		"m", "mme", "mlle", "dr", "prof", "etc", "cf", "ex", "p",
+		"vol", "no", "cie", "sté",
+	},
+	"de": {
+		"hr", "fr", "dr", "prof", "usw", "bzw", "ca", "bspw", "nr",
+		"bd", "jh", "str", "abb",
+	},
+}
+
+// abbreviationSet builds the effective abbreviation lookup for language,
+// merging in any caller-supplied extras. lang falling outside
+// defaultAbbreviations's keys is treated as "en".
+func abbreviationSet(lang string, extra []string) map[string]bool {
+	entries, ok := defaultAbbreviations[lang]
+	if !ok {
+		entries = defaultAbbreviations["en"]
+	}
+
+	set := make(map[string]bool, len(entries)+len(extra))
+	for _, word := range entries {
+		set[normalizeAbbreviation(word)] = true
+	}
+	for _, word := range extra {
+		set[normalizeAbbreviation(word)] = true
+	}
+	return set
+}
+
+func normalizeAbbreviation(word string) string {
+	return strings.ToLower(strings.ReplaceAll(word, ".", ""))
+}
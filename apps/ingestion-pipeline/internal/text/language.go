@@ -0,0 +1,54 @@
+package text
+
+import "strings"
+
+// languageDetectionSampleSize bounds how much of the text DetectLanguage
+// inspects. Document-level language is stable enough that the opening
+// ~2KB is plenty of signal, and capping it keeps detection cheap on long
+// documents.
+const languageDetectionSampleSize = 2048
+
+// languageSignatures maps each supported language to a handful of
+// character n-grams (padded with spaces so they only match whole-word
+// boundaries) that are both common and distinctive in that language.
+// DetectLanguage scores a sample by how many times each language's
+// n-grams occur in it and picks the highest-scoring language.
+var languageSignatures = map[string][]string{
+	"en": {" the ", " and ", " of ", " to ", " is ", " that ", " with ", " for "},
+	"es": {" de ", " que ", " la ", " el ", " los ", " para ", " con ", " una "},
+	"fr": {" de ", " le ", " la ", " les ", " des ", " est ", " que ", " pour "},
+	"de": {" der ", " die ", " und ", " das ", " ist ", " mit ", " den ", " für "},
+}
+
+// languageDetectionOrder fixes the order DetectLanguage checks languages
+// in, so a tied score always resolves to the same language rather than
+// depending on Go's randomized map iteration order. "en" leads the list
+// deliberately: it's the fallback, so a tie against it should keep it.
+var languageDetectionOrder = []string{"en", "es", "fr", "de"}
+
+// DetectLanguage guesses text's language from a small set of character
+// n-gram frequencies in its first ~2KB, returning one of
+// languageSignatures's keys. Ties and texts too short to carry any signal
+// resolve to "en".
+func DetectLanguage(text string) string {
+	sample := text
+	if len(sample) > languageDetectionSampleSize {
+		sample = sample[:languageDetectionSampleSize]
+	}
+	sample = " " + strings.ToLower(sample) + " "
+
+	best := "en"
+	bestScore := 0
+	for _, lang := range languageDetectionOrder {
+		score := 0
+		for _, ngram := range languageSignatures[lang] {
+			score += strings.Count(sample, ngram)
+		}
+		if score > bestScore {
+			bestScore = score
+			best = lang
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,65 @@
+package text
+
+import "fmt"
+
+// CleanStep is one named transformation a Pipeline can run. Steps are
+// pure string->string functions: no step depends on another having run
+// first, so any subset of the registry can be composed in any order.
+type CleanStep interface {
+	Name() string
+	Apply(text string) string
+}
+
+// Pipeline runs an ordered sequence of CleanSteps over a string.
+type Pipeline struct {
+	steps []CleanStep
+}
+
+// NewPipeline builds a Pipeline from the named steps, in order, looking
+// each one up in the built-in step registry. An unknown name is an error
+// rather than a silently-skipped step, so a typo in a config file's
+// text.pipeline list fails fast instead of quietly running fewer steps
+// than configured.
+func NewPipeline(names []string) (*Pipeline, error) {
+	steps := make([]CleanStep, 0, len(names))
+	for _, name := range names {
+		step, ok := stepRegistry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown text clean step %q", name)
+		}
+		steps = append(steps, step)
+	}
+	return &Pipeline{steps: steps}, nil
+}
+
+// Apply runs every step in order, feeding each step's output into the
+// next.
+func (p *Pipeline) Apply(text string) string {
+	for _, step := range p.steps {
+		text = step.Apply(text)
+	}
+	return text
+}
+
+// defaultPipelineSteps is the step order Cleaner has always run when no
+// text.pipeline is configured, preserving existing behavior for configs
+// that predate this package's pipeline support.
+var defaultPipelineSteps = []string{
+	"html_strip",
+	"control_chars",
+	"collapse_ws",
+	"collapse_newlines",
+	"trim",
+	"ocr_artifacts",
+}
+
+func newDefaultPipeline() *Pipeline {
+	pipeline, err := NewPipeline(defaultPipelineSteps)
+	if err != nil {
+		// defaultPipelineSteps only names built-in steps registered below,
+		// so this can only happen if the two fall out of sync with each
+		// other.
+		panic(err)
+	}
+	return pipeline
+}
@@ -0,0 +1,280 @@
+package text
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Span marks one detected PII occurrence by its byte offsets into the
+// text a Detector scanned, and what kind of PII it is ("email", "phone",
+// "credit_card", "iban", "ipv4", "ipv6", or a user-defined pattern name).
+type Span struct {
+	Start, End int
+	Type       string
+}
+
+// Detector finds every occurrence of one kind of PII in text.
+type Detector interface {
+	Detect(text string) []Span
+}
+
+// regexDetector implements Detector for anything expressible as a single
+// regular expression: every built-in detector below, and every
+// user-defined pattern from RedactorConfig.Patterns.
+type regexDetector struct {
+	spanType string
+	re       *regexp.Regexp
+	validate func(match string) bool // optional; nil means every regex match is accepted
+}
+
+func (d regexDetector) Detect(text string) []Span {
+	var spans []Span
+	for _, loc := range d.re.FindAllStringIndex(text, -1) {
+		if d.validate != nil && !d.validate(text[loc[0]:loc[1]]) {
+			continue
+		}
+		spans = append(spans, Span{Start: loc[0], End: loc[1], Type: d.spanType})
+	}
+	return spans
+}
+
+var emailDetector = regexDetector{
+	spanType: "email",
+	re:       regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}\b`),
+}
+
+// phoneDetector matches E.164 numbers ("+14155552671") and the common
+// separator-delimited national format ("(415) 555-2671", "415-555-2671").
+var phoneDetector = regexDetector{
+	spanType: "phone",
+	re:       regexp.MustCompile(`\+[1-9]\d{7,14}|\(?\b\d{3}\)?[-.\s]\d{3}[-.\s]\d{4}\b`),
+}
+
+// creditCardCandidateRegex matches digit runs (allowing space/dash
+// separators, as cards are usually printed) of a plausible card-number
+// length; luhnValid filters this down to ones that actually pass the
+// Luhn check, cutting false positives against ordinary long numbers
+// (invoice IDs, phone numbers that slipped past word boundaries, etc.).
+var creditCardCandidateRegex = regexp.MustCompile(`\b(?:\d[ -]?){13,19}\b`)
+
+var creditCardDetector = regexDetector{
+	spanType: "credit_card",
+	re:       creditCardCandidateRegex,
+	validate: func(match string) bool {
+		return luhnValid(match)
+	},
+}
+
+func luhnValid(raw string) bool {
+	digits := make([]int, 0, len(raw))
+	for _, r := range raw {
+		if r < '0' || r > '9' {
+			continue
+		}
+		d, _ := strconv.Atoi(string(r))
+		digits = append(digits, d)
+	}
+	if len(digits) < 13 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// ibanDetector matches an ISO 13616 IBAN: a two-letter country code, a
+// two-digit check, and up to 30 alphanumeric characters of BBAN.
+var ibanDetector = regexDetector{
+	spanType: "iban",
+	re:       regexp.MustCompile(`\b[A-Z]{2}\d{2}[A-Z0-9]{11,30}\b`),
+}
+
+var ipv4Detector = regexDetector{
+	spanType: "ipv4",
+	re:       regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`),
+}
+
+// ipv6Detector covers both the uncompressed form (8 colon-separated
+// groups) and the "::" zero-run-compressed form. It isn't a full RFC
+// 4291 parser, but it catches addresses as they actually appear in logs
+// and config files.
+var ipv6Detector = regexDetector{
+	spanType: "ipv6",
+	re: regexp.MustCompile(
+		`\b(?:[A-Fa-f0-9]{1,4}:){7}[A-Fa-f0-9]{1,4}\b` +
+			`|\b(?:[A-Fa-f0-9]{1,4}:){1,7}:(?:[A-Fa-f0-9]{1,4}:?){0,6}[A-Fa-f0-9]{0,4}\b` +
+			`|\b::(?:[A-Fa-f0-9]{1,4}:){0,6}[A-Fa-f0-9]{1,4}\b`,
+	),
+}
+
+// defaultTokens is what each built-in detector's span is replaced with
+// when RedactorConfig.Tokens doesn't override it.
+var defaultTokens = map[string]string{
+	"email":       "[EMAIL]",
+	"phone":       "[PHONE]",
+	"credit_card": "[CREDIT_CARD]",
+	"iban":        "[IBAN]",
+	"ipv4":        "[IP]",
+	"ipv6":        "[IP]",
+}
+
+// RedactorConfig configures NewRedactor.
+type RedactorConfig struct {
+	// Tokens overrides the replacement token for a span type, e.g.
+	// {"email": "[REDACTED_EMAIL]"}. Unlisted types fall back to
+	// defaultTokens, and a type with neither falls back to "[REDACTED]".
+	Tokens map[string]string
+
+	// Patterns adds a user-defined regexDetector per entry: key is the
+	// span type name used in Tokens/the sidecar map, value is the regex
+	// to match.
+	Patterns map[string]string
+
+	// EmitSidecar, if true, makes Redact return a map from each inserted
+	// placeholder back to the original value it replaced (e.g.
+	// "[EMAIL_1]" -> "alice@example.com"), so a caller with the right
+	// authorization can rehydrate the original text. If false, Redact
+	// always uses the plain type token with no per-occurrence numbering
+	// and returns a nil sidecar, so the substitution isn't reversible.
+	EmitSidecar bool
+}
+
+// Redactor finds and masks PII in text.
+type Redactor struct {
+	detectors []Detector
+	tokens    map[string]string
+	sidecar   bool
+}
+
+// NewRedactor builds a Redactor running the six built-in detectors
+// (email, phone, credit_card, iban, ipv4, ipv6) plus one regexDetector
+// per entry in cfg.Patterns.
+func NewRedactor(cfg RedactorConfig) (*Redactor, error) {
+	detectors := []Detector{
+		emailDetector, phoneDetector, creditCardDetector,
+		ibanDetector, ipv4Detector, ipv6Detector,
+	}
+
+	for name, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", name, err)
+		}
+		detectors = append(detectors, regexDetector{spanType: name, re: re})
+	}
+
+	tokens := make(map[string]string, len(defaultTokens)+len(cfg.Tokens))
+	for spanType, token := range defaultTokens {
+		tokens[spanType] = token
+	}
+	for spanType, token := range cfg.Tokens {
+		tokens[spanType] = token
+	}
+
+	return &Redactor{detectors: detectors, tokens: tokens, sidecar: cfg.EmitSidecar}, nil
+}
+
+func (r *Redactor) tokenFor(spanType string) string {
+	if token, ok := r.tokens[spanType]; ok {
+		return token
+	}
+	return "[REDACTED]"
+}
+
+// Redact replaces every detected PII span in text with its configured
+// token. When the Redactor was built with EmitSidecar, each placeholder
+// is numbered per type (e.g. "[EMAIL_1]", "[EMAIL_2]") and the returned
+// map records the original value behind each one; otherwise the second
+// return value is nil.
+func (r *Redactor) Redact(text string) (string, map[string]string) {
+	var spans []Span
+	for _, d := range r.detectors {
+		spans = append(spans, d.Detect(text)...)
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].Start != spans[j].Start {
+			return spans[i].Start < spans[j].Start
+		}
+		return spans[i].End > spans[j].End // prefer the longer match at the same start
+	})
+
+	var kept []Span
+	lastEnd := -1
+	for _, s := range spans {
+		if s.Start < lastEnd {
+			continue
+		}
+		kept = append(kept, s)
+		lastEnd = s.End
+	}
+
+	var sidecar map[string]string
+	if r.sidecar {
+		sidecar = make(map[string]string)
+	}
+
+	var b strings.Builder
+	counts := make(map[string]int)
+	cursor := 0
+	for _, s := range kept {
+		b.WriteString(text[cursor:s.Start])
+
+		token := r.tokenFor(s.Type)
+		if r.sidecar {
+			counts[s.Type]++
+			placeholder := strings.TrimSuffix(token, "]") + "_" + strconv.Itoa(counts[s.Type]) + "]"
+			sidecar[placeholder] = text[s.Start:s.End]
+			b.WriteString(placeholder)
+		} else {
+			b.WriteString(token)
+		}
+
+		cursor = s.End
+	}
+	b.WriteString(text[cursor:])
+
+	return b.String(), sidecar
+}
+
+// RedactStep wraps a Redactor as a CleanStep, for use in a configured
+// text.pipeline. It discards the sidecar map Redact can produce; a
+// caller that needs rehydration should call the underlying Redactor's
+// Redact directly instead of going through the pipeline.
+type RedactStep struct {
+	redactor *Redactor
+}
+
+func NewRedactStep(redactor *Redactor) *RedactStep {
+	return &RedactStep{redactor: redactor}
+}
+
+func (s *RedactStep) Name() string { return "redact_pii" }
+
+func (s *RedactStep) Apply(text string) string {
+	redacted, _ := s.redactor.Redact(text)
+	return redacted
+}
+
+// RegisterRedactStep adds redactor to the built-in step registry under
+// the "redact_pii" name, so a config's text.pipeline list can opt into
+// it the same way it names any other built-in step. Call once during
+// startup, before building a Cleaner from the pipeline that references
+// it.
+func RegisterRedactStep(redactor *Redactor) {
+	registerStep(NewRedactStep(redactor))
+}
@@ -0,0 +1,113 @@
+package text
+
+import "testing"
+
+func TestLuhnValid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want bool
+	}{
+		{name: "valid visa", raw: "4532015112830366", want: true},
+		{name: "valid with separators", raw: "4532-0151-1283-0366", want: true},
+		{name: "invalid check digit", raw: "4532015112830367", want: false},
+		{name: "too short to be a card", raw: "123456789012", want: false},
+		{name: "non-digit noise is ignored", raw: "4532 0151 1283 0366", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := luhnValid(tt.raw); got != tt.want {
+				t.Errorf("luhnValid(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorRedact(t *testing.T) {
+	redactor, err := NewRedactor(RedactorConfig{})
+	if err != nil {
+		t.Fatalf("NewRedactor: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "email",
+			text: "contact alice@example.com for details",
+			want: "contact [EMAIL] for details",
+		},
+		{
+			name: "valid credit card is redacted",
+			text: "card on file: 4532015112830366",
+			want: "card on file: [CREDIT_CARD]",
+		},
+		{
+			name: "invalid credit card candidate is left alone",
+			text: "order id: 4532015112830367",
+			want: "order id: 4532015112830367",
+		},
+		{
+			name: "ipv4 address",
+			text: "connect to 192.168.1.1 now",
+			want: "connect to [IP] now",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, sidecar := redactor.Redact(tt.text)
+			if got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			if sidecar != nil {
+				t.Errorf("Redact(%q) sidecar = %v, want nil (EmitSidecar not set)", tt.text, sidecar)
+			}
+		})
+	}
+}
+
+func TestRedactorOverlapResolution(t *testing.T) {
+	// Two custom patterns matching the same starting offset but different
+	// lengths should not both fire: Redact keeps the longer span at a
+	// given start and drops any span it overlaps, so the output has
+	// exactly one placeholder, not two interleaved ones.
+	redactor, err := NewRedactor(RedactorConfig{
+		Patterns: map[string]string{
+			"short": `CODE\d{3}`,
+			"long":  `CODE\d{3}-X`,
+		},
+		Tokens: map[string]string{
+			"short": "[SHORT]",
+			"long":  "[LONG]",
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRedactor: %v", err)
+	}
+
+	got, _ := redactor.Redact("id is CODE123-X end")
+	want := "id is [LONG] end"
+	if got != want {
+		t.Errorf("Redact overlap = %q, want %q", got, want)
+	}
+}
+
+func TestRedactorEmitSidecar(t *testing.T) {
+	redactor, err := NewRedactor(RedactorConfig{EmitSidecar: true})
+	if err != nil {
+		t.Fatalf("NewRedactor: %v", err)
+	}
+
+	got, sidecar := redactor.Redact("alice@example.com and bob@example.com")
+	want := "[EMAIL_1] and [EMAIL_2]"
+	if got != want {
+		t.Errorf("Redact(sidecar) = %q, want %q", got, want)
+	}
+	if sidecar["[EMAIL_1]"] != "alice@example.com" || sidecar["[EMAIL_2]"] != "bob@example.com" {
+		t.Errorf("sidecar = %v, want alice/bob mapping", sidecar)
+	}
+}
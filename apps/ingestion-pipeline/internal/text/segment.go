@@ -0,0 +1,200 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SegmentOptions configures ExtractSentences. A zero-value SegmentOptions
+// auto-detects the language and applies no sentence-length filter.
+type SegmentOptions struct {
+	// Language, if set, skips language detection and selects that
+	// language's abbreviation set directly (e.g. "en", "es", "fr", "de").
+	// An unrecognized language falls back to "en".
+	Language string
+
+	// Abbreviations is merged into the selected language's built-in
+	// abbreviation set. Entries are matched case-insensitively with
+	// internal periods stripped, so both "approx" and "approx." work.
+	Abbreviations []string
+
+	// MinSentenceLength drops any segmented sentence shorter than this
+	// many runes after trimming. Zero keeps every non-empty sentence.
+	MinSentenceLength int
+}
+
+// sentenceBreakClass is a simplified version of UAX #29's sentence break
+// property classes: just enough of the standard to resolve the cases that
+// actually come up in extracted document text (abbreviations, decimals,
+// closing quotes/brackets, multi-character terminators like "?!" or
+// "...", and paragraph breaks).
+type sentenceBreakClass int
+
+const (
+	classOther   sentenceBreakClass = iota
+	classATerm                      // '.'
+	classSTerm                      // '!' or '?'
+	classClose                      // closing quote/bracket
+	classSp                         // inline whitespace
+	classParaSep                    // paragraph separator (newline, etc.)
+	classNumeric
+	classLetter
+)
+
+func classify(r rune) sentenceBreakClass {
+	switch r {
+	case '.':
+		return classATerm
+	case '!', '?':
+		return classSTerm
+	case '\n', '\r', ' ', ' ':
+		return classParaSep
+	}
+	switch {
+	case unicode.IsDigit(r):
+		return classNumeric
+	case isCloseRune(r):
+		return classClose
+	case unicode.IsSpace(r):
+		return classSp
+	case unicode.IsLetter(r):
+		return classLetter
+	default:
+		return classOther
+	}
+}
+
+// closeRunes are closing quotes and brackets UAX #29 treats as still part
+// of the sentence that precedes them, e.g. the quote in `She said "no."`
+// belongs with the sentence it closes, not the one after it.
+var closeRunes = map[rune]bool{
+	')': true, ']': true, '}': true,
+	'"': true, '\'': true,
+	'”': true, '’': true, '»': true, '›': true,
+	'」': true, '』': true,
+}
+
+func isCloseRune(r rune) bool {
+	return closeRunes[r]
+}
+
+// isTerminator reports whether cls ends a sentence candidate (ATerm or
+// STerm).
+func isTerminator(cls sentenceBreakClass) bool {
+	return cls == classATerm || cls == classSTerm
+}
+
+// lastAbbreviationCandidate scans backward from upTo (the index of a '.'
+// rune) over a run of letters and embedded periods, e.g. picking "e.g"
+// out of "... such as e.g. apples" or "U.S.A" out of "... the U.S.A.
+// today". Internal periods are stripped by the caller before the
+// abbreviation-set lookup, so "U.S.A" and "usa" match the same entry.
+func lastAbbreviationCandidate(runes []rune, upTo int) string {
+	j := upTo
+	for j > 0 {
+		r := runes[j-1]
+		if unicode.IsLetter(r) || r == '.' {
+			j--
+			continue
+		}
+		break
+	}
+	return strings.ToLower(strings.Trim(string(runes[j:upTo]), "."))
+}
+
+// ExtractSentences splits text into sentences using a simplified UAX #29
+// sentence-boundary walk: a terminator (ATerm/STerm), followed by any
+// closing quotes/brackets and whitespace, is a sentence boundary unless
+// the token immediately before it is a known abbreviation for opts'
+// language, or it's a decimal point between two digits. Language is
+// taken from opts.Language if set, otherwise detected from the first
+// ~2KB of text via DetectLanguage.
+func (c *Cleaner) ExtractSentences(text string, opts SegmentOptions) []string {
+	if text == "" {
+		return []string{}
+	}
+
+	lang := opts.Language
+	if lang == "" {
+		lang = DetectLanguage(text)
+	}
+	abbreviations := abbreviationSet(lang, opts.Abbreviations)
+
+	runes := []rune(text)
+	n := len(runes)
+
+	var sentences []string
+	emit := func(s string) {
+		trimmed := strings.TrimSpace(s)
+		if trimmed == "" {
+			return
+		}
+		if len([]rune(trimmed)) < opts.MinSentenceLength {
+			return
+		}
+		sentences = append(sentences, trimmed)
+	}
+
+	start := 0
+	i := 0
+	for i < n {
+		cls := classify(runes[i])
+
+		if cls == classParaSep {
+			emit(string(runes[start:i]))
+			for i < n && classify(runes[i]) == classParaSep {
+				i++
+			}
+			start = i
+			continue
+		}
+
+		if !isTerminator(cls) {
+			i++
+			continue
+		}
+
+		termStart := i
+		for i < n && isTerminator(classify(runes[i])) {
+			i++
+		}
+
+		// SB6-style exception: a single '.' directly between two digits is
+		// a decimal point, not a sentence terminator.
+		if i-termStart == 1 && runes[termStart] == '.' &&
+			termStart > 0 && classify(runes[termStart-1]) == classNumeric &&
+			i < n && classify(runes[i]) == classNumeric {
+			continue
+		}
+
+		for i < n && classify(runes[i]) == classClose {
+			i++
+		}
+		spanEnd := i
+		for i < n && classify(runes[i]) == classSp {
+			i++
+		}
+
+		if i == spanEnd && i < n {
+			// No whitespace/EOF followed the terminator (e.g. the first
+			// '.' in "e.g."), so this isn't a candidate boundary at all.
+			continue
+		}
+
+		if runes[termStart] == '.' {
+			word := lastAbbreviationCandidate(runes, termStart)
+			if word != "" && abbreviations[strings.ReplaceAll(word, ".", "")] {
+				continue
+			}
+		}
+
+		emit(string(runes[start:spanEnd]))
+		start = i
+	}
+
+	if start < n {
+		emit(string(runes[start:]))
+	}
+
+	return sentences
+}
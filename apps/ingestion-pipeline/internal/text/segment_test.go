@@ -0,0 +1,84 @@
+package text
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractSentences(t *testing.T) {
+	cleaner, err := NewCleaner(true, nil)
+	if err != nil {
+		t.Fatalf("NewCleaner: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		opts SegmentOptions
+		want []string
+	}{
+		{
+			name: "plain sentences",
+			text: "One. Two. Three.",
+			opts: SegmentOptions{Language: "en"},
+			want: []string{"One.", "Two.", "Three."},
+		},
+		{
+			name: "abbreviation is not a sentence break",
+			text: "Dr. Smith arrived. He left soon after.",
+			opts: SegmentOptions{Language: "en"},
+			want: []string{"Dr. Smith arrived.", "He left soon after."},
+		},
+		{
+			name: "custom abbreviation extends the built-in set",
+			text: "See approx. 5 items re. the order. Next sentence.",
+			opts: SegmentOptions{Language: "en", Abbreviations: []string{"re"}},
+			want: []string{"See approx. 5 items re. the order.", "Next sentence."},
+		},
+		{
+			name: "decimal point is not a sentence break",
+			text: "The total was 3.14 units. It rounded up.",
+			opts: SegmentOptions{Language: "en"},
+			want: []string{"The total was 3.14 units.", "It rounded up."},
+		},
+		{
+			name: "period after a trailing digit with no following digit still breaks",
+			text: "It cost 5. He paid cash.",
+			opts: SegmentOptions{Language: "en"},
+			want: []string{"It cost 5.", "He paid cash."},
+		},
+		{
+			name: "closing quote after terminator stays with its sentence",
+			text: `She said "stop." He walked away.`,
+			opts: SegmentOptions{Language: "en"},
+			want: []string{`She said "stop."`, "He walked away."},
+		},
+		{
+			name: "min sentence length filters short fragments",
+			text: "Hi. This one is long enough.",
+			opts: SegmentOptions{Language: "en", MinSentenceLength: 10},
+			want: []string{"This one is long enough."},
+		},
+		{
+			name: "paragraph break splits without requiring a terminator",
+			text: "First line\n\nSecond line.",
+			opts: SegmentOptions{Language: "en"},
+			want: []string{"First line", "Second line."},
+		},
+		{
+			name: "empty text yields no sentences",
+			text: "",
+			opts: SegmentOptions{Language: "en"},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cleaner.ExtractSentences(tt.text, tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractSentences(%q) = %#v, want %#v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
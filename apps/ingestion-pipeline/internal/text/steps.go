@@ -0,0 +1,213 @@
+package text
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// stepRegistry maps a config-file step name to its built-in
+// implementation. NewPipeline is the only reader; register a new step
+// here to make it available to text.pipeline.
+var stepRegistry = map[string]CleanStep{}
+
+func registerStep(step CleanStep) {
+	stepRegistry[step.Name()] = step
+}
+
+func init() {
+	registerStep(htmlStripStep{})
+	registerStep(controlCharsStep{})
+	registerStep(collapseWhitespaceStep{})
+	registerStep(collapseNewlinesStep{})
+	registerStep(trimStep{})
+	registerStep(ocrArtifactsStep{})
+	registerStep(nfkcStep{})
+	registerStep(ligatureStep{})
+	registerStep(dehyphenateStep{})
+	registerStep(smartQuoteStep{})
+	registerStep(zeroWidthStep{})
+}
+
+var htmlTagsRegex = regexp.MustCompile(`<[^>]*>`)
+
+// htmlStripStep removes HTML/XML-style tags.
+type htmlStripStep struct{}
+
+func (htmlStripStep) Name() string { return "html_strip" }
+func (htmlStripStep) Apply(text string) string {
+	return htmlTagsRegex.ReplaceAllString(text, "")
+}
+
+var controlCharsRegex = regexp.MustCompile(`[\x00-\x08\x0B\x0C\x0E-\x1F\x7F]`)
+
+// controlCharsStep removes non-printable ASCII control characters, other
+// than the whitespace ones (tab, newline, carriage return) later steps
+// still need to see.
+type controlCharsStep struct{}
+
+func (controlCharsStep) Name() string { return "control_chars" }
+func (controlCharsStep) Apply(text string) string {
+	return controlCharsRegex.ReplaceAllString(text, "")
+}
+
+var multipleSpacesRegex = regexp.MustCompile(`\s+`)
+
+// collapseWhitespaceStep folds any run of whitespace into a single space.
+// Note this also collapses newlines, so it should run before
+// collapse_newlines has anything left to do; ordering is the config's
+// responsibility.
+type collapseWhitespaceStep struct{}
+
+func (collapseWhitespaceStep) Name() string { return "collapse_ws" }
+func (collapseWhitespaceStep) Apply(text string) string {
+	return multipleSpacesRegex.ReplaceAllString(text, " ")
+}
+
+var multipleNewlinesRegex = regexp.MustCompile(`\n{3,}`)
+
+// collapseNewlinesStep folds 3+ consecutive newlines down to a single
+// blank line.
+type collapseNewlinesStep struct{}
+
+func (collapseNewlinesStep) Name() string { return "collapse_newlines" }
+func (collapseNewlinesStep) Apply(text string) string {
+	return multipleNewlinesRegex.ReplaceAllString(text, "\n\n")
+}
+
+// trimStep trims leading and trailing whitespace from the whole text.
+type trimStep struct{}
+
+func (trimStep) Name() string { return "trim" }
+func (trimStep) Apply(text string) string {
+	return strings.TrimSpace(text)
+}
+
+var shortNonAlphaRegex = regexp.MustCompile(`^[^a-zA-Z]*$`)
+
+// ocrArtifactsStep drops lines that are almost certainly scanner noise
+// rather than content: single characters, short non-alphabetic runs, and
+// common rule/divider artifacts ("___", "---", "...", "|||", "^^^").
+type ocrArtifactsStep struct{}
+
+func (ocrArtifactsStep) Name() string { return "ocr_artifacts" }
+func (ocrArtifactsStep) Apply(text string) string {
+	lines := strings.Split(text, "\n")
+	var cleanedLines []string
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) > 1 && !isOCRArtifact(trimmed) {
+			cleanedLines = append(cleanedLines, line)
+		}
+	}
+
+	return strings.Join(cleanedLines, "\n")
+}
+
+func isOCRArtifact(line string) bool {
+	trimmed := strings.TrimSpace(line)
+
+	if len(trimmed) == 1 {
+		return true
+	}
+
+	if shortNonAlphaRegex.MatchString(trimmed) && len(trimmed) < 4 {
+		return true
+	}
+
+	commonArtifacts := []string{"___", "---", "...", "|||", "^^^"}
+	for _, artifact := range commonArtifacts {
+		if strings.Contains(trimmed, artifact) && len(trimmed) < 10 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nfkcStep applies Unicode NFKC normalization, folding compatibility
+// characters (full-width digits, ligatures encoded as a single code
+// point, etc.) to their canonical form.
+type nfkcStep struct{}
+
+func (nfkcStep) Name() string { return "nfkc" }
+func (nfkcStep) Apply(text string) string {
+	return norm.NFKC.String(text)
+}
+
+// ligatureReplacer expands the common Latin typographic ligatures PDF
+// extraction leaves behind as single code points. NFKC also decomposes
+// the U+FB00-U+FB06 presentation forms, but not Æ, æ, Œ, œ or Ꜳ, which
+// Unicode treats as letters with no decomposition.
+var ligatureReplacer = strings.NewReplacer(
+	"ﬀ", "ff",
+	"ﬁ", "fi",
+	"ﬂ", "fl",
+	"ﬃ", "ffi",
+	"ﬄ", "ffl",
+	"ﬆ", "st",
+	"Ꜳ", "AA",
+	"Æ", "AE",
+	"æ", "ae",
+	"Œ", "OE",
+	"œ", "oe",
+)
+
+// ligatureStep expands typographic ligatures into their constituent
+// letters.
+type ligatureStep struct{}
+
+func (ligatureStep) Name() string { return "ligature" }
+func (ligatureStep) Apply(text string) string {
+	return ligatureReplacer.Replace(text)
+}
+
+// hyphenEOLRegex matches a hyphen at the end of a line followed by a
+// lowercase letter starting the next line — the shape a PDF/OCR
+// extractor leaves when a word was hyphenated across a line break.
+var hyphenEOLRegex = regexp.MustCompile(`(\p{L})-\n(\p{Ll})`)
+
+// dehyphenateStep joins a word that was split across a line break by a
+// trailing hyphen back into one word, e.g. "hyphen-\nation" ->
+// "hyphenation".
+type dehyphenateStep struct{}
+
+func (dehyphenateStep) Name() string { return "dehyphenate" }
+func (dehyphenateStep) Apply(text string) string {
+	return hyphenEOLRegex.ReplaceAllString(text, "$1$2")
+}
+
+// smartQuoteReplacer folds curly quotes and dashes back to their plain
+// ASCII equivalents, so downstream tokenization doesn't have to treat
+// “word” and "word" as different tokens.
+var smartQuoteReplacer = strings.NewReplacer(
+	"‘", "'", // left single quotation mark
+	"’", "'", // right single quotation mark
+	"“", "\"", // left double quotation mark
+	"”", "\"", // right double quotation mark
+	"–", "-", // en dash
+	"—", "-", // em dash
+)
+
+// smartQuoteStep folds typographic quotes/dashes to their ASCII forms.
+type smartQuoteStep struct{}
+
+func (smartQuoteStep) Name() string { return "smart_quotes" }
+func (smartQuoteStep) Apply(text string) string {
+	return smartQuoteReplacer.Replace(text)
+}
+
+// zeroWidthRegex matches zero-width and byte-order-mark characters that
+// sometimes survive copy-paste from web pages or certain PDF extractors,
+// and are otherwise invisible in any downstream diff or log.
+var zeroWidthRegex = regexp.MustCompile(`[\x{200B}-\x{200D}\x{FEFF}]`)
+
+// zeroWidthStep removes zero-width characters.
+type zeroWidthStep struct{}
+
+func (zeroWidthStep) Name() string { return "zero_width" }
+func (zeroWidthStep) Apply(text string) string {
+	return zeroWidthRegex.ReplaceAllString(text, "")
+}
@@ -1,7 +1,6 @@
 package tika
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
@@ -11,6 +10,9 @@ import (
 	"mime/multipart"
 	"net/http"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,52 +27,119 @@ type Client struct {
 	cleaner    *text.Cleaner
 }
 
-type JSONMetadata struct {
-	KeyCount     int                    `json:"keyCount"`
-	MaxDepth     int                    `json:"maxDepth"`
-	ArrayCount   int                    `json:"arrayCount"`
-	ObjectCount  int                    `json:"objectCount"`
-	DataTypes    map[string]int         `json:"dataTypes"`
-	TopLevelKeys []string               `json:"topLevelKeys"`
-	Structure    map[string]interface{} `json:"structure"`
+// ExtractProgress reports upload progress for a single extraction attempt:
+// bytesSent is the cumulative count streamed so far, bytesTotal is the
+// file's total size (0 if the multipart header didn't carry one).
+type ExtractProgress func(bytesSent, bytesTotal int64)
+
+// progressReader wraps an io.Reader, invoking onProgress after every Read so
+// a long upload can be tracked without buffering the whole file to measure it.
+type progressReader struct {
+	io.Reader
+	sent       int64
+	total      int64
+	onProgress ExtractProgress
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.onProgress(r.sent, r.total)
+	}
+	return n, err
+}
+
+// tikaAttempt is one HTTP attempt at extraction. A non-zero retryAfter
+// signals ExtractWithMetadata's retry loop to honor the server's
+// Retry-After header instead of its fixed RetryDelay.
+type tikaAttempt func(ctx context.Context, file multipart.File, header *multipart.FileHeader, onProgress ExtractProgress) (result *models.ExtractedContent, retryAfter time.Duration, err error)
+
+// jsonChunk is one JSONPath-addressed slice of a JSON document, carrying the
+// text jsonToText would have produced for just that slice.
+type jsonChunk struct {
+	path string
+	text string
 }
 
 func NewClient(cfg *config.TikaConfig) *Client {
+	// An empty pipeline always resolves to text's built-in default step
+	// sequence, so this can never actually error.
+	cleaner, _ := text.NewCleaner(true, nil)
+
 	return &Client{
 		config: cfg,
 		httpClient: &http.Client{
 			Timeout: cfg.Timeout.Duration,
 		},
-		cleaner: text.NewCleaner(true),
+		cleaner: cleaner,
 	}
 }
 
-func (c *Client) ExtractWithMetadata(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*models.ExtractedContent, error) {
+// ExtractWithMetadata extracts text and metadata from file. onProgress is
+// optional (pass nothing, or a single ExtractProgress) and is invoked as the
+// file streams to Tika, for long uploads that want to report progress.
+func (c *Client) ExtractWithMetadata(ctx context.Context, file multipart.File, header *multipart.FileHeader, onProgress ...ExtractProgress) (*models.ExtractedContent, error) {
 	if header != nil && strings.ToLower(filepath.Ext(header.Filename)) == ".json" {
 		return c.extractJSONContent(ctx, file, header)
 	}
 
+	var progress ExtractProgress
+	if len(onProgress) > 0 {
+		progress = onProgress[0]
+	}
+
+	attempt := c.extractWithMetadataAttempt
+	if c.config.Recursive {
+		attempt = c.extractRecursiveAttempt
+	}
+
 	var lastErr error
-	for attempt := 0; attempt <= c.config.RetryAttempts; attempt++ {
-		if attempt > 0 {
+	var retryAfter time.Duration
+	for i := 0; i <= c.config.RetryAttempts; i++ {
+		if i > 0 {
+			wait := c.config.RetryDelay.Duration
+			if retryAfter > 0 {
+				wait = retryAfter
+			}
 			select {
 			case <-ctx.Done():
 				return nil, ctx.Err()
-			case <-time.After(c.config.RetryDelay.Duration):
+			case <-time.After(wait):
 			}
 		}
 
-		result, err := c.extractWithMetadataAttempt(ctx, file, header)
+		attemptCtx, cancel := context.WithTimeout(ctx, c.config.Timeout.Duration)
+		result, after, err := attempt(attemptCtx, file, header, progress)
+		cancel()
 		if err == nil {
 			return result, nil
 		}
 
 		lastErr = err
+		retryAfter = after
 	}
 
 	return nil, fmt.Errorf("failed after %d attempts: %w", c.config.RetryAttempts+1, lastErr)
 }
 
+// parseRetryAfter reads a 429/503 response's Retry-After header (either
+// delta-seconds or an HTTP-date, per RFC 7231 §7.1.3) and returns the wait
+// duration it names, or zero if the header is absent or unparseable.
+func parseRetryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
+
 func (c *Client) extractJSONContent(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*models.ExtractedContent, error) {
 	file.Seek(0, 0)
 
@@ -84,8 +153,8 @@ func (c *Client) extractJSONContent(ctx context.Context, file multipart.File, he
 		return nil, fmt.Errorf("failed to parse JSON content: %w", err)
 	}
 
-	jsonMeta := c.analyzeJSON(jsonData)
-	
+	schema := c.inferJSONSchema(jsonData)
+
 	cleanText := c.jsonToText(jsonData, 0)
 	wordCount := c.cleaner.CountWords(cleanText)
 	checksum := generateChecksum(fileContent)
@@ -93,7 +162,7 @@ func (c *Client) extractJSONContent(ctx context.Context, file multipart.File, he
 	title := ""
 	author := ""
 	language := "json"
-	
+
 	if obj, ok := jsonData.(map[string]interface{}); ok {
 		if titleVal, exists := obj["title"]; exists {
 			if titleStr, ok := titleVal.(string); ok {
@@ -118,14 +187,22 @@ func (c *Client) extractJSONContent(ctx context.Context, file multipart.File, he
 	}
 
 	metadata := map[string]interface{}{
-		"content-type":     "application/json",
-		"json-key-count":   jsonMeta.KeyCount,
-		"json-max-depth":   jsonMeta.MaxDepth,
-		"json-array-count": jsonMeta.ArrayCount,
-		"json-object-count": jsonMeta.ObjectCount,
-		"json-data-types":  jsonMeta.DataTypes,
-		"json-top-keys":    jsonMeta.TopLevelKeys,
-		"json-structure":   jsonMeta.Structure,
+		"content-type": "application/json",
+		"json-schema":  schema,
+	}
+
+	var children []*models.ExtractedContent
+	for _, chunk := range c.jsonChunks(jsonData) {
+		children = append(children, &models.ExtractedContent{
+			Metadata: models.DocumentMetadata{
+				ContentType: "application/json",
+				ExtraMetadata: map[string]interface{}{
+					"json_path": chunk.path,
+				},
+			},
+			CleanText: chunk.text,
+			WordCount: c.cleaner.CountWords(chunk.text),
+		})
 	}
 
 	return &models.ExtractedContent{
@@ -134,7 +211,7 @@ func (c *Client) extractJSONContent(ctx context.Context, file multipart.File, he
 			Filepath:      filepath,
 			FileSize:      int64(len(fileContent)),
 			Author:        author,
-			CreationDate:  nil, 
+			CreationDate:  nil,
 			Language:      language,
 			ContentType:   "application/json",
 			Checksum:      checksum,
@@ -143,92 +220,291 @@ func (c *Client) extractJSONContent(ctx context.Context, file multipart.File, he
 		},
 		CleanText: cleanText,
 		WordCount: wordCount,
-		PageCount: 1, 
+		PageCount: 1,
+		Children:  children,
 	}, nil
 }
 
-func (c *Client) analyzeJSON(data interface{}) *JSONMetadata {
-	meta := &JSONMetadata{
-		DataTypes: make(map[string]int),
-		Structure: make(map[string]interface{}),
+// inferJSONSchema walks data and builds a Draft 2020-12-style JSON Schema
+// describing it: object types get properties/required, arrays get an items
+// schema unified across every element (unifySchemas), and scalars map to
+// their JSON Schema type.
+func (c *Client) inferJSONSchema(data interface{}) map[string]interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		return c.objectSchema(v)
+	case []interface{}:
+		schema := map[string]interface{}{"type": "array"}
+		if len(v) > 0 {
+			schema["items"] = c.unifySchemas(v)
+		}
+		return schema
+	case string:
+		return map[string]interface{}{"type": "string"}
+	case float64:
+		return map[string]interface{}{"type": "number"}
+	case bool:
+		return map[string]interface{}{"type": "boolean"}
+	default:
+		return map[string]interface{}{"type": "null"}
+	}
+}
+
+func (c *Client) objectSchema(v map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{}, len(v))
+	required := make([]string, 0, len(v))
+	for key, value := range v {
+		properties[key] = c.inferJSONSchema(value)
+		required = append(required, key)
 	}
+	sort.Strings(required)
 
-	c.analyzeJSONRecursive(data, meta, 0, "")
-	return meta
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
 }
 
-func (c *Client) analyzeJSONRecursive(data interface{}, meta *JSONMetadata, depth int, path string) {
-	if depth > meta.MaxDepth {
-		meta.MaxDepth = depth
+// unifySchemas infers one schema covering every element of values, for a
+// JSON Schema "items" keyword. Sibling objects are unified key-by-key
+// (unifyObjectSchemas); sibling arrays are flattened and recursed into;
+// everything else falls back to an enum schema when the distinct values are
+// few enough to enumerate (enumSchema), or to the first element's schema
+// when every element already agrees.
+func (c *Client) unifySchemas(values []interface{}) map[string]interface{} {
+	allObjects, allArrays := true, true
+	for _, v := range values {
+		if _, ok := v.(map[string]interface{}); !ok {
+			allObjects = false
+		}
+		if _, ok := v.([]interface{}); !ok {
+			allArrays = false
+		}
 	}
 
-	switch v := data.(type) {
-	case map[string]interface{}:
-		meta.ObjectCount++
-		meta.DataTypes["object"]++
-		
-		if depth == 0 {
-			for key := range v {
-				meta.TopLevelKeys = append(meta.TopLevelKeys, key)
-				meta.KeyCount++
-			}
+	if allObjects {
+		objects := make([]map[string]interface{}, len(values))
+		for i, v := range values {
+			objects[i] = v.(map[string]interface{})
 		}
+		return c.unifyObjectSchemas(objects)
+	}
 
-		if depth <= 2 {
-			structObj := make(map[string]interface{})
-			for key, value := range v {
-				structObj[key] = c.getValueType(value)
-			}
-			if path == "" {
-				meta.Structure = structObj
-			}
+	if allArrays {
+		var flattened []interface{}
+		for _, v := range values {
+			flattened = append(flattened, v.([]interface{})...)
 		}
+		schema := map[string]interface{}{"type": "array"}
+		if len(flattened) > 0 {
+			schema["items"] = c.unifySchemas(flattened)
+		}
+		return schema
+	}
 
-		for key, value := range v {
-			newPath := key
-			if path != "" {
-				newPath = path + "." + key
+	if schema, ok := c.enumSchema(values); ok {
+		return schema
+	}
+
+	first := c.inferJSONSchema(values[0])
+	for _, v := range values[1:] {
+		if !reflect.DeepEqual(c.inferJSONSchema(v), first) {
+			return map[string]interface{}{}
+		}
+	}
+	return first
+}
+
+// unifyObjectSchemas merges a set of sibling objects (typically the elements
+// of one JSON array) into one object schema: each key's schema is unified
+// across every sibling that has it, and a key is "required" only if every
+// sibling object has it.
+func (c *Client) unifyObjectSchemas(objects []map[string]interface{}) map[string]interface{} {
+	valuesByKey := make(map[string][]interface{})
+	presentCount := make(map[string]int)
+	for _, obj := range objects {
+		for key, value := range obj {
+			valuesByKey[key] = append(valuesByKey[key], value)
+			presentCount[key]++
+		}
+	}
+
+	properties := make(map[string]interface{}, len(valuesByKey))
+	var required []string
+	for key, values := range valuesByKey {
+		properties[key] = c.unifySchemas(values)
+		if presentCount[key] == len(objects) {
+			required = append(required, key)
+		}
+	}
+	sort.Strings(required)
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// enumSchema detects that every value is a string and there are at most
+// JSONSchemaMaxEnumCandidates distinct ones, representing that as an enum
+// instead of a bare "type": "string" so low-cardinality fields (status
+// codes, categories, log levels) stay filterable downstream.
+func (c *Client) enumSchema(values []interface{}) (map[string]interface{}, bool) {
+	max := c.config.JSONSchemaMaxEnumCandidates
+	if max <= 0 {
+		max = 20
+	}
+
+	seen := make(map[string]bool)
+	var distinct []string
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		if !seen[s] {
+			seen[s] = true
+			distinct = append(distinct, s)
+			if len(distinct) > max {
+				return nil, false
 			}
-			c.analyzeJSONRecursive(value, meta, depth+1, newPath)
 		}
+	}
 
-	case []interface{}:
-		meta.ArrayCount++
-		meta.DataTypes["array"]++
-		
-		for i, item := range v {
-			newPath := fmt.Sprintf("%s[%d]", path, i)
-			c.analyzeJSONRecursive(item, meta, depth+1, newPath)
+	sort.Strings(distinct)
+	return map[string]interface{}{
+		"type": "string",
+		"enum": distinct,
+	}, true
+}
+
+// jsonChunks splits data into one chunk per JSONPath: config.JSONPointers
+// when set (an override for known shapes auto-detection can't infer, like
+// OpenAPI specs or chat transcripts), otherwise every auto-detected leaf
+// array (detectLeafArrayPaths).
+func (c *Client) jsonChunks(data interface{}) []jsonChunk {
+	paths := c.config.JSONPointers
+	if len(paths) == 0 {
+		paths = detectLeafArrayPaths(data)
+	}
+
+	chunks := make([]jsonChunk, 0, len(paths))
+	for _, path := range paths {
+		matches := resolveSegments(data, jsonPathSegments(path))
+		if len(matches) == 0 {
+			continue
+		}
+		var builder strings.Builder
+		for _, match := range matches {
+			c.jsonToTextRecursive(match, &builder, 0, "")
 		}
+		chunks = append(chunks, jsonChunk{path: path, text: c.cleaner.Clean(builder.String())})
+	}
+	return chunks
+}
 
-	case string:
-		meta.DataTypes["string"]++
-	case float64:
-		meta.DataTypes["number"]++
-	case bool:
-		meta.DataTypes["boolean"]++
-	case nil:
-		meta.DataTypes["null"]++
+// jsonPathSegments turns a JSONPath-ish string ("$.messages[*].content",
+// "items[*]") into the walk resolveSegments expects: a leading "$" is
+// stripped, "[*]" becomes its own wildcard segment, and the rest splits on
+// ".".
+func jsonPathSegments(path string) []string {
+	path = strings.TrimPrefix(path, "$")
+	path = strings.ReplaceAll(path, "[*]", ".*")
+	path = strings.Trim(path, "./")
+	if path == "" {
+		return nil
 	}
+	return strings.Split(path, ".")
 }
 
-func (c *Client) getValueType(value interface{}) string {
-	switch value.(type) {
-	case map[string]interface{}:
-		return "object"
-	case []interface{}:
-		return "array"
-	case string:
-		return "string"
-	case float64:
-		return "number"
-	case bool:
-		return "boolean"
-	case nil:
-		return "null"
-	default:
-		return "unknown"
+// resolveSegments walks data by segments, descending into map keys and, for
+// a "*" segment, every element of an array, and returns every leaf value the
+// path matches.
+func resolveSegments(data interface{}, segments []string) []interface{} {
+	if len(segments) == 0 {
+		return []interface{}{data}
+	}
+
+	segment, rest := segments[0], segments[1:]
+	if segment == "*" {
+		arr, ok := data.([]interface{})
+		if !ok {
+			return nil
+		}
+		var matches []interface{}
+		for _, item := range arr {
+			matches = append(matches, resolveSegments(item, rest)...)
+		}
+		return matches
+	}
+
+	obj, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	value, ok := obj[segment]
+	if !ok {
+		return nil
+	}
+	return resolveSegments(value, rest)
+}
+
+// detectLeafArrayPaths walks data looking for "leaf arrays": arrays whose
+// elements are all scalars or flat objects (no nested arrays or objects). A
+// generic heuristic that covers common shapes - OpenAPI parameter lists,
+// JSONL-style event arrays, chat transcript message lists - without any
+// shape-specific logic.
+func detectLeafArrayPaths(data interface{}) []string {
+	var paths []string
+	var walk func(value interface{}, path string)
+	walk = func(value interface{}, path string) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, child := range v {
+				childPath := key
+				if path != "" {
+					childPath = path + "." + key
+				}
+				walk(child, childPath)
+			}
+		case []interface{}:
+			if isLeafArray(v) {
+				paths = append(paths, path+"[*]")
+				return
+			}
+			for _, item := range v {
+				walk(item, path+"[*]")
+			}
+		}
 	}
+	walk(data, "")
+	sort.Strings(paths)
+	return paths
+}
+
+// isLeafArray reports whether items is non-empty and none of its object
+// elements has a nested array/object as one of its own values. Scalar
+// elements, and elements that are themselves arrays, are accepted as they
+// are.
+func isLeafArray(items []interface{}) bool {
+	if len(items) == 0 {
+		return false
+	}
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, value := range obj {
+			switch value.(type) {
+			case map[string]interface{}, []interface{}:
+				return false
+			}
+		}
+	}
+	return true
 }
 
 func (c *Client) jsonToText(data interface{}, depth int) string {
@@ -280,125 +556,202 @@ func (c *Client) jsonToTextRecursive(data interface{}, builder *strings.Builder,
 	}
 }
 
-func (c *Client) extractWithMetadataAttempt(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*models.ExtractedContent, error) {
-	file.Seek(0, 0)
-
-	fileContent, err := io.ReadAll(file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file content: %w", err)
+// callRmeta streams file (rewound first, so a retry replays cleanly without
+// re-buffering) straight into the request body of a single PUT against
+// Tika's /rmeta/text endpoint, which returns text and metadata together.
+// The body is hashed as it's streamed, so the checksum comes for free
+// without a second pass over the file. A non-zero retryAfter is only ever
+// returned alongside a non-nil error, from a 429/503 response.
+func (c *Client) callRmeta(ctx context.Context, file multipart.File, header *multipart.FileHeader, onProgress ExtractProgress) ([]map[string]interface{}, string, time.Duration, error) {
+	if _, err := file.Seek(0, 0); err != nil {
+		return nil, "", 0, fmt.Errorf("failed to rewind file: %w", err)
 	}
 
-	tikaURL := fmt.Sprintf("%s/tika", c.config.ServerURL)
-	req, err := http.NewRequestWithContext(ctx, "PUT", tikaURL, bytes.NewReader(fileContent))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	contentType := detectContentType(header)
+
+	var total int64
+	if header != nil {
+		total = header.Size
 	}
 
-	contentType := "application/octet-stream"
-	if header != nil && header.Filename != "" {
-		switch filepath.Ext(header.Filename) {
-		case ".pdf":
-			contentType = "application/pdf"
-		case ".docx":
-			contentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
-		case ".doc":
-			contentType = "application/msword"
-		case ".txt":
-			contentType = "text/plain"
-		case ".html":
-			contentType = "text/html"
-		case ".rtf":
-			contentType = "application/rtf"
-		case ".odt":
-			contentType = "application/vnd.oasis.opendocument.text"
-		case ".json":
-			contentType = "application/json"
-		}
+	var body io.Reader = file
+	if onProgress != nil {
+		body = &progressReader{Reader: body, total: total, onProgress: onProgress}
 	}
+	hasher := sha256.New()
+	body = io.TeeReader(body, hasher)
 
+	rmetaURL := fmt.Sprintf("%s/rmeta/text", c.config.ServerURL)
+	req, err := http.NewRequestWithContext(ctx, "PUT", rmetaURL, body)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	if total > 0 {
+		req.ContentLength = total
+	}
 	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("Accept", "text/plain")
+	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute tika request: %w", err)
+		return nil, "", 0, fmt.Errorf("failed to execute tika rmeta request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, "", parseRetryAfter(resp), fmt.Errorf("tika rmeta server returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("tika server returned status %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, "", 0, fmt.Errorf("tika rmeta server returned status %d: %s", resp.StatusCode, string(bodyBytes))
 	}
 
-	extractedText, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	var resources []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		return nil, "", 0, fmt.Errorf("failed to decode rmeta response: %w", err)
 	}
+	if len(resources) == 0 {
+		return nil, "", 0, fmt.Errorf("tika rmeta response contained no resources")
+	}
+
+	return resources, hex.EncodeToString(hasher.Sum(nil)), 0, nil
+}
 
-	metaURL := fmt.Sprintf("%s/meta", c.config.ServerURL)
-	metaReq, err := http.NewRequestWithContext(ctx, "PUT", metaURL, bytes.NewReader(fileContent))
+func (c *Client) extractWithMetadataAttempt(ctx context.Context, file multipart.File, header *multipart.FileHeader, onProgress ExtractProgress) (*models.ExtractedContent, time.Duration, error) {
+	resources, checksum, retryAfter, err := c.callRmeta(ctx, file, header, onProgress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create metadata request: %w", err)
+		return nil, retryAfter, err
+	}
+
+	contentType := detectContentType(header)
+	filepath := ""
+	if header != nil && header.Filename != "" {
+		filepath = header.Filename
+	}
+	var fileSize int64
+	if header != nil {
+		fileSize = header.Size
 	}
-	metaReq.Header.Set("Content-Type", contentType)
-	metaReq.Header.Set("Accept", "application/json")
 
-	metaResp, err := c.httpClient.Do(metaReq)
+	return c.resourceToExtractedContent(resources[0], filepath, contentType, checksum, fileSize), 0, nil
+}
+
+// extractRecursiveAttempt streams the payload through callRmeta and builds a
+// tree from the resulting resources: element 0 is the container document,
+// the rest are embedded resources identified by their
+// X-TIKA:embedded_resource_path. Resources nested deeper than
+// MaxEmbeddedDepth are left out of the tree (their text is not cleaned or
+// counted); Tika itself still unpacks every level, and the whole /rmeta
+// response is decoded before they are skipped.
+func (c *Client) extractRecursiveAttempt(ctx context.Context, file multipart.File, header *multipart.FileHeader, onProgress ExtractProgress) (*models.ExtractedContent, time.Duration, error) {
+	resources, checksum, retryAfter, err := c.callRmeta(ctx, file, header, onProgress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute metadata request: %w", err)
+		return nil, retryAfter, err
 	}
-	defer metaResp.Body.Close()
 
-	if metaResp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(metaResp.Body)
-		return nil, fmt.Errorf("tika metadata request returned status %d: %s", metaResp.StatusCode, string(bodyBytes))
+	contentType := detectContentType(header)
+	rootPath := ""
+	if header != nil && header.Filename != "" {
+		rootPath = header.Filename
+	}
+	var fileSize int64
+	if header != nil {
+		fileSize = header.Size
 	}
 
-	var metadata map[string]interface{}
-	if err := json.NewDecoder(metaResp.Body).Decode(&metadata); err != nil {
-		return nil, fmt.Errorf("failed to decode metadata response: %w", err)
+	root := c.resourceToExtractedContent(resources[0], rootPath, contentType, checksum, fileSize)
+
+	for _, resource := range resources[1:] {
+		resourcePath, _ := resource["X-TIKA:embedded_resource_path"].(string)
+		if embeddedDepth(resourcePath) > c.config.MaxEmbeddedDepth {
+			continue
+		}
+
+		childContentType := contentType
+		if ct, ok := resource["Content-Type"].(string); ok && ct != "" {
+			childContentType = ct
+		}
+
+		// Embedded resources don't have their original raw bytes
+		// available (the rmeta response only exposes their extracted
+		// text), so their checksum is taken over that text instead.
+		rawText, _ := resource["X-TIKA:content"].(string)
+		childChecksum := generateChecksum([]byte(rawText))
+
+		root.Children = append(root.Children, c.resourceToExtractedContent(resource, resourcePath, childContentType, childChecksum, int64(len(rawText))))
 	}
 
-	rawText := string(extractedText)
+	return root, 0, nil
+}
+
+// resourceToExtractedContent builds an ExtractedContent from one element of
+// a /rmeta/text response.
+func (c *Client) resourceToExtractedContent(resource map[string]interface{}, resourcePath, contentType, checksum string, fileSize int64) *models.ExtractedContent {
+	rawText, _ := resource["X-TIKA:content"].(string)
 	cleanText := c.cleaner.Clean(rawText)
 	wordCount := c.cleaner.CountWords(cleanText)
-	pageCount := extractPageCount(metadata)
-
-	checksum := generateChecksum(fileContent)
 
-	title := extractStringFromMetadata(metadata, []string{"dc:title", "title", "Title"})
-	author := extractStringFromMetadata(metadata, []string{"dc:creator", "Author", "meta:author", "creator"})
-	language := extractStringFromMetadata(metadata, []string{"dc:language", "language", "Content-Language"})
-
-	creationDate := extractDateFromMetadata(metadata, []string{
+	title := extractStringFromMetadata(resource, []string{"dc:title", "title", "Title", "resourceName"})
+	author := extractStringFromMetadata(resource, []string{"dc:creator", "Author", "meta:author", "creator"})
+	language := extractStringFromMetadata(resource, []string{"dc:language", "language", "Content-Language"})
+	creationDate := extractDateFromMetadata(resource, []string{
 		"dcterms:created", "meta:creation-date", "Creation-Date",
 		"dc:created", "created", "dcterms:modified",
 	})
 
-	filename := ""
-	filepath := ""
-	if header != nil && header.Filename != "" {
-		filename = header.Filename
-		filepath = filename
-	}
-
 	return &models.ExtractedContent{
 		Metadata: models.DocumentMetadata{
 			Title:         title,
-			Filepath:      filepath,
-			FileSize:      int64(len(fileContent)),
+			Filepath:      resourcePath,
+			FileSize:      fileSize,
 			Author:        author,
 			CreationDate:  creationDate,
 			Language:      language,
 			ContentType:   contentType,
 			Checksum:      checksum,
 			ProcessedAt:   time.Now(),
-			ExtraMetadata: metadata,
+			ExtraMetadata: resource,
 		},
 		CleanText: cleanText,
 		WordCount: wordCount,
-		PageCount: pageCount,
-	}, nil
+		PageCount: extractPageCount(resource),
+	}
+}
+
+// embeddedDepth counts the nesting level of a /rmeta embedded_resource_path
+// such as "/embedded1.docx/embedded2.jpg" (depth 2). The container document
+// itself has no path and is depth 0.
+func embeddedDepth(resourcePath string) int {
+	if resourcePath == "" {
+		return 0
+	}
+	return strings.Count(strings.Trim(resourcePath, "/"), "/") + 1
+}
+
+func detectContentType(header *multipart.FileHeader) string {
+	contentType := "application/octet-stream"
+	if header != nil && header.Filename != "" {
+		switch filepath.Ext(header.Filename) {
+		case ".pdf":
+			contentType = "application/pdf"
+		case ".docx":
+			contentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+		case ".doc":
+			contentType = "application/msword"
+		case ".txt":
+			contentType = "text/plain"
+		case ".html":
+			contentType = "text/html"
+		case ".rtf":
+			contentType = "application/rtf"
+		case ".odt":
+			contentType = "application/vnd.oasis.opendocument.text"
+		case ".json":
+			contentType = "application/json"
+		}
+	}
+	return contentType
 }
 
 func generateChecksum(content []byte) string {
@@ -528,4 +881,4 @@ func parseInt(s string) int {
 		}
 	}
 	return result
-}
\ No newline at end of file
+}
@@ -0,0 +1,143 @@
+// Package transfer implements the peer hand-off used during graceful
+// shutdown: an instance that is draining serializes any documents still
+// mid-analysis (the chunks already analyzed, the original text, and a
+// resumption cursor) and ships them to a peer over HTTP+JSON instead of
+// discarding that work on a rolling restart.
+//
+// transfer.proto documents the intended wire shape for a generated gRPC
+// service; protoc isn't available in this pipeline (the same constraint
+// chunking.ProtobufSerializer works around), so Client and Server exchange
+// plain JSON matching that shape instead of depending on generated types.
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// TransferChunksRequest carries an in-flight document's already-analyzed
+// chunks and resumption cursor to a peer instance. Field names and shape
+// mirror transfer.proto's TransferChunksRequest message.
+type TransferChunksRequest struct {
+	SourceID   string `json:"source_id"`
+	SourceType string `json:"source_type"`
+	Title      string `json:"title"`
+	Filepath   string `json:"filepath"`
+
+	OriginalText string `json:"original_text"`
+
+	// CompletedChunksJSON is JSON-encoded []chunking.ChunkPayload for the
+	// chunks already analyzed, so the receiving instance can resume
+	// instead of re-running Gemini on them.
+	CompletedChunksJSON []byte `json:"completed_chunks_json"`
+
+	ResumptionCursor int32 `json:"resumption_cursor"`
+	TotalChunks      int32 `json:"total_chunks"`
+}
+
+// TransferChunksResponse mirrors transfer.proto's TransferChunksResponse
+// message.
+type TransferChunksResponse struct {
+	Accepted bool   `json:"accepted"`
+	Message  string `json:"message"`
+}
+
+// transferPath is the path Client posts to and Server handles.
+const transferPath = "/transfer/chunks"
+
+// Client hands in-flight work off to a peer instance's transfer endpoint.
+type Client struct {
+	addr       string
+	httpClient *http.Client
+}
+
+func NewClient(addr string) (*Client, error) {
+	return &Client{
+		addr:       addr,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (c *Client) TransferChunks(ctx context.Context, req *TransferChunksRequest) (*TransferChunksResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transfer request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+c.addr+transferPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build transfer request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach transfer peer %s: %w", c.addr, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("transfer peer %s returned status %d: %s", c.addr, resp.StatusCode, string(respBody))
+	}
+
+	var transferResp TransferChunksResponse
+	if err := json.NewDecoder(resp.Body).Decode(&transferResp); err != nil {
+		return nil, fmt.Errorf("failed to decode transfer response from %s: %w", c.addr, err)
+	}
+	return &transferResp, nil
+}
+
+func (c *Client) Close() error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// ResumeHandler is invoked on the receiving side for each handed-off
+// document. Implementations are expected to unmarshal CompletedChunksJSON
+// back into []chunking.ChunkPayload and continue analysis from
+// ResumptionCursor rather than re-running Tika extraction and re-analyzing
+// chunks that already completed.
+type ResumeHandler func(ctx context.Context, req *TransferChunksRequest) error
+
+// Server handles a peer's handed-off documents. It implements http.Handler
+// so it can be mounted at transferPath on the receiving instance's server.
+type Server struct {
+	onResume ResumeHandler
+}
+
+func NewServer(onResume ResumeHandler) *Server {
+	return &Server{onResume: onResume}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req TransferChunksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid transfer request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	resp := s.transferChunks(r.Context(), &req)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) transferChunks(ctx context.Context, req *TransferChunksRequest) *TransferChunksResponse {
+	if err := s.onResume(ctx, req); err != nil {
+		return &TransferChunksResponse{
+			Accepted: false,
+			Message:  fmt.Sprintf("failed to resume transferred document: %v", err),
+		}
+	}
+
+	return &TransferChunksResponse{
+		Accepted: true,
+		Message:  "resuming from cursor",
+	}
+}
@@ -0,0 +1,270 @@
+// Package uploads implements tus-protocol-style resumable uploads: a client
+// creates an upload session with a declared total size, then PATCHes chunks
+// in at an expected offset until the session is complete. Bytes are streamed
+// straight into a storage.Manager temp file, with a SHA-256 hash computed
+// incrementally as they arrive so the completed upload's content hash is
+// ready without a second read pass.
+package uploads
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/NEMYSESx/Orbit/apps/ingestion-pipeline/internal/storage"
+	"github.com/google/uuid"
+)
+
+// ErrOffsetMismatch is returned by Session.Write when the caller's declared
+// offset doesn't match the session's actual offset, mirroring tus's
+// "Conflict" semantics for out-of-order or duplicate PATCH requests.
+var ErrOffsetMismatch = errors.New("upload offset mismatch")
+
+// ErrSessionNotFound is returned when an upload ID has no matching session,
+// either because it was never created or it has already been finalized.
+var ErrSessionNotFound = errors.New("upload session not found")
+
+// ErrTooManySessions is returned by Manager.Create when maxSessions
+// concurrently open upload sessions are already tracked.
+var ErrTooManySessions = errors.New("too many concurrent upload sessions")
+
+// Session tracks one in-progress resumable upload.
+type Session struct {
+	ID          string
+	Filename    string
+	ContentType string
+	TotalSize   int64
+	CreatedAt   time.Time
+
+	mu     sync.Mutex
+	file   *os.File
+	offset int64
+	sum    hash.Hash
+}
+
+// Manager creates and tracks Sessions in memory, backing each one with a
+// storage.Manager temp file.
+type Manager struct {
+	storage *storage.Manager
+
+	// maxSessions caps how many sessions can be tracked at once, so a burst
+	// of POST /receive/uploads can't open unbounded temp files before Reaper
+	// gets a chance to run. Zero means no cap.
+	maxSessions int
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+// NewManager builds a Manager whose sessions stream into storageManager's
+// temp directory, rejecting new sessions once maxSessions are already
+// tracked (zero means no cap).
+func NewManager(storageManager *storage.Manager, maxSessions int) *Manager {
+	return &Manager{
+		storage:     storageManager,
+		maxSessions: maxSessions,
+		sessions:    make(map[string]*Session),
+	}
+}
+
+// Create starts a new upload session for a file of the given declared size,
+// returning the session handle the caller should reply with (e.g. as a
+// Location header). If the Manager is already at its session cap, the temp
+// file it just opened is closed and removed and ErrTooManySessions is
+// returned.
+func (m *Manager) Create(filename, contentType string, totalSize int64) (*Session, error) {
+	file, err := m.storage.CreateTempFile("upload")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file for upload: %w", err)
+	}
+
+	session := &Session{
+		ID:          uuid.NewString(),
+		Filename:    filename,
+		ContentType: contentType,
+		TotalSize:   totalSize,
+		CreatedAt:   time.Now(),
+		file:        file,
+		sum:         sha256.New(),
+	}
+
+	m.mu.Lock()
+	if m.maxSessions > 0 && len(m.sessions) >= m.maxSessions {
+		m.mu.Unlock()
+		file.Close()
+		os.Remove(file.Name())
+		return nil, ErrTooManySessions
+	}
+	m.sessions[session.ID] = session
+	m.mu.Unlock()
+
+	return session, nil
+}
+
+// reapExpired closes and evicts every session whose CreatedAt is older than
+// ttl, so an upload that's never PATCHed again doesn't hold its temp file
+// and map entry open for the life of the process. Returns the number of
+// sessions evicted.
+func (m *Manager) reapExpired(ttl time.Duration) int {
+	cutoff := time.Now().Add(-ttl)
+
+	m.mu.Lock()
+	var expired []*Session
+	for id, s := range m.sessions {
+		if s.CreatedAt.Before(cutoff) {
+			expired = append(expired, s)
+			delete(m.sessions, id)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, s := range expired {
+		if err := s.Close(); err != nil {
+			log.Printf("failed to close expired upload session %s: %v", s.ID, err)
+		}
+	}
+
+	return len(expired)
+}
+
+// Get returns the session for id, if one exists.
+func (m *Manager) Get(id string) (*Session, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	return s, ok
+}
+
+// Delete removes a session from tracking. Callers must Close the session's
+// temp file first unless Finalize already took ownership of it.
+func (m *Manager) Delete(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+}
+
+// Write appends data to the session at expectedOffset, the offset the
+// client believes the upload is currently at. A mismatch returns
+// ErrOffsetMismatch without writing anything, so the caller can reply 409
+// Conflict and the client can recover with a HEAD request. Returns the
+// session's new offset.
+func (s *Session) Write(expectedOffset int64, r io.Reader) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if expectedOffset != s.offset {
+		return s.offset, ErrOffsetMismatch
+	}
+
+	n, err := io.Copy(io.MultiWriter(s.file, s.sum), r)
+	s.offset += n
+	if err != nil {
+		return s.offset, fmt.Errorf("failed to write upload chunk: %w", err)
+	}
+
+	return s.offset, nil
+}
+
+// Offset returns the session's current byte offset.
+func (s *Session) Offset() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offset
+}
+
+// Complete reports whether the upload has received its full declared size.
+func (s *Session) Complete() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.TotalSize > 0 && s.offset >= s.TotalSize
+}
+
+// ContentHash returns the hex-encoded SHA-256 of the bytes written so far.
+func (s *Session) ContentHash() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return hex.EncodeToString(s.sum.Sum(nil))
+}
+
+// Finalize rewinds the session's backing temp file so it can be read back
+// as a completed upload, returning it alongside a synthetic
+// *multipart.FileHeader shaped the way processor.DocumentProcessor.
+// ProcessDocument expects from an in-memory multipart upload. The caller
+// becomes responsible for closing the returned file.
+func (s *Session) Finalize() (*os.File, *multipart.FileHeader, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, fmt.Errorf("failed to rewind completed upload: %w", err)
+	}
+
+	header := &multipart.FileHeader{
+		Filename: s.Filename,
+		Header:   textproto.MIMEHeader{"Content-Type": []string{s.ContentType}},
+		Size:     s.TotalSize,
+	}
+
+	return s.file, header, nil
+}
+
+// Close releases the session's backing temp file without finalizing it, for
+// abandoned or errored sessions.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// Reaper periodically evicts upload sessions that have sat open longer than
+// ttl without being finalized, so a client that creates a session and never
+// PATCHes it doesn't leak a temp file and a map entry for the life of the
+// process.
+type Reaper struct {
+	manager  *Manager
+	ttl      time.Duration
+	interval time.Duration
+	stop     chan struct{}
+}
+
+// NewReaper builds a Reaper that sweeps manager every interval, evicting
+// sessions older than ttl.
+func NewReaper(manager *Manager, ttl, interval time.Duration) *Reaper {
+	return &Reaper{
+		manager:  manager,
+		ttl:      ttl,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Run blocks, sweeping on every tick until Stop is called.
+func (r *Reaper) Run() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if n := r.manager.reapExpired(r.ttl); n > 0 {
+				log.Printf("upload reaper: evicted %d expired session(s)", n)
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Stop ends the sweep loop after its current pass, if any, finishes.
+func (r *Reaper) Stop() {
+	close(r.stop)
+}
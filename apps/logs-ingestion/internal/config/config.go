@@ -6,9 +6,94 @@ import (
 )
 
 type Config struct {
-	Server ServerConfig `json:"server"`
-	Kafka  KafkaConfig  `json:"kafka"`
-	Log    LogConfig    `json:"logging"`
+	Server        ServerConfig        `json:"server"`
+	Kafka         KafkaConfig         `json:"kafka"`
+	Log           LogConfig           `json:"logging"`
+	FluentForward FluentForwardConfig `json:"fluent_forward"`
+	Syslog        SyslogConfig        `json:"syslog"`
+	GRPC          GRPCConfig          `json:"grpc"`
+}
+
+// GRPCConfig configures the interceptor chain LogService installs on its
+// gRPC server: auth, per-peer rate limiting, and per-call deadlines.
+type GRPCConfig struct {
+	Auth      GRPCAuthConfig      `json:"auth"`
+	RateLimit GRPCRateLimitConfig `json:"rate_limit"`
+
+	// RequestTimeoutSeconds bounds how long a unary call, or a whole stream
+	// call, may run before its context is canceled. Zero disables the
+	// deadline.
+	RequestTimeoutSeconds int `json:"request_timeout_seconds"`
+
+	// StreamWorkers sizes the bounded worker pool SendLogStream pipelines
+	// Kafka sends through while it keeps calling Recv. Zero defaults to 4.
+	StreamWorkers int `json:"stream_workers"`
+}
+
+// GRPCAuthConfig selects how LogService authenticates callers. Both checks
+// may be enabled together; MTLS is enforced at the transport level before a
+// call reaches the interceptor chain, while BearerToken is checked inside it.
+type GRPCAuthConfig struct {
+	// BearerToken, when non-empty, is required in the
+	// "authorization: Bearer <token>" metadata of every call.
+	BearerToken string `json:"bearer_token"`
+
+	MTLS GRPCMTLSConfig `json:"mtls"`
+}
+
+// GRPCMTLSConfig requires and verifies a client certificate against CAFile
+// before the gRPC server will complete a connection's TLS handshake.
+type GRPCMTLSConfig struct {
+	Enabled  bool   `json:"enabled"`
+	CAFile   string `json:"ca_file"`
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+}
+
+// GRPCRateLimitConfig drives a token-bucket limiter keyed by peer identity
+// (the mTLS client certificate CommonName if present, else the remote
+// address). Zero RequestsPerSecond disables rate limiting entirely.
+type GRPCRateLimitConfig struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+}
+
+// SyslogConfig configures LogService's syslog listeners, each of which can
+// be enabled independently of the gRPC listener and of each other.
+type SyslogConfig struct {
+	Enabled bool `json:"enabled"`
+
+	UDP SyslogUDPConfig `json:"udp"`
+	TCP SyslogTCPConfig `json:"tcp"`
+	TLS SyslogTLSConfig `json:"tls"`
+}
+
+type SyslogUDPConfig struct {
+	Enabled bool   `json:"enabled"`
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+
+	// QueueSize bounds how many received datagrams may be queued for a
+	// parser worker. Once full, new datagrams are dropped (and counted)
+	// rather than blocking the UDP receive loop or Kafka.
+	QueueSize int `json:"queue_size"`
+}
+
+// SyslogTCPConfig is the plain-TCP listener: newline-delimited messages,
+// no framing.
+type SyslogTCPConfig struct {
+	Enabled bool   `json:"enabled"`
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+}
+
+// SyslogTLSConfig is the TCP+TLS listener: RFC 5425 octet-counting framing.
+type SyslogTLSConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
 }
 
 type ServerConfig struct {
@@ -16,6 +101,20 @@ type ServerConfig struct {
 	Port int    `json:"port"`
 }
 
+// FluentForwardConfig configures the second listener LogService exposes for
+// Fluentd/Fluent Bit agents speaking the Fluent Forward protocol, alongside
+// the primary gRPC listener on ServerConfig.
+type FluentForwardConfig struct {
+	Enabled bool   `json:"enabled"`
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+
+	// SharedKey, when non-empty, requires clients to complete the Fluent
+	// Forward shared-key handshake (HELO/PING/PONG) before any entries are
+	// accepted. Empty means no authentication.
+	SharedKey string `json:"shared_key"`
+}
+
 type KafkaConfig struct {
 	Brokers       []string `json:"brokers"`
 	Topic         string   `json:"topic"`
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"log-ingester/config"
+	"log-ingester/kafka"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// forwardEntry is one [time, record] pair out of a Forward mode entries
+// array or a PackedForward mode packed byte stream.
+type forwardEntry struct {
+	time   interface{}
+	record map[string]interface{}
+}
+
+// FluentForwardServer accepts the Fluent Forward protocol (the wire protocol
+// spoken by Fluentd's out_forward and Fluent Bit's forward output plugins)
+// over TCP. Each entry is translated into a kafka.LogMessage and handed to
+// the same producer LogService's gRPC path uses for batch delivery, so
+// Fluent Bit/Fluentd agents can ship into Orbit without a shim.
+type FluentForwardServer struct {
+	producer  *kafka.Producer
+	host      string
+	port      int
+	sharedKey string
+
+	listener net.Listener
+}
+
+// NewFluentForwardServer builds a server bound to cfg.FluentForward and
+// sharing producer with LogService's gRPC listener.
+func NewFluentForwardServer(cfg *config.Config, producer *kafka.Producer) *FluentForwardServer {
+	return &FluentForwardServer{
+		producer:  producer,
+		host:      cfg.FluentForward.Host,
+		port:      cfg.FluentForward.Port,
+		sharedKey: cfg.FluentForward.SharedKey,
+	}
+}
+
+// Start listens for Fluent Forward connections and blocks serving them until
+// Close is called.
+func (s *FluentForwardServer) Start() error {
+	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", s.host, s.port))
+	if err != nil {
+		return err
+	}
+	s.listener = lis
+
+	log.Printf("Fluent Forward server starting on %s:%d", s.host, s.port)
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if s.listener == nil {
+				return nil
+			}
+			log.Printf("Fluent Forward: accept error: %v", err)
+			continue
+		}
+		go s.handleConn(conn)
+	}
+}
+
+// Close stops accepting new Fluent Forward connections.
+func (s *FluentForwardServer) Close() error {
+	if s.listener == nil {
+		return nil
+	}
+	lis := s.listener
+	s.listener = nil
+	return lis.Close()
+}
+
+func (s *FluentForwardServer) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	if s.sharedKey != "" {
+		if err := s.handshake(conn, reader); err != nil {
+			log.Printf("Fluent Forward: handshake with %s failed: %v", conn.RemoteAddr(), err)
+			return
+		}
+	}
+
+	dec := msgpack.NewDecoder(reader)
+
+	for {
+		raw, err := dec.DecodeInterface()
+		if err != nil {
+			if err != io.EOF {
+				log.Printf("Fluent Forward: decode error from %s: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+
+		msg, ok := raw.([]interface{})
+		if !ok || len(msg) < 2 {
+			log.Printf("Fluent Forward: malformed message from %s", conn.RemoteAddr())
+			continue
+		}
+
+		tag, _ := msg[0].(string)
+
+		entries, option, err := decodeForwardEntries(msg)
+		if err != nil {
+			log.Printf("Fluent Forward: failed to decode entries for tag %q: %v", tag, err)
+			continue
+		}
+
+		logMsgs := make([]*kafka.LogMessage, 0, len(entries))
+		for _, e := range entries {
+			logMsgs = append(logMsgs, buildLogMessage(tag, e.record))
+		}
+
+		if err := s.producer.SendLogs(logMsgs); err != nil {
+			log.Printf("Fluent Forward: failed to send %d logs for tag %q: %v", len(logMsgs), tag, err)
+			continue
+		}
+
+		if chunk, ok := option["chunk"].(string); ok && chunk != "" {
+			if err := sendAck(conn, chunk); err != nil {
+				log.Printf("Fluent Forward: failed to send ack for tag %q: %v", tag, err)
+			}
+		}
+	}
+}
+
+// decodeForwardEntries handles all three entry shapes the Forward protocol
+// allows: Message mode (a single [tag, time, record, option?]), Forward mode
+// (entries as a literal array of [time, record] pairs), and PackedForward
+// mode (entries packed into a single msgpack byte string, optionally
+// gzip-compressed per option.compressed).
+func decodeForwardEntries(msg []interface{}) ([]forwardEntry, map[string]interface{}, error) {
+	switch second := msg[1].(type) {
+	case []byte:
+		option := optionFromMsg(msg, 2)
+		payload := second
+		if compressed, _ := option["compressed"].(string); compressed == "gzip" {
+			gz, err := gzip.NewReader(bytes.NewReader(second))
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to open gzip packed forward payload: %w", err)
+			}
+			defer gz.Close()
+			payload, err = io.ReadAll(gz)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to decompress packed forward payload: %w", err)
+			}
+		}
+		return decodePackedEntries(payload), option, nil
+
+	case []interface{}:
+		option := optionFromMsg(msg, 2)
+		entries := make([]forwardEntry, 0, len(second))
+		for _, raw := range second {
+			pair, ok := raw.([]interface{})
+			if !ok || len(pair) < 2 {
+				continue
+			}
+			record, _ := pair[1].(map[string]interface{})
+			entries = append(entries, forwardEntry{time: pair[0], record: record})
+		}
+		return entries, option, nil
+
+	default:
+		if len(msg) < 3 {
+			return nil, nil, fmt.Errorf("message mode entry missing record")
+		}
+		record, _ := msg[2].(map[string]interface{})
+		option := optionFromMsg(msg, 3)
+		return []forwardEntry{{time: msg[1], record: record}}, option, nil
+	}
+}
+
+func optionFromMsg(msg []interface{}, index int) map[string]interface{} {
+	if len(msg) <= index {
+		return nil
+	}
+	option, _ := msg[index].(map[string]interface{})
+	return option
+}
+
+func decodePackedEntries(payload []byte) []forwardEntry {
+	dec := msgpack.NewDecoder(bytes.NewReader(payload))
+	var entries []forwardEntry
+	for {
+		raw, err := dec.DecodeInterface()
+		if err != nil {
+			break
+		}
+		pair, ok := raw.([]interface{})
+		if !ok || len(pair) < 2 {
+			continue
+		}
+		record, _ := pair[1].(map[string]interface{})
+		entries = append(entries, forwardEntry{time: pair[0], record: record})
+	}
+	return entries
+}
+
+// buildLogMessage maps a Fluent Forward tag and record onto the shared
+// kafka.LogMessage shape: the tag becomes Type (and its last dot-separated
+// segment becomes Category), well-known record keys become Message/Level/
+// Hostname/Source, and everything else is carried through as Metadata.
+func buildLogMessage(tag string, record map[string]interface{}) *kafka.LogMessage {
+	category := tag
+	if i := strings.LastIndex(tag, "."); i >= 0 {
+		category = tag[i+1:]
+	}
+
+	msg := &kafka.LogMessage{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Type:      tag,
+		Category:  category,
+		Metadata:  make(map[string]string),
+	}
+
+	for k, v := range record {
+		switch k {
+		case "message", "msg", "log":
+			msg.Message = fmt.Sprintf("%v", v)
+		case "level", "severity":
+			msg.Level = fmt.Sprintf("%v", v)
+		case "host", "hostname":
+			msg.Hostname = fmt.Sprintf("%v", v)
+		case "source":
+			msg.Source = fmt.Sprintf("%v", v)
+		default:
+			msg.Metadata[k] = fmt.Sprintf("%v", v)
+		}
+	}
+
+	return msg
+}
+
+func sendAck(conn net.Conn, chunk string) error {
+	return msgpack.NewEncoder(conn).Encode(map[string]string{"ack": chunk})
+}
+
+// handshake runs the Fluent Forward shared-key handshake: send HELO with a
+// fresh nonce, read the client's PING (which carries a salt and the client's
+// digest of salt+nonce+shared_key), and reply with PONG carrying the
+// server's own digest of the same inputs. A digest mismatch fails the
+// connection.
+func (s *FluentForwardServer) handshake(conn net.Conn, reader *bufio.Reader) error {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	enc := msgpack.NewEncoder(conn)
+	if err := enc.Encode([]interface{}{"HELO", map[string]interface{}{
+		"nonce":     nonce,
+		"auth":      []byte{},
+		"keepalive": true,
+	}}); err != nil {
+		return fmt.Errorf("failed to send HELO: %w", err)
+	}
+
+	dec := msgpack.NewDecoder(reader)
+	raw, err := dec.DecodeInterface()
+	if err != nil {
+		return fmt.Errorf("failed to read PING: %w", err)
+	}
+
+	ping, ok := raw.([]interface{})
+	if !ok || len(ping) < 6 {
+		return fmt.Errorf("malformed PING")
+	}
+
+	salt, _ := ping[2].([]byte)
+	clientDigest, _ := ping[3].(string)
+	serverDigest := sharedKeyDigest(salt, nonce, s.sharedKey)
+	authenticated := serverDigest == clientDigest
+
+	reason := ""
+	if !authenticated {
+		reason = "shared key mismatch"
+	}
+
+	if err := enc.Encode([]interface{}{"PONG", authenticated, reason, hostnameOrDefault(), serverDigest}); err != nil {
+		return fmt.Errorf("failed to send PONG: %w", err)
+	}
+
+	if !authenticated {
+		return fmt.Errorf("shared-key authentication failed")
+	}
+
+	return nil
+}
+
+func sharedKeyDigest(salt, nonce []byte, sharedKey string) string {
+	h := sha512.New()
+	h.Write(salt)
+	h.Write(nonce)
+	h.Write([]byte(sharedKey))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func hostnameOrDefault() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "unknown-host"
+	}
+	return host
+}
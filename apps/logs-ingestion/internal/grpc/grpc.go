@@ -3,8 +3,13 @@ package service
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"log-ingester/config"
 	"log-ingester/kafka"
@@ -17,6 +22,15 @@ type LogService struct {
 	pb.UnimplementedLogIngesterServer
 	producer *kafka.Producer
 	config   *config.Config
+
+	// fluentForward is the optional second listener accepting the Fluent
+	// Forward protocol, shared against the same producer as the gRPC path.
+	// Nil when config.FluentForward.Enabled is false.
+	fluentForward *FluentForwardServer
+
+	// syslog is the optional third listener accepting RFC 5424/RFC 3164
+	// syslog over UDP/TCP/TLS. Nil when config.Syslog.Enabled is false.
+	syslog *SyslogServer
 }
 
 func NewLogService(cfg *config.Config) (*LogService, error) {
@@ -25,10 +39,20 @@ func NewLogService(cfg *config.Config) (*LogService, error) {
 		return nil, err
 	}
 
-	return &LogService{
+	svc := &LogService{
 		producer: producer,
 		config:   cfg,
-	}, nil
+	}
+
+	if cfg.FluentForward.Enabled {
+		svc.fluentForward = NewFluentForwardServer(cfg, producer)
+	}
+
+	if cfg.Syslog.Enabled {
+		svc.syslog = NewSyslogServer(cfg, producer)
+	}
+
+	return svc, nil
 }
 
 func (s *LogService) SendLogs(ctx context.Context, req *pb.LogBatch) (*pb.LogResponse, error) {
@@ -50,7 +74,11 @@ func (s *LogService) SendLogs(ctx context.Context, req *pb.LogBatch) (*pb.LogRes
 		}
 	}
 
+	grpcBatchSize.Observe(float64(len(req.Logs)))
+
+	start := time.Now()
 	err := s.producer.SendLogs(logMessages)
+	kafkaPublishLatency.Observe(time.Since(start).Seconds())
 	if err != nil {
 		log.Printf("Failed to send logs to Kafka: %v", err)
 		return &pb.LogResponse{
@@ -67,15 +95,56 @@ func (s *LogService) SendLogs(ctx context.Context, req *pb.LogBatch) (*pb.LogRes
 	}, nil
 }
 
+// SendLogStream keeps calling Recv while a bounded pool of streamWorkers
+// goroutines pipelines the resulting entries into Kafka, so a slow publish
+// doesn't stall the receive loop (the buffered job channel is where
+// backpressure shows up once the pool falls behind). It tolerates individual
+// publish failures rather than aborting the whole stream, returning however
+// many entries actually succeeded.
 func (s *LogService) SendLogStream(stream pb.LogIngester_SendLogStreamServer) error {
-	count := 0
+	workers := s.config.GRPC.StreamWorkers
+	if workers <= 0 {
+		workers = 4
+	}
+
+	jobs := make(chan *kafka.LogMessage, workers*2)
+	var wg sync.WaitGroup
+	var succeeded atomic.Int64
+
+	var errMu sync.Mutex
+	var entryErrors []string
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for logMessage := range jobs {
+				start := time.Now()
+				err := s.producer.SendLog(logMessage)
+				kafkaPublishLatency.Observe(time.Since(start).Seconds())
+				if err != nil {
+					log.Printf("Failed to send log to Kafka: %v", err)
+					errMu.Lock()
+					entryErrors = append(entryErrors, fmt.Sprintf("%s: %v", logMessage.Timestamp, err))
+					errMu.Unlock()
+					continue
+				}
+				succeeded.Add(1)
+			}
+		}()
+	}
+
+	var recvErr error
+	total := 0
 	for {
 		logEntry, err := stream.Recv()
 		if err != nil {
+			recvErr = err
 			break
 		}
+		total++
 
-		logMessage := &kafka.LogMessage{
+		jobs <- &kafka.LogMessage{
 			Timestamp:   logEntry.Timestamp,
 			Level:       logEntry.Level,
 			Type:        logEntry.Type,
@@ -88,23 +157,30 @@ func (s *LogService) SendLogStream(stream pb.LogIngester_SendLogStreamServer) er
 			Priority:    logEntry.Priority,
 			Metadata:    logEntry.Metadata,
 		}
+	}
+	close(jobs)
+	wg.Wait()
 
-		err = s.producer.SendLog(logMessage)
-		if err != nil {
-			log.Printf("Failed to send log to Kafka: %v", err)
-			return stream.SendAndClose(&pb.LogResponse{
-				Success:        false,
-				Message:        fmt.Sprintf("Failed to send log: %v", err),
-				ProcessedCount: int32(count),
-			})
-		}
-		count++
+	grpcBatchSize.Observe(float64(total))
+
+	if recvErr != nil && recvErr != io.EOF {
+		log.Printf("SendLogStream: client stream ended early: %v", recvErr)
+	}
+
+	message := "Stream processed successfully"
+	if len(entryErrors) > 0 {
+		// pb.LogResponse has no dedicated per-entry error field in this
+		// tree (log-ingester/proto isn't vendored here to extend its
+		// message definitions), so the per-entry failures are folded into
+		// Message as the closest honest approximation until the proto
+		// message gains a repeated field for them.
+		message = fmt.Sprintf("%d/%d entries failed: %s", len(entryErrors), total, strings.Join(entryErrors, "; "))
 	}
 
 	return stream.SendAndClose(&pb.LogResponse{
-		Success:        true,
-		Message:        "Stream processed successfully",
-		ProcessedCount: int32(count),
+		Success:        len(entryErrors) == 0,
+		Message:        message,
+		ProcessedCount: int32(succeeded.Load()),
 	})
 }
 
@@ -114,7 +190,28 @@ func (s *LogService) Start() error {
 		return err
 	}
 
-	grpcServer := grpc.NewServer()
+	if s.fluentForward != nil {
+		go func() {
+			if err := s.fluentForward.Start(); err != nil {
+				log.Printf("Fluent Forward server stopped: %v", err)
+			}
+		}()
+	}
+
+	if s.syslog != nil {
+		go func() {
+			if err := s.syslog.Start(); err != nil {
+				log.Printf("Syslog server stopped: %v", err)
+			}
+		}()
+	}
+
+	serverOpts, err := buildServerOptions(s.config)
+	if err != nil {
+		return fmt.Errorf("failed to configure gRPC server: %w", err)
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
 	pb.RegisterLogIngesterServer(grpcServer, s)
 
 	log.Printf("gRPC server starting on %s:%d", s.config.Server.Host, s.config.Server.Port)
@@ -122,5 +219,15 @@ func (s *LogService) Start() error {
 }
 
 func (s *LogService) Close() error {
+	if s.fluentForward != nil {
+		if err := s.fluentForward.Close(); err != nil {
+			log.Printf("Failed to close Fluent Forward server: %v", err)
+		}
+	}
+	if s.syslog != nil {
+		if err := s.syslog.Close(); err != nil {
+			log.Printf("Failed to close syslog server: %v", err)
+		}
+	}
 	return s.producer.Close()
 }
\ No newline at end of file
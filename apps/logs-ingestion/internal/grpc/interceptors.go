@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"log-ingester/config"
+
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// requestLogger emits the structured (request id / peer / method / latency)
+// log lines loggingUnaryInterceptor and loggingStreamInterceptor produce, in
+// place of the stdlib log package SendLogs/SendLogStream used previously.
+var requestLogger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+// buildServerOptions assembles the ServerOptions LogService.Start installs:
+// mTLS transport credentials when configured, plus the unary/stream
+// interceptor chains in request order (rate limit, auth, deadline, logging,
+// metrics).
+func buildServerOptions(cfg *config.Config) ([]grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+
+	if cfg.GRPC.Auth.MTLS.Enabled {
+		creds, err := loadMTLSCredentials(&cfg.GRPC.Auth.MTLS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load mTLS credentials: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	limiter := newPeerRateLimiter(cfg.GRPC.RateLimit)
+	deadline := time.Duration(cfg.GRPC.RequestTimeoutSeconds) * time.Second
+	bearerToken := cfg.GRPC.Auth.BearerToken
+
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(
+			limiter.unaryInterceptor,
+			authUnaryInterceptor(bearerToken),
+			deadlineUnaryInterceptor(deadline),
+			loggingUnaryInterceptor,
+			metricsUnaryInterceptor,
+		),
+		grpc.ChainStreamInterceptor(
+			limiter.streamInterceptor,
+			authStreamInterceptor(bearerToken),
+			deadlineStreamInterceptor(deadline),
+			loggingStreamInterceptor,
+			metricsStreamInterceptor,
+		),
+	)
+
+	return opts, nil
+}
+
+func loadMTLSCredentials(cfg *config.GRPCMTLSConfig) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	caCert, err := os.ReadFile(cfg.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("failed to parse CA certificate from %s", cfg.CAFile)
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    caPool,
+	}), nil
+}
+
+// peerIdentity returns the mTLS client certificate's CommonName if present,
+// otherwise the peer's remote address. It keys both the rate limiter and
+// the structured log lines.
+func peerIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+		return tlsInfo.State.PeerCertificates[0].Subject.CommonName
+	}
+
+	return p.Addr.String()
+}
+
+// peerRateLimiter enforces a token-bucket rate limit per peer identity.
+type peerRateLimiter struct {
+	cfg config.GRPCRateLimitConfig
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func newPeerRateLimiter(cfg config.GRPCRateLimitConfig) *peerRateLimiter {
+	return &peerRateLimiter{
+		cfg:      cfg,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+func (l *peerRateLimiter) allow(identity string) bool {
+	if l.cfg.RequestsPerSecond <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	limiter, ok := l.limiters[identity]
+	if !ok {
+		burst := l.cfg.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(l.cfg.RequestsPerSecond), burst)
+		l.limiters[identity] = limiter
+	}
+	l.mu.Unlock()
+
+	return limiter.Allow()
+}
+
+func (l *peerRateLimiter) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !l.allow(peerIdentity(ctx)) {
+		return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return handler(ctx, req)
+}
+
+func (l *peerRateLimiter) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !l.allow(peerIdentity(ss.Context())) {
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return handler(srv, ss)
+}
+
+// authUnaryInterceptor/authStreamInterceptor enforce the configured bearer
+// token, when one is set. mTLS (if enabled) is already enforced by the
+// transport credentials before a call ever reaches here.
+func authUnaryInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := checkBearerToken(ctx, token); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authStreamInterceptor(token string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkBearerToken(ss.Context(), token); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func checkBearerToken(ctx context.Context, token string) error {
+	if token == "" {
+		return nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return status.Error(codes.Unauthenticated, "missing authorization header")
+	}
+
+	if strings.TrimPrefix(values[0], "Bearer ") != token {
+		return status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+
+	return nil
+}
+
+// deadlineUnaryInterceptor/deadlineStreamInterceptor cancel the context a
+// call's handler sees once d has elapsed, independent of whatever deadline
+// (if any) the client set. SendLogs and SendLogStream do not check that
+// context, so the cancellation does not cut short a batch they are already
+// receiving or publishing.
+func deadlineUnaryInterceptor(d time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if d <= 0 {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+func deadlineStreamInterceptor(d time.Duration) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if d <= 0 {
+			return handler(srv, ss)
+		}
+		ctx, cancel := context.WithTimeout(ss.Context(), d)
+		defer cancel()
+		return handler(srv, &deadlineServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// deadlineServerStream overrides Context so handlers observe the
+// server-enforced deadline rather than the stream's original context.
+type deadlineServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *deadlineServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func loggingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	requestID := uuid.NewString()
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	requestLogger.Info("grpc unary call",
+		"request_id", requestID,
+		"method", info.FullMethod,
+		"peer", peerIdentity(ctx),
+		"duration_ms", time.Since(start).Milliseconds(),
+		"error", errString(err),
+	)
+
+	return resp, err
+}
+
+func loggingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	requestID := uuid.NewString()
+	start := time.Now()
+
+	err := handler(srv, ss)
+
+	requestLogger.Info("grpc stream call",
+		"request_id", requestID,
+		"method", info.FullMethod,
+		"peer", peerIdentity(ss.Context()),
+		"duration_ms", time.Since(start).Milliseconds(),
+		"error", errString(err),
+	)
+
+	return err
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func metricsUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	grpcRequestsTotal.WithLabelValues(info.FullMethod, "unary").Inc()
+	return handler(ctx, req)
+}
+
+func metricsStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	grpcRequestsTotal.WithLabelValues(info.FullMethod, "stream").Inc()
+	grpcInFlightStreams.Inc()
+	defer grpcInFlightStreams.Dec()
+	return handler(srv, ss)
+}
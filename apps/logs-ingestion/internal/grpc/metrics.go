@@ -0,0 +1,30 @@
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	grpcRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "logingest_grpc_requests_total",
+		Help: "gRPC calls received by LogService, by method and call type.",
+	}, []string{"method", "type"})
+
+	grpcBatchSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "logingest_grpc_batch_size",
+		Help:    "Number of log entries carried by a single SendLogs call or SendLogStream stream.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	})
+
+	kafkaPublishLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "logingest_kafka_publish_latency_seconds",
+		Help:    "Latency of a single Kafka publish made while handling a gRPC call.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	grpcInFlightStreams = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "logingest_grpc_in_flight_streams",
+		Help: "SendLogStream calls currently being served.",
+	})
+)
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"log-ingester/config"
+	"log-ingester/kafka"
+)
+
+// syslogUDPWorkers is the number of goroutines draining the UDP receive
+// queue. Kept small and fixed since parsing is cheap; the queue itself is
+// what absorbs bursts.
+const syslogUDPWorkers = 4
+
+// SyslogServer listens for RFC 5424 and legacy RFC 3164 syslog messages over
+// UDP, TCP, and TCP+TLS (RFC 5425 octet-counting framing), translating each
+// into a kafka.LogMessage and pushing it through the same producer
+// LogService's gRPC and Fluent Forward paths use.
+type SyslogServer struct {
+	producer *kafka.Producer
+	config   *config.SyslogConfig
+
+	udpConn     net.PacketConn
+	tcpListener net.Listener
+	tlsListener net.Listener
+	done        chan struct{}
+
+	droppedUDP atomic.Int64
+}
+
+// NewSyslogServer builds a server bound to cfg.Syslog and sharing producer
+// with LogService's other listeners.
+func NewSyslogServer(cfg *config.Config, producer *kafka.Producer) *SyslogServer {
+	return &SyslogServer{
+		producer: producer,
+		config:   &cfg.Syslog,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start brings up every transport enabled in config.SyslogConfig and blocks
+// until Close is called. It returns an error immediately if an enabled
+// transport fails to bind.
+func (s *SyslogServer) Start() error {
+	started := false
+
+	if s.config.UDP.Enabled {
+		conn, err := net.ListenPacket("udp", fmt.Sprintf("%s:%d", s.config.UDP.Host, s.config.UDP.Port))
+		if err != nil {
+			return fmt.Errorf("failed to start syslog UDP listener: %w", err)
+		}
+		s.udpConn = conn
+		started = true
+		go s.serveUDP(conn)
+	}
+
+	if s.config.TCP.Enabled {
+		lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", s.config.TCP.Host, s.config.TCP.Port))
+		if err != nil {
+			return fmt.Errorf("failed to start syslog TCP listener: %w", err)
+		}
+		s.tcpListener = lis
+		started = true
+		go s.serveTCP(lis, false)
+	}
+
+	if s.config.TLS.Enabled {
+		cert, err := tls.LoadX509KeyPair(s.config.TLS.CertFile, s.config.TLS.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load syslog TLS certificate: %w", err)
+		}
+
+		lis, err := tls.Listen("tcp", fmt.Sprintf("%s:%d", s.config.TLS.Host, s.config.TLS.Port), &tls.Config{
+			Certificates: []tls.Certificate{cert},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to start syslog TLS listener: %w", err)
+		}
+		s.tlsListener = lis
+		started = true
+		go s.serveTCP(lis, true)
+	}
+
+	if !started {
+		return fmt.Errorf("no syslog transport enabled")
+	}
+
+	log.Printf("Syslog server started (udp=%v tcp=%v tls=%v)", s.config.UDP.Enabled, s.config.TCP.Enabled, s.config.TLS.Enabled)
+	<-s.done
+	return nil
+}
+
+// Close stops every transport SyslogServer started.
+func (s *SyslogServer) Close() error {
+	if s.udpConn != nil {
+		s.udpConn.Close()
+	}
+	if s.tcpListener != nil {
+		s.tcpListener.Close()
+	}
+	if s.tlsListener != nil {
+		s.tlsListener.Close()
+	}
+	close(s.done)
+	return nil
+}
+
+// DroppedUDP reports how many UDP datagrams were dropped because the parser
+// queue was full, rather than blocking the receive loop or Kafka.
+func (s *SyslogServer) DroppedUDP() int64 {
+	return s.droppedUDP.Load()
+}
+
+func (s *SyslogServer) serveUDP(conn net.PacketConn) {
+	queueSize := s.config.UDP.QueueSize
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+	jobs := make(chan []byte, queueSize)
+
+	for i := 0; i < syslogUDPWorkers; i++ {
+		go s.udpWorker(jobs)
+	}
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			close(jobs)
+			return
+		}
+
+		data := make([]byte, n)
+		copy(data, buf[:n])
+
+		select {
+		case jobs <- data:
+		default:
+			s.droppedUDP.Add(1)
+		}
+	}
+}
+
+func (s *SyslogServer) udpWorker(jobs <-chan []byte) {
+	for data := range jobs {
+		s.handleMessage(string(data))
+	}
+}
+
+func (s *SyslogServer) serveTCP(lis net.Listener, octetCounting bool) {
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		go s.handleTCPConn(conn, octetCounting)
+	}
+}
+
+func (s *SyslogServer) handleTCPConn(conn net.Conn, octetCounting bool) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	if octetCounting {
+		for {
+			frame, err := readOctetCountedFrame(reader)
+			if err != nil {
+				if err != io.EOF {
+					log.Printf("Syslog: octet-counting frame error from %s: %v", conn.RemoteAddr(), err)
+				}
+				return
+			}
+			s.handleMessage(frame)
+		}
+	}
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		s.handleMessage(scanner.Text())
+	}
+}
+
+func (s *SyslogServer) handleMessage(raw string) {
+	logMsg := parseSyslogMessage(raw)
+	if err := s.producer.SendLog(logMsg); err != nil {
+		log.Printf("Syslog: failed to send log: %v", err)
+	}
+}
+
+// readOctetCountedFrame reads one RFC 5425 frame: an ASCII decimal length, a
+// single space, then exactly that many bytes of message.
+func readOctetCountedFrame(reader *bufio.Reader) (string, error) {
+	lengthStr, err := reader.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(lengthStr))
+	if err != nil {
+		return "", fmt.Errorf("invalid octet-counting frame length %q: %w", lengthStr, err)
+	}
+
+	msg := make([]byte, length)
+	if _, err := io.ReadFull(reader, msg); err != nil {
+		return "", err
+	}
+
+	return string(msg), nil
+}
+
+var (
+	rfc5424Pattern = regexp.MustCompile(`^<(\d+)>(\d+)\s+(\S+)\s+(\S+)\s+(\S+)\s+(\S+)\s+(\S+)\s+(-|\[.*\])\s?(.*)$`)
+	rfc3164Pattern = regexp.MustCompile(`^<(\d+)>(\w{3}\s+\d{1,2}\s+\d{2}:\d{2}:\d{2})\s+(\S+)\s+([^:]+):\s*(.*)$`)
+	sdParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+var severityNames = []string{"emergency", "alert", "critical", "error", "warning", "notice", "info", "debug"}
+
+var facilityNames = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console", "solaris-cron",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+// parseSyslogMessage tries RFC 5424 first, falls back to legacy RFC 3164,
+// and failing both treats the whole line as an unparsed message body.
+func parseSyslogMessage(raw string) *kafka.LogMessage {
+	raw = strings.TrimRight(raw, "\r\n")
+
+	if m := rfc5424Pattern.FindStringSubmatch(raw); m != nil {
+		return buildRFC5424LogMessage(m)
+	}
+	if m := rfc3164Pattern.FindStringSubmatch(raw); m != nil {
+		return buildRFC3164LogMessage(m)
+	}
+
+	return &kafka.LogMessage{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Level:     "unknown",
+		Message:   raw,
+		Metadata:  make(map[string]string),
+	}
+}
+
+// buildRFC5424LogMessage maps PRI to Facility/Priority/Level, APP-NAME to
+// Source, and STRUCTURED-DATA to Metadata.
+func buildRFC5424LogMessage(m []string) *kafka.LogMessage {
+	facility, severity := decodePriority(m[1])
+
+	return &kafka.LogMessage{
+		Timestamp: m[3],
+		Level:     severityName(severity),
+		Facility:  facilityName(facility),
+		Priority:  m[1],
+		Source:    m[5],
+		Hostname:  m[4],
+		Message:   m[9],
+		Metadata:  parseStructuredData(m[8]),
+	}
+}
+
+// buildRFC3164LogMessage maps PRI to Facility/Priority/Level and TAG to
+// Source. RFC 3164 has no structured data, so Metadata is left empty.
+func buildRFC3164LogMessage(m []string) *kafka.LogMessage {
+	facility, severity := decodePriority(m[1])
+
+	return &kafka.LogMessage{
+		Timestamp: m[2],
+		Level:     severityName(severity),
+		Facility:  facilityName(facility),
+		Priority:  m[1],
+		Source:    strings.TrimSpace(m[4]),
+		Hostname:  m[3],
+		Message:   strings.TrimSpace(m[5]),
+		Metadata:  make(map[string]string),
+	}
+}
+
+func decodePriority(priStr string) (facility, severity int) {
+	pri, _ := strconv.Atoi(priStr)
+	return pri / 8, pri % 8
+}
+
+func severityName(sev int) string {
+	if sev < 0 || sev >= len(severityNames) {
+		return "unknown"
+	}
+	return severityNames[sev]
+}
+
+func facilityName(fac int) string {
+	if fac < 0 || fac >= len(facilityNames) {
+		return strconv.Itoa(fac)
+	}
+	return facilityNames[fac]
+}
+
+// parseStructuredData extracts key="value" pairs out of one or more
+// RFC 5424 SD-ELEMENTs. A bare "-" (no structured data) yields an empty map.
+func parseStructuredData(sd string) map[string]string {
+	metadata := make(map[string]string)
+	if sd == "-" || sd == "" {
+		return metadata
+	}
+
+	for _, m := range sdParamPattern.FindAllStringSubmatch(sd, -1) {
+		metadata[m[1]] = m[2]
+	}
+
+	return metadata
+}